@@ -151,6 +151,30 @@ func PublishAPI(serviceBody apic.ServiceBody) error {
 	return nil
 }
 
+// PublishAPIs - Publishes the APIs with a pool of workers, returning the result of each API in the same order.
+// The access request definition of each API is published before the API, see apic.ServiceClient.PublishServices for the options.
+func PublishAPIs(serviceBodies []*apic.ServiceBody, opts ...apic.PublishOption) ([]apic.PublishResult, error) {
+	if !agent.publishingLockAcquired.Load() {
+		PublishingLock()
+		defer PublishingUnlock()
+	}
+	if agent.apicClient == nil {
+		return nil, errors.New("apic client is not initialized")
+	}
+
+	opts = append([]apic.PublishOption{apic.WithBeforePublish(func(serviceBody *apic.ServiceBody) error {
+		_, err := publishAccessRequestDefinition(serviceBody)
+		return err
+	})}, opts...)
+	results := agent.apicClient.PublishServices(serviceBodies, opts...)
+	for _, result := range results {
+		if result.Err == nil && !result.Duplicate {
+			log.Infof("Published API %v-%v in environment %v", result.ServiceBody.APIName, result.ServiceBody.Version, agent.cfg.GetEnvironmentName())
+		}
+	}
+	return results, nil
+}
+
 // RemovePublishedAPIAgentDetail -
 func RemovePublishedAPIAgentDetail(externalAPIID, detailKey string) error {
 	apiSvc := agent.cacheManager.GetAPIServiceWithAPIID(externalAPIID)
@@ -10,7 +10,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Axway/agent-sdk/pkg/apic"
 	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
 	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
 	"github.com/stretchr/testify/assert"
@@ -365,7 +364,7 @@ func TestJWTAuth(t *testing.T) {
 	jAuthStruct, ok := c.auth.(*jwtAuth)
 
 	assert.True(t, ok)
-	jAuthStruct.tokenGetter = apic.MockTokenGetter
+	jAuthStruct.tokenGetter = testTokenGetter{}
 
 	req := &http.Request{
 		Header: make(http.Header),
@@ -741,3 +740,12 @@ func TestUpdateMerge(t *testing.T) {
 		})
 	}
 }
+
+// testTokenGetter - returns a fake token
+type testTokenGetter struct{}
+
+func (testTokenGetter) GetToken() (string, error) {
+	return "testToken", nil
+}
+
+func (testTokenGetter) Close() error { return nil }
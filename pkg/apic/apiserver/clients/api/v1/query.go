@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
 )
@@ -15,7 +16,7 @@ type QueryStringer struct {
 
 func (qs QueryStringer) String() string {
 	v := rsqlVisitor{strings.Builder{}}
-	v.Visit(qs)
+	v.Visit(qs.QueryNode)
 
 	return v.String()
 }
@@ -67,6 +68,24 @@ func (r *referenceNode) Accept(v Visitor) {
 	v.Visit(r)
 }
 
+type fieldNode struct {
+	field  string
+	values []string
+}
+
+func (n *fieldNode) Accept(v Visitor) {
+	v.Visit(n)
+}
+
+type timeRangeNode struct {
+	field    string
+	from, to time.Time
+}
+
+func (n *timeRangeNode) Accept(v Visitor) {
+	v.Visit(n)
+}
+
 // AttrIn creates a query that matches resources with attribute key and  any of values
 func AttrIn(key string, values ...string) QueryNode {
 	return &attrNode{
@@ -130,6 +149,40 @@ func Names(names ...string) QueryNode {
 	return namesNode(names)
 }
 
+// FieldIn creates a query that matches resources with the field, a dot separated path like metadata.scope.name, set
+// to any of values
+func FieldIn(field string, values ...string) QueryNode {
+	return &fieldNode{
+		field,
+		values,
+	}
+}
+
+// OwnerIn creates a query that matches resources owned by any of the team or user ids
+func OwnerIn(ids ...string) QueryNode {
+	return FieldIn("owner.id", ids...)
+}
+
+// TimeRange creates a query that matches resources with the time field from, inclusive, to, exclusive. A zero from or
+// to leaves the range open on that side.
+func TimeRange(field string, from, to time.Time) QueryNode {
+	return &timeRangeNode{
+		field: field,
+		from:  from,
+		to:    to,
+	}
+}
+
+// CreatedBetween creates a query that matches resources created from, inclusive, to, exclusive
+func CreatedBetween(from, to time.Time) QueryNode {
+	return TimeRange("metadata.audit.createTimestamp", from, to)
+}
+
+// ModifiedBetween creates a query that matches resources last modified from, inclusive, to, exclusive
+func ModifiedBetween(from, to time.Time) QueryNode {
+	return TimeRange("metadata.audit.modifyTimestamp", from, to)
+}
+
 // Or creates a query that ors two or more subqueries
 func Or(first, second QueryNode, rest ...QueryNode) QueryNode {
 	nodes := make([]QueryNode, len(rest)+2)
@@ -222,6 +275,28 @@ func (rv *rsqlVisitor) Visit(node QueryNode) {
 		default:
 			rv.b.WriteString(fmt.Sprintf(`attributes.%s=in=("%s")`, n.key, strings.Join(n.values, `","`)))
 		}
+	case *fieldNode:
+		switch len(n.values) {
+		case 0:
+			rv.b.WriteString(fmt.Sprintf(`%s==""`, n.field))
+		case 1:
+			rv.b.WriteString(fmt.Sprintf(`%s=="%s"`, n.field, n.values[0]))
+		default:
+			rv.b.WriteString(fmt.Sprintf(`%s=in=("%s")`, n.field, strings.Join(n.values, `","`)))
+		}
+	case *timeRangeNode:
+		bounds := []string{}
+		if !n.from.IsZero() {
+			bounds = append(bounds, fmt.Sprintf(`%s>="%s"`, n.field, n.from.UTC().Format(apiv1.APIServerTimeFormat)))
+		}
+		if !n.to.IsZero() {
+			bounds = append(bounds, fmt.Sprintf(`%s<"%s"`, n.field, n.to.UTC().Format(apiv1.APIServerTimeFormat)))
+		}
+		if len(bounds) > 1 {
+			rv.b.WriteString("(" + strings.Join(bounds, ";") + ")")
+		} else {
+			rv.b.WriteString(strings.Join(bounds, ""))
+		}
 	case *referenceNode:
 		rv.b.WriteString(fmt.Sprintf(`metadata.references.name==%s;metadata.references.kind==%s`, n.name, n.gvk.GroupKind.Kind))
 	default:
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
 )
@@ -62,6 +63,26 @@ func TestRSQL(t *testing.T) {
 			Or(AttrIn("a", "v1", "v2"), Reference(management.APIServiceGVK(), "my-rd-svc")),
 			`(attributes.a=in=("v1","v2"),metadata.references.name==my-rd-svc;metadata.references.kind==APIService)`,
 		},
+		{
+			"one field, one value",
+			FieldIn("metadata.scope.name", "env"),
+			`metadata.scope.name=="env"`,
+		},
+		{
+			"owners",
+			OwnerIn("t1", "t2"),
+			`owner.id=in=("t1","t2")`,
+		},
+		{
+			"names anded with a creation time range",
+			And(Names("n1", "n2"), CreatedBetween(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))),
+			`(name=in=("n1","n2");(metadata.audit.createTimestamp>="2024-01-02T03:04:05.000+0000";metadata.audit.createTimestamp<"2024-02-01T00:00:00.000+0000"))`,
+		},
+		{
+			"modified since",
+			ModifiedBetween(time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)), time.Time{}),
+			`metadata.audit.modifyTimestamp>="2024-01-02T02:04:05.000+0000"`,
+		},
 	}
 
 	for i := range testCases {
@@ -75,3 +96,10 @@ func TestRSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryStringer(t *testing.T) {
+	qs := QueryStringer{And(Names("n1"), TagsIn("t1"))}
+	if qs.String() != `(name=="n1";tags=="t1")` {
+		t.Errorf("got: %s", qs.String())
+	}
+}
@@ -67,6 +67,10 @@ func (c *ServiceClient) setMigrationFlags(svcDetails map[string]interface{}) {
 }
 
 func (c *ServiceClient) getOwnerObject(serviceBody *ServiceBody, warning bool) (*apiv1.Owner, error) {
+	// the offline client of the exporter has no team cache, the exported resources have no owner
+	if c.caches == nil {
+		return nil, nil
+	}
 	if id, found := c.getTeamFromCache(serviceBody.TeamName); found {
 		return &apiv1.Owner{
 			Type: apiv1.TeamOwner,
@@ -491,9 +491,10 @@ func TestPublishServiceError(t *testing.T) {
 
 func TestProcessRevision(t *testing.T) {
 	tests := map[string]struct {
-		httpResponses   []api.MockResponse
-		serviceBody     ServiceBody
-		expectedRevName string
+		httpResponses    []api.MockResponse
+		serviceBody      ServiceBody
+		expectedRevName  string
+		expectedSeverity string
 	}{
 		"publish new revision": {
 			httpResponses: []api.MockResponse{
@@ -635,6 +636,45 @@ func TestProcessRevision(t *testing.T) {
 			},
 			expectedRevName: testRevisionNameAlt,
 		},
+		"compute the update severity from the previous revision": {
+			httpResponses: []api.MockResponse{
+				emptyRevisionListResponse, // GET revisions (getExistingRevision, by hash - no match)
+				{
+					FileName:    testRevisionListFile, // GET revisions of the service, to find the previous revision
+					RespCode:    http.StatusOK,
+					RespHeaders: map[string][]string{"X-Axway-Total-Count": {"1"}},
+				},
+				{
+					FileName: testRevisionFile, // GET the previous revision, with its spec
+					RespCode: http.StatusOK,
+				},
+				{
+					FileName: testRevisionFile, // POST serviceRevision
+					RespCode: http.StatusCreated,
+				},
+				{
+					FileName: testAgentDetailsFile, // revision information subresource
+					RespCode: http.StatusOK,
+				},
+				{
+					FileName: testAgentDetailsFile, // revision x-agent-details subresource
+					RespCode: http.StatusOK,
+				},
+			},
+			serviceBody: ServiceBody{
+				APIName:                  "daleapi",
+				ResourceType:             Oas2,
+				RestAPIID:                "12345",
+				SpecDefinition:           []byte(`{"swagger": "2.0", "info": {"title": "DaleAPI", "version": "1.1"}, "host": "apiv7:8065", "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}}`),
+				specHash:                 "def456",
+				computeAPIUpdateSeverity: true,
+				serviceContext: serviceContext{
+					serviceAction: updateAPI,
+				},
+			},
+			expectedRevName:  "daleapi",
+			expectedSeverity: APIUpdateSeverityMajor,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -644,6 +684,10 @@ func TestProcessRevision(t *testing.T) {
 			client.processRevision(&tc.serviceBody)
 			assert.NotEmpty(t, tc.serviceBody.serviceContext.revisionName)
 			assert.Equal(t, tc.expectedRevName, tc.serviceBody.serviceContext.revisionName)
+			assert.Equal(t, tc.expectedSeverity, tc.serviceBody.APIUpdateSeverity)
+			if tc.expectedSeverity != "" {
+				assert.Equal(t, "daleapi", tc.serviceBody.GetSpecDiff().PreviousRevision)
+			}
 		})
 	}
 }
@@ -39,7 +39,10 @@ func (c *ServiceClient) checkCredentialRequestDefinitions(serviceBody *ServiceBo
 	// remove any crd not in the cache
 	knownCRDs := make([]string, 0)
 	// Check if request definitions are allowed. False would indicate the service is Unpublished
-	if serviceBody.requestDefinitionsAllowed {
+	if serviceBody.requestDefinitionsAllowed && c.caches == nil {
+		// the offline client of the exporter can not check the definitions
+		knownCRDs = append(knownCRDs, crds...)
+	} else if serviceBody.requestDefinitionsAllowed {
 		for _, crd := range crds {
 			if def, err := c.caches.GetCredentialRequestDefinitionByName(crd); err == nil && def != nil {
 				knownCRDs = append(knownCRDs, crd)
@@ -59,6 +62,9 @@ func (c *ServiceClient) checkAccessRequestDefinition(serviceBody *ServiceBody) {
 
 	// Check if request definitions are allowed. False would indicate the service is Unpublished
 	if serviceBody.requestDefinitionsAllowed {
+		if c.caches == nil {
+			return
+		}
 		if def, err := c.caches.GetAccessRequestDefinitionByName(ard); err == nil && def != nil {
 			return
 		}
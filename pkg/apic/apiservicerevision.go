@@ -60,6 +60,9 @@ func (c *ServiceClient) buildAPIServiceRevision(serviceBody *ServiceBody) *manag
 	newRev.Owner, _ = c.getOwnerObject(serviceBody, false)
 
 	revDetails := util.MergeMapStringInterface(serviceBody.ServiceAgentDetails, serviceBody.RevisionAgentDetails)
+	if serviceBody.specDiff != nil {
+		revDetails[specChangelogDetailKey] = serviceBody.specDiff.toDetails()
+	}
 	agentDetails := buildAgentDetailsSubResource(serviceBody, false, revDetails)
 	util.SetAgentDetails(newRev, agentDetails)
 
@@ -80,6 +83,11 @@ func (c *ServiceClient) processRevision(serviceBody *ServiceBody) error {
 		return err
 	}
 
+	// a new revision is created for the updated service, compare its spec with the previous revision
+	if existingRevision == nil && serviceBody.serviceContext.serviceAction == updateAPI && serviceBody.computeAPIUpdateSeverity {
+		c.diffPreviousRevision(serviceBody)
+	}
+
 	log.
 		WithField("action", logProcess).
 		WithField("service", serviceBody.APIName).
@@ -90,6 +98,44 @@ func (c *ServiceClient) processRevision(serviceBody *ServiceBody) error {
 	return c.createOrUpdateRevision(serviceBody, existingRevision)
 }
 
+// diffPreviousRevision - computes the changes from the spec of the latest revision of the service, and the
+// api update severity when it was not set by the agent
+func (c *ServiceClient) diffPreviousRevision(serviceBody *ServiceBody) {
+	logger := c.logger.WithField("service", serviceBody.APIName)
+	revisions, totalCount, err := c.getRevisions(fmt.Sprintf("metadata.references.id==%s", serviceBody.serviceContext.serviceID))
+	if err != nil || totalCount == 0 || len(revisions) == 0 {
+		logger.WithError(err).Debug("no previous revision found to compare the spec with")
+		return
+	}
+
+	previousRevision, err := c.GetAPIRevisionByName(revisions[0].Name)
+	if err != nil || previousRevision == nil {
+		logger.WithError(err).WithField("revision", revisions[0].Name).Warn("could not get the previous revision to compare the spec with")
+		return
+	}
+	previousSpec, err := base64.StdEncoding.DecodeString(previousRevision.Spec.Definition.Value)
+	if err != nil {
+		logger.WithError(err).WithField("revision", previousRevision.Name).Warn("could not decode the spec of the previous revision")
+		return
+	}
+
+	diff, err := DiffSpecs(previousSpec, serviceBody.SpecDefinition)
+	if err != nil || diff == nil {
+		logger.WithError(err).Debug("the spec changes were not computed")
+		return
+	}
+	diff.PreviousRevision = previousRevision.Name
+	serviceBody.specDiff = diff
+	if serviceBody.APIUpdateSeverity == "" {
+		serviceBody.APIUpdateSeverity = diff.Severity
+	}
+	logger.
+		WithField("previousRevision", previousRevision.Name).
+		WithField("severity", diff.Severity).
+		WithField("changes", len(diff.Changes)).
+		Debug("computed the spec changes from the previous revision")
+}
+
 // createOrUpdateRevision encapsulates CreateOrUpdateResource and setting up information subresource with spec hash
 func (c *ServiceClient) createOrUpdateRevision(serviceBody *ServiceBody, existingRevision *management.APIServiceRevision) error {
 	revision := c.buildAPIServiceRevision(serviceBody)
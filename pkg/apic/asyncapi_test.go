@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 type asyncServer struct {
@@ -383,3 +384,109 @@ func TestAsyncAPIGenerator(t *testing.T) {
 		})
 	}
 }
+
+func TestAsyncAPIGeneratorV3(t *testing.T) {
+	builder := CreateAsyncAPIBuilder(WithAsyncAPIV3())
+	builder.AddServer("prod", "production broker", "SASL_SSL://broker.example.com:9092/kafka",
+		WithSaslScramSecurity("SCRAM-SHA-512", "scram"),
+		WithProtocol("kafka", "3.5.0"))
+	builder.AddComponentMessage("order", "application/vnd.aai.asyncapi+json;version=3.0.0", "application/json",
+		map[string]interface{}{"type": "object"})
+	builder.AddChannel("orders", "order events", WithKafkaPublishOperationBinding(true, false), WithKafkaSubscribeOperationBinding(false, true))
+	builder.SetPublishMessageRef("orders", "order")
+	builder.SetSubscribeMessageRef("orders", "order")
+
+	spec, err := builder.Build("kafka://orders", "orders", "order api", "1.2.0")
+	assert.Nil(t, err)
+	assert.NotNil(t, spec)
+
+	// endpoints of the built spec are unchanged
+	endpoints, err := spec.GetEndpoints()
+	assert.Nil(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "broker.example.com", endpoints[0].Host)
+	assert.Equal(t, int32(9092), endpoints[0].Port)
+	assert.Equal(t, "SASL_SSL", endpoints[0].Protocol)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(spec.GetSpecBytes(), &doc))
+	assert.Equal(t, "3.0.0", doc["asyncapi"])
+	assert.Equal(t, map[string]interface{}{
+		"host":            "broker.example.com:9092",
+		"pathname":        "/kafka",
+		"protocol":        "kafka",
+		"protocolVersion": "3.5.0",
+		"description":     "production broker",
+		"security":        []interface{}{map[string]interface{}{"$ref": "#/components/securitySchemes/saslScramCreds"}},
+	}, doc["servers"].(map[string]interface{})["prod"])
+
+	channel := doc["channels"].(map[string]interface{})["orders"].(map[string]interface{})
+	assert.Equal(t, "orders", channel["address"])
+	assert.Contains(t, channel["messages"], "order")
+
+	operations := doc["operations"].(map[string]interface{})
+	assert.Len(t, operations, 2)
+	receive := operations["orders.receive"].(map[string]interface{})
+	assert.Equal(t, "receive", receive["action"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/channels/orders"}, receive["channel"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"$ref": "#/channels/orders/messages/order"}}, receive["messages"])
+	assert.Contains(t, receive["bindings"].(map[string]interface{})["kafka"], "groupId")
+	send := operations["orders.send"].(map[string]interface{})
+	assert.Equal(t, "send", send["action"])
+	assert.Contains(t, send["bindings"].(map[string]interface{})["kafka"], "clientId")
+
+	components := doc["components"].(map[string]interface{})
+	message := components["messages"].(map[string]interface{})["order"].(map[string]interface{})
+	assert.Equal(t, "application/vnd.aai.asyncapi+json;version=3.0.0", message["payload"].(map[string]interface{})["schemaFormat"])
+	assert.Equal(t, "scramSha512", components["securitySchemes"].(map[string]interface{})["saslScramCreds"].(map[string]interface{})["type"])
+
+	// the built spec is parsed as an asyncapi 3.0 spec
+	specParser := NewSpecResourceParser(spec.GetSpecBytes(), "")
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, AsyncAPI, specParser.GetSpecProcessor().GetResourceType())
+	assert.Equal(t, "1.2.0", specParser.GetSpecProcessor().GetVersion())
+	parsedEndpoints, err := specParser.GetSpecProcessor().GetEndpoints()
+	assert.Nil(t, err)
+	assert.Equal(t, "broker.example.com", parsedEndpoints[0].Host)
+	assert.Equal(t, int32(9092), parsedEndpoints[0].Port)
+	assert.Equal(t, "kafka", parsedEndpoints[0].Protocol)
+	assert.Equal(t, "/kafka", parsedEndpoints[0].BasePath)
+}
+
+func TestAsyncAPIGeneratorV3ChannelKeys(t *testing.T) {
+	builder := CreateAsyncAPIBuilder(WithAsyncAPIV3())
+	builder.AddServer("prod", "production broker", "kafka://broker.example.com:9092", WithProtocol("kafka", "3.5.0"))
+	builder.AddComponentMessage("order", "", "application/json", map[string]interface{}{"type": "object"})
+	for _, topic := range []string{"orders/created/{id}", "orders_created_id", "orders/created/id"} {
+		builder.AddChannel(topic, "order events", WithKafkaSubscribeOperationBinding(false, true))
+		builder.SetSubscribeMessageRef(topic, "order")
+	}
+
+	spec, err := builder.Build("kafka://orders", "orders", "order api", "1.0.0")
+	assert.Nil(t, err)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(spec.GetSpecBytes(), &doc))
+
+	// the keys only use the allowed characters, the topics are kept in the addresses
+	channels := doc["channels"].(map[string]interface{})
+	addresses := map[string]interface{}{}
+	for key, channel := range channels {
+		assert.Regexp(t, `^[a-zA-Z0-9\.\-_]+$`, key)
+		addresses[key] = channel.(map[string]interface{})["address"]
+	}
+	assert.Equal(t, map[string]interface{}{
+		"orders_created_id":   "orders_created_id",
+		"orders_created_id_2": "orders/created/id",
+		"orders_created_id_3": "orders/created/{id}",
+	}, addresses)
+
+	operations := doc["operations"].(map[string]interface{})
+	send := operations["orders_created_id_3.send"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/channels/orders_created_id_3"}, send["channel"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"$ref": "#/channels/orders_created_id_3/messages/order"}}, send["messages"])
+
+	specParser := NewSpecResourceParser(spec.GetSpecBytes(), "")
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, AsyncAPI, specParser.GetSpecProcessor().GetResourceType())
+}
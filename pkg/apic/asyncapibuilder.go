@@ -122,7 +122,17 @@ type AsyncAPIBuilder interface {
 	Build(id, title, description, version string) (AsyncSpecProcessor, error)
 }
 
+type AsyncAPIBuilderOpts func(*asyncAPIBuilder)
+
+// WithAsyncAPIV3 - builds the spec as an AsyncAPI 3.0 document, with separate channels and send/receive operations
+func WithAsyncAPIV3() AsyncAPIBuilderOpts {
+	return func(b *asyncAPIBuilder) {
+		b.useV3 = true
+	}
+}
+
 type asyncAPIBuilder struct {
+	useV3                      bool
 	servers                    map[string]spec.Server
 	channels                   map[string]spec.ChannelItem
 	channelPublishMessageRef   map[string]string
@@ -131,8 +141,8 @@ type asyncAPIBuilder struct {
 	securitySchemas            map[string]*spec.SecurityScheme
 }
 
-func CreateAsyncAPIBuilder() AsyncAPIBuilder {
-	return &asyncAPIBuilder{
+func CreateAsyncAPIBuilder(opts ...AsyncAPIBuilderOpts) AsyncAPIBuilder {
+	b := &asyncAPIBuilder{
 		servers:                    make(map[string]spec.Server),
 		channels:                   make(map[string]spec.ChannelItem),
 		channelPublishMessageRef:   make(map[string]string),
@@ -140,6 +150,10 @@ func CreateAsyncAPIBuilder() AsyncAPIBuilder {
 		componentMessages:          make(map[string]spec.MessageEntity),
 		securitySchemas:            make(map[string]*spec.SecurityScheme),
 	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
 }
 
 func (b *asyncAPIBuilder) AddServer(name, description, url string, opts ...AsyncAPIServerOpts) AsyncAPIBuilder {
@@ -340,7 +354,12 @@ func (b *asyncAPIBuilder) Build(id, title, description, version string) (AsyncSp
 	b.buildChannels(api)
 	b.buildComponents(api)
 
-	raw, err := api.MarshalYAML()
+	var raw []byte
+	if b.useV3 {
+		raw, err = b.buildV3(api)
+	} else {
+		raw, err = api.MarshalYAML()
+	}
 	if err != nil {
 		return nil, err
 	}
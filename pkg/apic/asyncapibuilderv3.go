@@ -0,0 +1,232 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/go-asyncapi/spec-2.4.0"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	asyncAPIV3Version             = "3.0.0"
+	asyncAPIV3ActionSend          = "send"
+	asyncAPIV3ActionReceive       = "receive"
+	channelRefTemplate            = "#/channels/%s"
+	channelMessageRefTemplate     = "#/channels/%s/messages/%s"
+	componentSecuritySchemeRefTpl = "#/components/securitySchemes/%s"
+)
+
+// asyncAPIV3InvalidKeyChars - the characters not allowed in the keys of the channels and operations
+var asyncAPIV3InvalidKeyChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+type asyncAPIV3Ref struct {
+	Ref string `yaml:"$ref"`
+}
+
+type asyncAPIV3Info struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type asyncAPIV3Server struct {
+	Host            string          `yaml:"host"`
+	Protocol        string          `yaml:"protocol"`
+	ProtocolVersion string          `yaml:"protocolVersion,omitempty"`
+	Pathname        string          `yaml:"pathname,omitempty"`
+	Description     string          `yaml:"description,omitempty"`
+	Security        []asyncAPIV3Ref `yaml:"security,omitempty"`
+}
+
+type asyncAPIV3Channel struct {
+	Address     string                   `yaml:"address"`
+	Description string                   `yaml:"description,omitempty"`
+	Messages    map[string]asyncAPIV3Ref `yaml:"messages,omitempty"`
+}
+
+type asyncAPIV3Operation struct {
+	Action   string                 `yaml:"action"`
+	Channel  asyncAPIV3Ref          `yaml:"channel"`
+	Messages []asyncAPIV3Ref        `yaml:"messages,omitempty"`
+	Bindings map[string]interface{} `yaml:"bindings,omitempty"`
+}
+
+type asyncAPIV3Message struct {
+	ContentType string      `yaml:"contentType,omitempty"`
+	Payload     interface{} `yaml:"payload"`
+}
+
+type asyncAPIV3Components struct {
+	Messages        map[string]asyncAPIV3Message `yaml:"messages,omitempty"`
+	SecuritySchemes map[string]interface{}       `yaml:"securitySchemes,omitempty"`
+}
+
+type asyncAPIV3 struct {
+	AsyncAPI   string                         `yaml:"asyncapi"`
+	ID         string                         `yaml:"id,omitempty"`
+	Info       asyncAPIV3Info                 `yaml:"info"`
+	Servers    map[string]asyncAPIV3Server    `yaml:"servers,omitempty"`
+	Channels   map[string]asyncAPIV3Channel   `yaml:"channels,omitempty"`
+	Operations map[string]asyncAPIV3Operation `yaml:"operations,omitempty"`
+	Components asyncAPIV3Components           `yaml:"components,omitempty"`
+}
+
+// buildV3 - converts the builder content to an AsyncAPI 3.0 document, a 2.x publish operation
+// is a receive action of the application and a subscribe operation is a send action
+func (b *asyncAPIBuilder) buildV3(api *spec.AsyncAPI) ([]byte, error) {
+	doc := asyncAPIV3{
+		AsyncAPI: asyncAPIV3Version,
+		ID:       api.ID,
+		Info: asyncAPIV3Info{
+			Title:       api.Info.Title,
+			Version:     api.Info.Version,
+			Description: api.Info.Description,
+		},
+		Servers:    make(map[string]asyncAPIV3Server),
+		Channels:   make(map[string]asyncAPIV3Channel),
+		Operations: make(map[string]asyncAPIV3Operation),
+	}
+
+	for name, server := range b.servers {
+		host, pathname := splitAsyncAPIServerURL(server.URL)
+		v3Server := asyncAPIV3Server{
+			Host:            host,
+			Pathname:        pathname,
+			Protocol:        server.Protocol,
+			ProtocolVersion: server.ProtocolVersion,
+			Description:     server.Description,
+		}
+		for _, requirement := range server.Security {
+			for schemeName := range requirement {
+				v3Server.Security = append(v3Server.Security, asyncAPIV3Ref{Ref: fmt.Sprintf(componentSecuritySchemeRefTpl, schemeName)})
+			}
+		}
+		doc.Servers[name] = v3Server
+	}
+
+	// the channels are keyed by a name made of the allowed characters, the topic is kept in the address
+	keys := asyncAPIV3ChannelKeys(b.channels)
+	for name, channel := range b.channels {
+		key := keys[name]
+		v3Channel := asyncAPIV3Channel{
+			Address:     name,
+			Description: channel.Description,
+			Messages:    make(map[string]asyncAPIV3Ref),
+		}
+		operations := []struct {
+			action string
+			op     *spec.Operation
+			msgRef string
+		}{
+			{action: asyncAPIV3ActionReceive, op: channel.Publish, msgRef: b.channelPublishMessageRef[name]},
+			{action: asyncAPIV3ActionSend, op: channel.Subscribe, msgRef: b.channelSubscribeMessageRef[name]},
+		}
+		for _, o := range operations {
+			if o.op == nil {
+				continue
+			}
+			operation := asyncAPIV3Operation{
+				Action:  o.action,
+				Channel: asyncAPIV3Ref{Ref: fmt.Sprintf(channelRefTemplate, key)},
+			}
+			if o.msgRef != "" {
+				v3Channel.Messages[o.msgRef] = asyncAPIV3Ref{Ref: fmt.Sprintf(componentMessageRefTemplate, o.msgRef)}
+				operation.Messages = []asyncAPIV3Ref{{Ref: fmt.Sprintf(channelMessageRefTemplate, key, o.msgRef)}}
+			}
+			if o.op.Bindings != nil {
+				bindings, err := toAsyncAPIV3Map(o.op.Bindings)
+				if err != nil {
+					return nil, err
+				}
+				operation.Bindings = bindings
+			}
+			doc.Operations[fmt.Sprintf("%s.%s", key, o.action)] = operation
+		}
+		doc.Channels[key] = v3Channel
+	}
+
+	doc.Components.Messages = make(map[string]asyncAPIV3Message)
+	for msgName, msg := range b.componentMessages {
+		var payload interface{} = msg.Payload
+		if msg.SchemaFormat != "" {
+			// 3.0 moved the schema format from the message to a multi format schema payload
+			payload = map[string]interface{}{
+				"schemaFormat": msg.SchemaFormat,
+				"schema":       msg.Payload,
+			}
+		}
+		doc.Components.Messages[msgName] = asyncAPIV3Message{
+			ContentType: msg.ContentType,
+			Payload:     payload,
+		}
+	}
+	if len(b.securitySchemas) > 0 {
+		doc.Components.SecuritySchemes = make(map[string]interface{})
+		for name, securitySchema := range b.securitySchemas {
+			scheme, err := toAsyncAPIV3Map(securitySchema)
+			if err != nil {
+				return nil, err
+			}
+			doc.Components.SecuritySchemes[name] = scheme
+		}
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// asyncAPIV3ChannelKeys - the keys of the channels, the addresses with the characters not allowed in a key replaced.
+// The addresses that are valid keys are kept, the others are suffixed with a number when the key is already used.
+func asyncAPIV3ChannelKeys(channels map[string]spec.ChannelItem) map[string]string {
+	keys := make(map[string]string, len(channels))
+	used := make(map[string]bool, len(channels))
+	invalid := []string{}
+	for address := range channels {
+		if asyncAPIV3InvalidKeyChars.MatchString(address) || address == "" {
+			invalid = append(invalid, address)
+			continue
+		}
+		keys[address] = address
+		used[address] = true
+	}
+
+	sort.Strings(invalid)
+	for _, address := range invalid {
+		base := strings.Trim(asyncAPIV3InvalidKeyChars.ReplaceAllString(address, "_"), "_")
+		if base == "" {
+			base = "channel"
+		}
+		key := base
+		for i := 2; used[key]; i++ {
+			key = fmt.Sprintf("%s_%d", base, i)
+		}
+		keys[address] = key
+		used[key] = true
+	}
+	return keys
+}
+
+// splitAsyncAPIServerURL - returns the host, with port, and the pathname of a server url, the scheme is dropped
+func splitAsyncAPIServerURL(serverURL string) (string, string) {
+	if i := strings.Index(serverURL, "://"); i >= 0 {
+		serverURL = serverURL[i+3:]
+	}
+	if i := strings.Index(serverURL, "/"); i >= 0 {
+		return serverURL[:i], serverURL[i:]
+	}
+	return serverURL, ""
+}
+
+// toAsyncAPIV3Map - the 2.x spec objects that are unchanged in 3.0 are converted using their json representation
+func toAsyncAPIV3Map(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
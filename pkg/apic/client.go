@@ -1,8 +1,10 @@
 package apic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 	"strconv"
 	"strings"
@@ -57,6 +59,7 @@ const (
 	HdrContentType    = "Content-Type"
 	HdrAuthorization  = "Authorization"
 	HdrAxwayTenantID  = "X-Axway-Tenant-Id"
+	HdrIfMatch        = "If-Match"
 )
 
 // ValidPolicies - list of valid auth policies supported by Central.  Add to this list as more policies are supported.
@@ -68,6 +71,9 @@ type updateOptions struct {
 	existingRI             *apiv1.ResourceInstance
 	skipSetSpecHash        bool
 	skipXAgentDetailUpdate bool
+	ifMatch                bool
+	forceUpdate            bool
+	mergeAgentDetails      *mergeOptions
 }
 
 // Client - interface
@@ -75,6 +81,9 @@ type Client interface {
 	SetTokenGetter(tokenRequester auth.PlatformTokenGetter)
 	SetConfig(cfg corecfg.CentralConfig)
 	PublishService(serviceBody *ServiceBody) (*management.APIService, error)
+	PublishServices(serviceBodies []*ServiceBody, opts ...PublishOption) []PublishResult
+	GetDryRunPlan() *Plan
+	ApplyResources(resources []*apiv1.ResourceInstance, opts ...ApplyOption) ([]PlanChange, error)
 	DeleteAPIServiceInstance(name string) error
 	DeleteServiceByName(name string) error
 	GetUserEmailAddress(ID string) (string, error)
@@ -86,6 +95,12 @@ type Client interface {
 	GetAPIServiceInstances(query map[string]string, URL string) ([]*management.APIServiceInstance, error)
 	GetAPIV1ResourceInstances(query map[string]string, URL string) ([]*apiv1.ResourceInstance, error)
 	GetAPIV1ResourceCount(URL string) (int, error)
+	WalkAPIV1ResourceInstances(ctx context.Context, query map[string]string, URL string, handler ResourcePageHandler) error
+	IterateAPIV1ResourceInstances(ctx context.Context, query map[string]string, URL string) iter.Seq2[*apiv1.ResourceInstance, error]
+	ListAPIV1ResourceInstances(URL string, opts ...ListOption) ([]*apiv1.ResourceInstance, error)
+	ListAPIServices(opts ...ListOption) ([]*management.APIService, error)
+	ListAPIServiceRevisions(opts ...ListOption) ([]*management.APIServiceRevision, error)
+	ListAPIServiceInstances(opts ...ListOption) ([]*management.APIServiceInstance, error)
 	GetAPIServiceByName(name string) (*management.APIService, error)
 	GetAPIServiceInstanceByName(name string) (*management.APIServiceInstance, error)
 	GetAPIRevisionByName(name string) (*management.APIServiceRevision, error)
@@ -101,6 +116,9 @@ type Client interface {
 	UpdateResourceFinalizer(ri *apiv1.ResourceInstance, finalizer, description string, addAction bool) (*apiv1.ResourceInstance, error)
 
 	UpdateResourceInstance(ri apiv1.Interface) (*apiv1.ResourceInstance, error)
+	UpdateResourceInstanceIfMatch(ri apiv1.Interface) (*apiv1.ResourceInstance, error)
+	UpdateResourceWithRetry(selfLink string, mutate ResourceMutator, retries int) (*apiv1.ResourceInstance, error)
+	MergeSubResource(ri apiv1.Interface, subResourceName string, values map[string]interface{}, opts ...MergeOption) (*apiv1.ResourceInstance, error)
 	CreateOrUpdateResource(ri apiv1.Interface, opts ...UpdateOption) (*apiv1.ResourceInstance, error)
 	CreateResourceInstance(ri apiv1.Interface) (*apiv1.ResourceInstance, error)
 	PatchSubResource(ri apiv1.Interface, subResourceName string, patches []map[string]interface{}) (*apiv1.ResourceInstance, error)
@@ -160,6 +178,10 @@ func (c *ServiceClient) initClient(cfg corecfg.CentralConfig) {
 	c.cfg = cfg
 	c.apiClient = coreapi.NewClient(cfg.GetTLSConfig(), cfg.GetProxyURL(),
 		coreapi.WithTimeout(cfg.GetClientTimeout()), coreapi.WithSingleURL())
+	if cfg.IsDryRun() && c.dryRun == nil {
+		c.logger.Warn("dry run mode, the changes to Amplify Central are planned but not made")
+		c.dryRun = newDryRunPlanner(c.logger)
+	}
 
 	err := c.setTeamCache()
 	if err != nil {
@@ -572,31 +594,22 @@ func (c *ServiceClient) CreateAccessControlList(acl *management.AccessControlLis
 }
 
 func (c *ServiceClient) deployAccessControl(acl *management.AccessControlList, method string) (*management.AccessControlList, error) {
-	headers, err := c.createHeader()
-	if err != nil {
-		return nil, err
-	}
-
 	url := c.cfg.GetEnvironmentACLsURL()
 	if method == http.MethodPut || method == http.MethodDelete {
 		url = fmt.Sprintf("%s/%s", url, acl.Name)
 	}
 
-	request := coreapi.Request{
-		Method:  method,
-		URL:     url,
-		Headers: headers,
-	}
-
+	var data []byte
 	if method == http.MethodPut || method == http.MethodPost {
-		data, err := json.Marshal(*acl)
+		var err error
+		data, err = json.Marshal(*acl)
 		if err != nil {
 			return nil, err
 		}
-		request.Body = data
 	}
 
-	response, err := c.apiClient.Send(request)
+	// sent with executeAPI so the changes are only planned in dry run mode
+	response, err := c.executeAPI(method, url, nil, data, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -646,7 +659,17 @@ func (c *ServiceClient) executeAPI(method, url string, query map[string]string,
 		Body:        buffer,
 	}
 
-	return c.apiClient.Send(request)
+	if c.dryRun != nil {
+		if response := c.dryRun.send(request); response != nil {
+			return response, nil
+		}
+	}
+
+	response, err := c.apiClient.Send(request)
+	if err == nil {
+		c.checkRateLimit(response)
+	}
+	return response, err
 }
 
 // ExecuteAPI - execute the api
@@ -672,6 +695,10 @@ func (c *ServiceClient) ExecuteAPIWithHeader(method, url string, query map[strin
 		return nil, ErrAuthentication
 	case response.Code == http.StatusConflict:
 		return nil, ErrConflict
+	case response.Code == http.StatusPreconditionFailed:
+		return nil, ErrResourceVersionConflict
+	case response.Code == http.StatusTooManyRequests:
+		return nil, errors.Wrap(ErrRateLimited, readResponseErrors(response.Code, response.Body))
 	default:
 		responseErr := readResponseErrors(response.Code, response.Body)
 		return nil, errors.Wrap(ErrRequestQuery, responseErr)
@@ -855,6 +882,14 @@ func WithExistingResourceInstance(existingRI *apiv1.ResourceInstance) UpdateOpti
 	}
 }
 
+// withForceUpdate - updates the existing resource even when its spec hash, title and tags did not change, the
+// attributes of the resource are set on the existing resource
+func withForceUpdate() UpdateOption {
+	return func(o *updateOptions) {
+		o.forceUpdate = true
+	}
+}
+
 func WithSkipSetSpecHash(skip bool) UpdateOption {
 	return func(o *updateOptions) {
 		o.skipSetSpecHash = skip
@@ -896,6 +931,10 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 		logger = logger.WithField("resourceID", data.Metadata.ID)
 	}
 
+	oldHash := ""
+	newHash, _ := util.GetAgentDetailsValue(data, defs.AttrSpecHash)
+	var headers map[string]string
+	resourceVersion := ""
 	if err == nil && existingRI != nil && existingRI.Metadata.Scope.Name == data.Metadata.Scope.Name {
 		if data.Name == "" {
 			data.Name = existingRI.Name
@@ -905,9 +944,8 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 
 		// check if either hash, tags or title have changed and mark for update
 		equalTags := util.StringSlicesEqualUnordered(existingRI.GetTags(), data.GetTags())
-		oldHash, _ := util.GetAgentDetailsValue(existingRI, defs.AttrSpecHash)
-		newHash, _ := util.GetAgentDetailsValue(data, defs.AttrSpecHash)
-		if oldHash == newHash && existingRI.Title == data.Title && equalTags {
+		oldHash, _ = util.GetAgentDetailsValue(existingRI, defs.AttrSpecHash)
+		if oldHash == newHash && existingRI.Title == data.Title && equalTags && !options.forceUpdate {
 			logger.Debug("no updates to the hash or to the title")
 			updateRI = false
 		}
@@ -915,7 +953,12 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 		// check if x-agent-details have changed and mark for update
 		oldAgentDetails := util.GetAgentDetails(existingRI)
 		newAgentDetails := util.GetAgentDetails(data)
-		if util.MapsEqual(oldAgentDetails, newAgentDetails) {
+		if o := options.mergeAgentDetails; o != nil {
+			if _, changed := mergedSubResources(existingRI, defs.XAgentDetails, o.manager, newAgentDetails); !changed {
+				logger.Debug("no updates to the x-agent-details keys of the agent")
+				updateAgentDetails = false
+			}
+		} else if util.MapsEqual(oldAgentDetails, newAgentDetails) {
 			logger.Debug("no updates to the x-agent-details")
 			updateAgentDetails = false
 		}
@@ -923,6 +966,9 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 		// if no changes altogether, return without update
 		if !updateRI && !updateAgentDetails {
 			logger.Trace("no updates made to the resource instance or to the x-agent-details.")
+			if c.dryRun != nil {
+				c.dryRun.unchanged(existingRI.Kind, existingRI.Name, existingRI.Metadata.Scope.Name, newHash)
+			}
 			return existingRI, nil
 		}
 
@@ -939,6 +985,25 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 		existingRI.Title = data.Title
 		existingRI.Tags = data.GetTags()
 		existingRI.Owner = data.Owner
+		if options.forceUpdate {
+			for key, value := range data.Attributes {
+				if existingRI.Attributes == nil {
+					existingRI.Attributes = map[string]string{}
+				}
+				existingRI.Attributes[key] = value
+			}
+		}
+		if options.ifMatch {
+			// the version the caller read, the existing resource may be of a later version when the cache was updated
+			resourceVersion = data.Metadata.ResourceVersion
+			if resourceVersion == "" {
+				resourceVersion = existingRI.Metadata.ResourceVersion
+			}
+			if resourceVersion == "" {
+				return nil, ErrResourceVersionUnknown
+			}
+			headers = ifMatchHeaders(resourceVersion)
+		}
 		existingRI.Metadata.ResourceVersion = ""
 
 		// set the data and subresources to be pushed
@@ -952,8 +1017,13 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 			return nil, err
 		}
 
-		respBytes, err := c.ExecuteAPIWithHeader(method, url, nil, reqBytes, nil)
+		respBytes, err := c.ExecuteAPIWithHeader(method, url, nil, reqBytes, headers)
+		if c.dryRun != nil && err == nil {
+			c.dryRun.setSpecHashes(method, url, data.Name, oldHash, newHash)
+		}
 		switch {
+		case headers != nil && isConflict(err):
+			return nil, &ConflictError{Kind: data.Kind, Name: data.Name, ResourceVersion: resourceVersion}
 		case err == ErrConflict && method == coreapi.POST:
 			// Resource exists on server but not in cache; fetch and cache it.
 			log.Warnf("resource %s %s already exists on the server but was not in the local cache, fetching existing resource", data.Kind, data.Name)
@@ -979,7 +1049,16 @@ func (c *ServiceClient) updateSpecORCreateResourceInstance(data *apiv1.ResourceI
 	if data := util.GetAgentDetails(data); data != nil && updateAgentDetails {
 		var receivedRI *apiv1.ResourceInstance
 		// only send in the agent details here, that is all the agent needs to update for anything here
-		receivedRI, err = c.createSubResource(newRI.ResourceMeta, map[string]interface{}{defs.XAgentDetails: data})
+		if o := options.mergeAgentDetails; o != nil {
+			// the keys of others are merged with the latest x-agent-details, read again unless the resource was just created
+			var current *apiv1.ResourceInstance
+			if method == coreapi.POST {
+				current = newRI
+			}
+			receivedRI, err = c.mergeSubResource(newRI.GetSelfLink(), current, defs.XAgentDetails, data, o)
+		} else {
+			receivedRI, err = c.createSubResource(newRI.ResourceMeta, map[string]interface{}{defs.XAgentDetails: data})
+		}
 		if err != nil {
 			return nil, err
 		} else if receivedRI != nil {
@@ -1006,6 +1085,10 @@ func (c *ServiceClient) CreateOrUpdateResource(data apiv1.Interface, opts ...Upd
 
 // UpdateResourceInstance - updates a ResourceInstance
 func (c *ServiceClient) UpdateResourceInstance(ri apiv1.Interface) (*apiv1.ResourceInstance, error) {
+	return c.updateResourceInstance(ri, false)
+}
+
+func (c *ServiceClient) updateResourceInstance(ri apiv1.Interface, ifMatch bool) (*apiv1.ResourceInstance, error) {
 	inst, err := ri.AsInstance()
 	if err != nil {
 		return nil, err
@@ -1013,12 +1096,23 @@ func (c *ServiceClient) UpdateResourceInstance(ri apiv1.Interface) (*apiv1.Resou
 	if inst.GetSelfLink() == "" {
 		return nil, fmt.Errorf("could not remove resource instance, could not get self link")
 	}
+	var headers map[string]string
+	resourceVersion := inst.Metadata.ResourceVersion
+	if ifMatch {
+		if resourceVersion == "" {
+			return nil, ErrResourceVersionUnknown
+		}
+		headers = ifMatchHeaders(resourceVersion)
+	}
 	inst.Metadata.ResourceVersion = ""
 	bts, err := json.Marshal(ri)
 	if err != nil {
 		return nil, err
 	}
-	bts, err = c.ExecuteAPI(coreapi.PUT, c.createAPIServerURL(inst.GetSelfLink()), nil, bts)
+	bts, err = c.ExecuteAPIWithHeader(coreapi.PUT, c.createAPIServerURL(inst.GetSelfLink()), nil, bts, headers)
+	if headers != nil && isConflict(err) {
+		return nil, &ConflictError{Kind: inst.Kind, Name: inst.Name, ResourceVersion: resourceVersion}
+	}
 	if err != nil {
 		return nil, err
 	}
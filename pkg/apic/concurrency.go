@@ -0,0 +1,84 @@
+package apic
+
+import (
+	"errors"
+	"fmt"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	utilerrors "github.com/Axway/agent-sdk/pkg/util/errors"
+)
+
+// ConflictError - an update sent with If-Match that Amplify Central rejected, as the resource was changed since the
+// resource version was read. errors.Is matches it with ErrResourceVersionConflict.
+type ConflictError struct {
+	Kind            string
+	Name            string
+	ResourceVersion string
+}
+
+func (e *ConflictError) Error() string {
+	return utilerrors.Wrap(ErrResourceVersionConflict, fmt.Sprintf("%s %s at resource version %s", e.Kind, e.Name, e.ResourceVersion)).Error()
+}
+
+// Unwrap - the agent error of the conflict
+func (e *ConflictError) Unwrap() error {
+	return ErrResourceVersionConflict
+}
+
+// IsConflictError - true when the error is an update rejected as the resource was changed on Amplify Central
+func IsConflictError(err error) bool {
+	conflictErr := &ConflictError{}
+	return errors.As(err, &conflictErr)
+}
+
+// ResourceMutator - changes a resource read from Amplify Central before it is updated
+type ResourceMutator func(ri *apiv1.ResourceInstance) error
+
+// WithIfMatch - updates the existing resource only if its resource version on Amplify Central is still the version set
+// on the resource, or the version of the existing resource when none is set, returning a ConflictError otherwise
+func WithIfMatch() UpdateOption {
+	return func(o *updateOptions) {
+		o.ifMatch = true
+	}
+}
+
+// UpdateResourceInstanceIfMatch - updates the resource only if its resource version on Amplify Central is still the
+// resource version of ri, returning a ConflictError otherwise
+func (c *ServiceClient) UpdateResourceInstanceIfMatch(ri apiv1.Interface) (*apiv1.ResourceInstance, error) {
+	return c.updateResourceInstance(ri, true)
+}
+
+// UpdateResourceWithRetry - reads the resource at the self link, changes it with mutate and updates it only if it was
+// not changed on Amplify Central since it was read. On a conflict the resource is read again and mutate applied to the
+// new resource, up to retries times, so the changes made by others are not overwritten.
+func (c *ServiceClient) UpdateResourceWithRetry(selfLink string, mutate ResourceMutator, retries int) (*apiv1.ResourceInstance, error) {
+	logger := c.logger.WithField("selfLink", selfLink)
+	for attempt := 0; ; attempt++ {
+		ri, err := c.GetResource(selfLink)
+		if err != nil {
+			return nil, err
+		}
+		if err := mutate(ri); err != nil {
+			return nil, err
+		}
+
+		updated, err := c.UpdateResourceInstanceIfMatch(ri)
+		if err == nil || !IsConflictError(err) || attempt >= retries {
+			return updated, err
+		}
+		logger.WithField("attempt", attempt+1).Debug("the resource was changed since it was read, retrying the update")
+	}
+}
+
+// isConflict - true when the error is the response to a request whose If-Match did not match the resource
+func isConflict(err error) bool {
+	return err == ErrConflict || err == ErrResourceVersionConflict
+}
+
+// ifMatchHeaders - the headers making a request conditional on the resource version, nil when the version is not known
+func ifMatchHeaders(resourceVersion string) map[string]string {
+	if resourceVersion == "" {
+		return nil
+	}
+	return map[string]string{HdrIfMatch: fmt.Sprintf("%q", resourceVersion)}
+}
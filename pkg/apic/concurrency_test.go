@@ -0,0 +1,184 @@
+package apic
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+)
+
+func newVersionedService(t *testing.T, title, resourceVersion string) *apiv1.ResourceInstance {
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = title
+	svc.Metadata.ResourceVersion = resourceVersion
+	ri, err := svc.AsInstance()
+	assert.Nil(t, err)
+	return ri
+}
+
+func resourceResponse(t *testing.T, ri *apiv1.ResourceInstance) api.MockResponse {
+	data, err := json.Marshal(ri)
+	assert.Nil(t, err)
+	return api.MockResponse{RespCode: http.StatusOK, RespData: string(data)}
+}
+
+func TestUpdateResourceInstanceIfMatch(t *testing.T) {
+	tests := map[string]struct {
+		ifMatch  bool
+		respCode int
+		header   string
+		conflict bool
+	}{
+		"sends the resource version": {
+			ifMatch:  true,
+			respCode: http.StatusOK,
+			header:   `"5"`,
+		},
+		"returns a conflict error when the precondition fails": {
+			ifMatch:  true,
+			respCode: http.StatusPreconditionFailed,
+			header:   `"5"`,
+			conflict: true,
+		},
+		"returns a conflict error on conflict": {
+			ifMatch:  true,
+			respCode: http.StatusConflict,
+			header:   `"5"`,
+			conflict: true,
+		},
+		"does not send the resource version without if match": {
+			respCode: http.StatusOK,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, httpClient := GetTestServiceClient()
+			ri := newVersionedService(t, "Petstore", "5")
+			httpClient.SetResponses([]api.MockResponse{{RespCode: tc.respCode, RespData: "{}"}})
+
+			var err error
+			if tc.ifMatch {
+				_, err = client.UpdateResourceInstanceIfMatch(ri)
+			} else {
+				_, err = client.UpdateResourceInstance(ri)
+			}
+
+			assert.Len(t, httpClient.Requests, 1)
+			assert.Equal(t, tc.header, httpClient.Requests[0].Headers[HdrIfMatch])
+			assert.NotContains(t, string(httpClient.Requests[0].Body), "resourceVersion")
+			assert.Equal(t, tc.conflict, IsConflictError(err))
+			if !tc.conflict {
+				assert.Nil(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrResourceVersionConflict))
+			conflictErr := &ConflictError{}
+			assert.True(t, errors.As(err, &conflictErr))
+			assert.Equal(t, ConflictError{Kind: "APIService", Name: "petstore", ResourceVersion: "5"}, *conflictErr)
+		})
+	}
+}
+
+func TestCreateOrUpdateResourceWithIfMatch(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	existing := newVersionedService(t, "Petstore", "3")
+	httpClient.SetResponses([]api.MockResponse{{RespCode: http.StatusPreconditionFailed, RespData: "{}"}})
+
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = "Swagger Petstore"
+	_, err := client.CreateOrUpdateResource(svc, WithExistingResourceInstance(existing), WithIfMatch())
+	assert.True(t, IsConflictError(err))
+	assert.Len(t, httpClient.Requests, 1)
+	assert.Equal(t, http.MethodPut, httpClient.Requests[0].Method)
+	assert.Equal(t, `"3"`, httpClient.Requests[0].Headers[HdrIfMatch])
+}
+
+func TestCreateOrUpdateResourceWithIfMatchStaleVersion(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	// the cached resource is of the current version, the agent changed the version it read
+	existing := newVersionedService(t, "Pets", "4")
+	httpClient.SetResponses([]api.MockResponse{{RespCode: http.StatusPreconditionFailed, RespData: "{}"}})
+
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = "Swagger Petstore"
+	svc.Metadata.ResourceVersion = "3"
+	_, err := client.CreateOrUpdateResource(svc, WithExistingResourceInstance(existing), WithIfMatch())
+	assert.True(t, IsConflictError(err))
+	assert.Len(t, httpClient.Requests, 1)
+	assert.Equal(t, `"3"`, httpClient.Requests[0].Headers[HdrIfMatch])
+	conflictErr := &ConflictError{}
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, "3", conflictErr.ResourceVersion)
+}
+
+func TestIfMatchWithoutResourceVersion(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	_, err := client.UpdateResourceInstanceIfMatch(newVersionedService(t, "Petstore", ""))
+	assert.True(t, errors.Is(err, ErrResourceVersionUnknown))
+	assert.Len(t, httpClient.Requests, 0)
+
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = "Swagger Petstore"
+	_, err = client.CreateOrUpdateResource(svc, WithExistingResourceInstance(newVersionedService(t, "Petstore", "")), WithIfMatch())
+	assert.True(t, errors.Is(err, ErrResourceVersionUnknown))
+	assert.Len(t, httpClient.Requests, 0)
+}
+
+func TestUpdateResourceWithRetry(t *testing.T) {
+	selfLink := newVersionedService(t, "", "").GetSelfLink()
+	setTitle := func(ri *apiv1.ResourceInstance) error {
+		ri.Title = ri.Title + " (updated)"
+		return nil
+	}
+	conflict := api.MockResponse{RespCode: http.StatusPreconditionFailed, RespData: "{}"}
+
+	t.Run("reads and changes the resource again after a conflict", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		updated := newVersionedService(t, "Pets (updated)", "3")
+		httpClient.SetResponses([]api.MockResponse{
+			resourceResponse(t, newVersionedService(t, "Petstore", "1")),
+			conflict,
+			resourceResponse(t, newVersionedService(t, "Pets", "2")),
+			resourceResponse(t, updated),
+		})
+
+		ri, err := client.UpdateResourceWithRetry(selfLink, setTitle, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Pets (updated)", ri.Title)
+		assert.Len(t, httpClient.Requests, 4)
+		assert.Equal(t, `"2"`, httpClient.Requests[3].Headers[HdrIfMatch])
+		sent := &apiv1.ResourceInstance{}
+		assert.Nil(t, json.Unmarshal(httpClient.Requests[3].Body, sent))
+		assert.Equal(t, "Pets (updated)", sent.Title)
+	})
+
+	t.Run("returns the conflict when the retries are exhausted", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{
+			resourceResponse(t, newVersionedService(t, "Petstore", "1")),
+			conflict,
+			resourceResponse(t, newVersionedService(t, "Pets", "2")),
+			conflict,
+		})
+
+		_, err := client.UpdateResourceWithRetry(selfLink, setTitle, 1)
+		assert.True(t, IsConflictError(err))
+		assert.Len(t, httpClient.Requests, 4)
+	})
+
+	t.Run("returns the error of the mutation", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{resourceResponse(t, newVersionedService(t, "Petstore", "1"))})
+		mutateErr := errors.New("mutate error")
+
+		_, err := client.UpdateResourceWithRetry(selfLink, func(*apiv1.ResourceInstance) error { return mutateErr }, 3)
+		assert.ErrorIs(t, err, mutateErr)
+		assert.Len(t, httpClient.Requests, 1)
+	})
+}
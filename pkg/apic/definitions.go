@@ -2,6 +2,7 @@ package apic
 
 import (
 	"sync"
+	"sync/atomic"
 
 	cache2 "github.com/Axway/agent-sdk/pkg/agent/cache"
 	"github.com/Axway/agent-sdk/pkg/util/log"
@@ -28,6 +29,7 @@ const (
 	Mcp           = "mcp"
 	A2a           = "a2a"
 	Raml          = "RAML"
+	OpenRPC       = "openrpc"
 
 	SubscriptionSchemaNameSuffix      = ".authsubscription"
 	DefaultSubscriptionWebhookName    = "subscriptionwebhook"
@@ -35,6 +37,7 @@ const (
 
 	FieldsKey = "fields"
 	QueryKey  = "query"
+	SortKey   = "sort"
 
 	CreateTimestampQueryKey = "metadata.audit.createTimestamp"
 
@@ -162,6 +165,8 @@ type ServiceClient struct {
 	pageSizes                          map[string]int
 	pageSizeMutex                      *sync.Mutex
 	apiClientWorkers                   int
+	rateLimitedUntil                   atomic.Int64
+	dryRun                             *dryRunPlanner
 }
 
 // APIServerInfoProperty -
@@ -37,6 +37,7 @@ const (
 	AttrExternalAPISyncWarning       = "externalAPISyncWarning"
 	AttrCreatedBy                    = "createdBy"
 	AttrSpecHash                     = "specHash"
+	AttrManagedKeys                  = "managedKeys"
 	Spec                             = "spec"
 	MarketplaceSubResource           = "marketplace"
 	ReferencesSubResource            = "references"
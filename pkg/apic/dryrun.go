@@ -0,0 +1,249 @@
+package apic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// PlanAction - the action a dry run plans for a resource
+type PlanAction string
+
+const (
+	PlanCreate    PlanAction = "create"
+	PlanUpdate    PlanAction = "update"
+	PlanDelete    PlanAction = "delete"
+	PlanUnchanged PlanAction = "unchanged"
+)
+
+// PlanChange - a change to Amplify Central planned by a dry run, instead of being made, or made by ApplyResources
+type PlanChange struct {
+	Action      PlanAction `json:"action"`
+	Kind        string     `json:"kind,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Scope       string     `json:"scope,omitempty"`
+	SubResource string     `json:"subResource,omitempty"`
+	Method      string     `json:"method,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	// PreviousSpecHash and SpecHash - the spec hashes compared to decide whether the resource is updated
+	PreviousSpecHash string          `json:"previousSpecHash,omitempty"`
+	SpecHash         string          `json:"specHash,omitempty"`
+	Body             json.RawMessage `json:"body,omitempty"`
+	// Diff - the fields of an updated resource that changed, set when applying resources
+	Diff []string `json:"diff,omitempty"`
+}
+
+// Plan - the changes planned by a dry run, in the order they were planned
+type Plan struct {
+	Changes []PlanChange `json:"changes"`
+}
+
+// dryRunPlanner - records the changes the service client would make and answers the requests making them, so the
+// publishing continues as if they were made. Requests reading resources are still sent to Amplify Central.
+type dryRunPlanner struct {
+	mutex   sync.Mutex
+	logger  log.FieldLogger
+	changes []PlanChange
+	// resources - the resources created or updated by the dry run, by their self link
+	resources map[string]map[string]interface{}
+	// created - the index of the change creating the resource, by its self link
+	created map[string]int
+}
+
+func newDryRunPlanner(logger log.FieldLogger) *dryRunPlanner {
+	return &dryRunPlanner{
+		logger:    logger.WithField("dryRun", true),
+		resources: map[string]map[string]interface{}{},
+		created:   map[string]int{},
+	}
+}
+
+// GetDryRunPlan - the changes planned since the client was created, nil when the client is not in dry run mode
+func (c *ServiceClient) GetDryRunPlan() *Plan {
+	if c.dryRun == nil {
+		return nil
+	}
+	return c.dryRun.plan()
+}
+
+func (p *dryRunPlanner) plan() *Plan {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	plan := &Plan{Changes: make([]PlanChange, len(p.changes))}
+	copy(plan.Changes, p.changes)
+	return plan
+}
+
+// send - plans the change made by the request and returns the response Amplify Central would send,
+// nil for the requests that do not make changes
+func (p *dryRunPlanner) send(request coreapi.Request) *coreapi.Response {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	link := apiServerLink(request.URL)
+	resource := map[string]interface{}{}
+	json.Unmarshal(request.Body, &resource)
+	change := PlanChange{
+		Method: request.Method,
+		URL:    request.URL,
+		Body:   json.RawMessage(request.Body),
+	}
+
+	switch request.Method {
+	case http.MethodPost:
+		change.Action = PlanCreate
+		link = link + "/" + resourceField(resource, "name")
+		setResourceSelfLink(resource, link)
+		p.resources[link] = resource
+		p.created[link] = len(p.changes)
+		p.add(change, resource)
+		body, _ := json.Marshal(resource)
+		return &coreapi.Response{Code: http.StatusCreated, Body: body}
+	case http.MethodPut:
+		change.Action = PlanUpdate
+		if _, isResource := resource["kind"]; isResource {
+			setResourceSelfLink(resource, link)
+			p.resources[link] = resource
+			p.add(change, resource)
+			return &coreapi.Response{Code: http.StatusOK, Body: request.Body}
+		}
+		return p.updateSubResource(change, link, func(stored map[string]interface{}) {
+			for sub, value := range resource {
+				stored[sub] = value
+			}
+		})
+	case http.MethodPatch:
+		change.Action = PlanUpdate
+		return p.updateSubResource(change, link, nil)
+	case http.MethodDelete:
+		change.Action = PlanDelete
+		if _, isResource := resource["kind"]; !isResource {
+			resource = p.resources[link]
+		}
+		if resource == nil {
+			change.Name = path.Base(link)
+		}
+		delete(p.resources, link)
+		delete(p.created, link)
+		p.add(change, resource)
+		return &coreapi.Response{Code: http.StatusNoContent}
+	}
+	return nil
+}
+
+// updateSubResource - plans the update of the sub resource at the link, which is merged in the create change when the
+// resource is created by the dry run
+func (p *dryRunPlanner) updateSubResource(change PlanChange, link string, merge func(map[string]interface{})) *coreapi.Response {
+	resourceLink, subResource := path.Split(link)
+	resourceLink = strings.TrimSuffix(resourceLink, "/")
+	change.SubResource = subResource
+
+	stored, found := p.resources[resourceLink]
+	if !found {
+		change.Name = path.Base(resourceLink)
+		p.add(change, nil)
+		return &coreapi.Response{Code: http.StatusOK, Body: []byte("{}")}
+	}
+
+	if merge != nil {
+		merge(stored)
+	}
+	body, _ := json.Marshal(stored)
+	if i, created := p.created[resourceLink]; created {
+		p.changes[i].Body = body
+		return &coreapi.Response{Code: http.StatusOK, Body: body}
+	}
+	p.add(change, stored)
+	return &coreapi.Response{Code: http.StatusOK, Body: body}
+}
+
+// add - records the change, setting the kind, name and scope of the resource, and logs it
+func (p *dryRunPlanner) add(change PlanChange, resource map[string]interface{}) {
+	if resource != nil {
+		change.Kind = resourceField(resource, "kind")
+		change.Name = resourceField(resource, "name")
+		if metadata, ok := resource["metadata"].(map[string]interface{}); ok {
+			change.Scope = resourceField(metadata["scope"], "name")
+		}
+	}
+	p.changes = append(p.changes, change)
+
+	data, _ := json.Marshal(change)
+	p.logger.
+		WithField("action", change.Action).
+		WithField("kind", change.Kind).
+		WithField("name", change.Name).
+		WithField("plan", string(data)).
+		Info("planned change to Amplify Central")
+}
+
+// setSpecHashes - sets the spec hashes compared before the last change made with the method to the url for the resource
+func (p *dryRunPlanner) setSpecHashes(method, url, name, previousHash, hash string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i := len(p.changes) - 1; i >= 0; i-- {
+		if c := &p.changes[i]; c.Method == method && c.URL == url && c.Name == name {
+			c.PreviousSpecHash = previousHash
+			c.SpecHash = hash
+			return
+		}
+	}
+}
+
+// unchanged - records a resource that is not changed as it did not change since it was last published
+func (p *dryRunPlanner) unchanged(kind, name, scope, specHash string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.changes = append(p.changes, PlanChange{
+		Action:           PlanUnchanged,
+		Kind:             kind,
+		Name:             name,
+		Scope:            scope,
+		PreviousSpecHash: specHash,
+		SpecHash:         specHash,
+	})
+	p.logger.
+		WithField("action", PlanUnchanged).
+		WithField("kind", kind).
+		WithField("name", name).
+		Debug("no change planned to Amplify Central")
+}
+
+// apiServerLink - the path of the url after /apis, which is the self link of the resource
+func apiServerLink(rawURL string) string {
+	link := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		link = u.Path
+	}
+	if i := strings.Index(link, "/apis/"); i >= 0 {
+		link = link[i+len("/apis"):]
+	}
+	return strings.TrimSuffix(link, "/")
+}
+
+func setResourceSelfLink(resource map[string]interface{}, link string) {
+	metadata, ok := resource["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		resource["metadata"] = metadata
+	}
+	metadata["selfLink"] = link
+}
+
+func resourceField(resource interface{}, field string) string {
+	if m, ok := resource.(map[string]interface{}); ok {
+		if value, ok := m[field].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+package apic
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+func TestDryRunPublishService(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	assert.Nil(t, client.GetDryRunPlan())
+	client.dryRun = newDryRunPlanner(client.logger)
+	// only the revision list is read from the server
+	httpClient.SetResponses([]api.MockResponse{emptyRevisionListResponse})
+
+	oas2Bytes, err := os.ReadFile(testPetstoreSpec)
+	assert.Nil(t, err)
+	body, err := NewServiceBodyBuilder().
+		SetAPIName(serviceBody.APIName).
+		SetResourceType(serviceBody.ResourceType).
+		SetID(serviceBody.RestAPIID).
+		SetAPISpec(oas2Bytes).
+		Build()
+	assert.Nil(t, err)
+
+	apiSvc, err := client.PublishService(&body)
+	assert.Nil(t, err)
+	assert.NotNil(t, apiSvc)
+	assert.Len(t, httpClient.Requests, 1)
+	assert.Equal(t, http.MethodGet, httpClient.Requests[0].Method)
+
+	plan := client.GetDryRunPlan()
+	kinds := []string{}
+	for _, change := range plan.Changes {
+		assert.Equal(t, PlanCreate, change.Action)
+		assert.Equal(t, "v7envandcat", change.Scope)
+		kinds = append(kinds, change.Kind)
+	}
+	assert.Equal(t, []string{management.APIServiceGVK().Kind, management.APIServiceRevisionGVK().Kind, management.APIServiceInstanceGVK().Kind}, kinds)
+	assert.NotEmpty(t, plan.Changes[1].SpecHash)
+	assert.Empty(t, plan.Changes[1].PreviousSpecHash)
+
+	// the sub resources of a created resource are part of the create change
+	created := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(plan.Changes[0].Body, &created))
+	assert.Contains(t, created, defs.XAgentDetails)
+
+	_, err = json.Marshal(plan)
+	assert.Nil(t, err)
+}
+
+func TestDryRunCreateOrUpdateResource(t *testing.T) {
+	newService := func(specHash string) *management.APIService {
+		svc := management.NewAPIService("petstore", "v7envandcat")
+		svc.Title = "Petstore"
+		util.SetAgentDetails(svc, map[string]interface{}{defs.AttrSpecHash: specHash})
+		return svc
+	}
+
+	tests := map[string]struct {
+		existingHash string
+		hash         string
+		expected     []PlanChange
+	}{
+		"update when the spec hash changed": {
+			existingHash: "1",
+			hash:         "2",
+			expected: []PlanChange{
+				{Action: PlanUpdate, PreviousSpecHash: "1", SpecHash: "2"},
+				{Action: PlanUpdate, SubResource: defs.XAgentDetails},
+			},
+		},
+		"unchanged when the spec hash is the same": {
+			existingHash: "1",
+			hash:         "1",
+			expected: []PlanChange{
+				{Action: PlanUnchanged, PreviousSpecHash: "1", SpecHash: "1"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, httpClient := GetTestServiceClient()
+			client.dryRun = newDryRunPlanner(client.logger)
+
+			existingRI, _ := newService(tc.existingHash).AsInstance()
+			_, err := client.CreateOrUpdateResource(newService(tc.hash),
+				WithExistingResourceInstance(existingRI),
+				WithSkipSetSpecHash(true),
+			)
+			assert.Nil(t, err)
+			assert.Empty(t, httpClient.Requests)
+
+			plan := client.GetDryRunPlan()
+			assert.Len(t, plan.Changes, len(tc.expected))
+			for i, expected := range tc.expected {
+				if i >= len(plan.Changes) {
+					break
+				}
+				change := plan.Changes[i]
+				assert.Equal(t, expected.Action, change.Action)
+				assert.Equal(t, "petstore", change.Name)
+				assert.Equal(t, expected.SubResource, change.SubResource)
+				assert.Equal(t, expected.PreviousSpecHash, change.PreviousSpecHash)
+				assert.Equal(t, expected.SpecHash, change.SpecHash)
+			}
+		})
+	}
+}
+
+func TestDryRunDelete(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	client.dryRun = newDryRunPlanner(client.logger)
+
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	assert.Nil(t, client.DeleteResourceInstance(svc))
+	assert.Nil(t, client.DeleteServiceByName("orders"))
+	assert.Empty(t, httpClient.Requests)
+
+	plan := client.GetDryRunPlan()
+	assert.Len(t, plan.Changes, 2)
+	assert.Equal(t, PlanChange{
+		Action: PlanDelete,
+		Kind:   management.APIServiceGVK().Kind,
+		Name:   "petstore",
+		Scope:  "v7envandcat",
+	}, PlanChange{
+		Action: plan.Changes[0].Action,
+		Kind:   plan.Changes[0].Kind,
+		Name:   plan.Changes[0].Name,
+		Scope:  plan.Changes[0].Scope,
+	})
+	assert.Equal(t, PlanDelete, plan.Changes[1].Action)
+	assert.Equal(t, "orders", plan.Changes[1].Name)
+}
+
+func TestDryRunAccessControlList(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	client.dryRun = newDryRunPlanner(client.logger)
+
+	acl, _ := management.NewAccessControlList("agent-acl", management.EnvironmentGVK().Kind, "v7envandcat")
+	created, err := client.CreateAccessControlList(acl)
+	assert.Nil(t, err)
+	assert.Equal(t, "agent-acl", created.Name)
+	_, err = client.UpdateAccessControlList(acl)
+	assert.Nil(t, err)
+	// no request reaches Amplify Central
+	assert.Empty(t, httpClient.Requests)
+
+	plan := client.GetDryRunPlan()
+	assert.Len(t, plan.Changes, 2)
+	assert.Equal(t, PlanCreate, plan.Changes[0].Action)
+	assert.Equal(t, PlanUpdate, plan.Changes[1].Action)
+	for _, change := range plan.Changes {
+		assert.Equal(t, management.AccessControlListGVK().Kind, change.Kind)
+		assert.Equal(t, "agent-acl", change.Name)
+	}
+}
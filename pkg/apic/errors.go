@@ -4,14 +4,17 @@ import "github.com/Axway/agent-sdk/pkg/util/errors"
 
 // Errors hit when validating Amplify Central connectivity
 var (
-	ErrCentralConfig      = errors.New(1100, "configuration error for Amplify Central")
-	ErrEnvironmentQuery   = errors.New(1101, "error sending request to Amplify Central. Check configuration for CENTRAL_ENVIRONMENT")
-	ErrTeamNotFound       = errors.Newf(1102, "could not find team (%s) in Amplify Central. Check configuration for CENTRAL_TEAM")
-	ErrNetwork            = errors.New(1110, "error connecting to Amplify Central. Check docs.axway.com for more info on this error code")
-	ErrRequestQuery       = errors.New(1120, "error making a request to Amplify")
-	ErrAuthenticationCall = errors.New(1130, "error getting authentication token. Check Amplify Central auth configuration (CENTRAL_AUTH_*) and network configuration for agent on docs.axway.com")
-	ErrAuthentication     = errors.New(1131, "authentication token was not valid. Check Amplify Central auth configuration (CENTRAL_AUTH_*)")
-	ErrConflict           = errors.New(1132, "conflict: resource already exists on Amplify Central")
+	ErrCentralConfig           = errors.New(1100, "configuration error for Amplify Central")
+	ErrEnvironmentQuery        = errors.New(1101, "error sending request to Amplify Central. Check configuration for CENTRAL_ENVIRONMENT")
+	ErrTeamNotFound            = errors.Newf(1102, "could not find team (%s) in Amplify Central. Check configuration for CENTRAL_TEAM")
+	ErrNetwork                 = errors.New(1110, "error connecting to Amplify Central. Check docs.axway.com for more info on this error code")
+	ErrRequestQuery            = errors.New(1120, "error making a request to Amplify")
+	ErrAuthenticationCall      = errors.New(1130, "error getting authentication token. Check Amplify Central auth configuration (CENTRAL_AUTH_*) and network configuration for agent on docs.axway.com")
+	ErrAuthentication          = errors.New(1131, "authentication token was not valid. Check Amplify Central auth configuration (CENTRAL_AUTH_*)")
+	ErrConflict                = errors.New(1132, "conflict: resource already exists on Amplify Central")
+	ErrRateLimited             = errors.New(1133, "request was rate limited by Amplify Central")
+	ErrResourceVersionConflict = errors.New(1134, "conflict: the resource was changed on Amplify Central since it was read")
+	ErrResourceVersionUnknown  = errors.New(1135, "resource version unknown, cannot send If-Match")
 )
 
 // Errors hit when calling different Amplify APIs
@@ -20,4 +23,10 @@ var (
 
 	// Service body builder
 	ErrSetSpecEndPoints = errors.New(1160, "error getting endpoints for the API specification")
+
+	// Spec processor registry
+	ErrSpecProcessorRegistration = errors.Newf(1170, "could not register the spec processor for resource type '%s': %s")
+
+	// Spec validation
+	ErrSpecValidation = errors.Newf(1171, "the specification for '%s' did not pass validation: %s")
 )
@@ -1,6 +1,9 @@
 package mock
 
 import (
+	"context"
+	"iter"
+
 	"github.com/Axway/agent-sdk/pkg/apic"
 	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
 	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
@@ -14,6 +17,9 @@ type Client struct {
 	SetTokenGetterMock                        func(tokenRequester auth.PlatformTokenGetter)
 	SetConfigMock                             func(cfg corecfg.CentralConfig)
 	PublishServiceMock                        func(serviceBody *apic.ServiceBody) (*management.APIService, error)
+	PublishServicesMock                       func(serviceBodies []*apic.ServiceBody, opts ...apic.PublishOption) []apic.PublishResult
+	GetDryRunPlanMock                         func() *apic.Plan
+	ApplyResourcesMock                        func(resources []*v1.ResourceInstance, opts ...apic.ApplyOption) ([]apic.PlanChange, error)
 	DeleteAPIServiceInstanceMock              func(name string) error
 	DeleteServiceByNameMock                   func(name string) error
 	GetUserEmailAddressMock                   func(ID string) (string, error)
@@ -27,6 +33,12 @@ type Client struct {
 	GetAPIV1ResourceInstancesMock             func(queryParams map[string]string, URL string) ([]*v1.ResourceInstance, error)
 	GetAPIV1ResourceInstancesWithPageSizeMock func(queryParams map[string]string, URL string, pageSize int) ([]*v1.ResourceInstance, error)
 	GetAPIV1ResourceCountMock                 func(URL string) (int, error)
+	WalkAPIV1ResourceInstancesMock            func(ctx context.Context, queryParams map[string]string, URL string, handler apic.ResourcePageHandler) error
+	IterateAPIV1ResourceInstancesMock         func(ctx context.Context, queryParams map[string]string, URL string) iter.Seq2[*v1.ResourceInstance, error]
+	ListAPIV1ResourceInstancesMock            func(URL string, opts ...apic.ListOption) ([]*v1.ResourceInstance, error)
+	ListAPIServicesMock                       func(opts ...apic.ListOption) ([]*management.APIService, error)
+	ListAPIServiceRevisionsMock               func(opts ...apic.ListOption) ([]*management.APIServiceRevision, error)
+	ListAPIServiceInstancesMock               func(opts ...apic.ListOption) ([]*management.APIServiceInstance, error)
 	GetAPIServiceByNameMock                   func(serviceName string) (*management.APIService, error)
 	GetAPIServiceInstanceByNameMock           func(serviceInstanceName string) (*management.APIServiceInstance, error)
 	GetAPIRevisionByNameMock                  func(serviceRevisionName string) (*management.APIServiceRevision, error)
@@ -37,6 +49,9 @@ type Client struct {
 	UpdateAccessControlListMock               func(acl *management.AccessControlList) (*management.AccessControlList, error)
 	CreateAccessControlListMock               func(acl *management.AccessControlList) (*management.AccessControlList, error)
 	UpdateResourceInstanceMock                func(ri v1.Interface) (*v1.ResourceInstance, error)
+	UpdateResourceInstanceIfMatchMock         func(ri v1.Interface) (*v1.ResourceInstance, error)
+	UpdateResourceWithRetryMock               func(selfLink string, mutate apic.ResourceMutator, retries int) (*v1.ResourceInstance, error)
+	MergeSubResourceMock                      func(ri v1.Interface, subResourceName string, values map[string]interface{}, opts ...apic.MergeOption) (*v1.ResourceInstance, error)
 	CreateResourceInstanceMock                func(ri v1.Interface) (*v1.ResourceInstance, error)
 	PatchSubResourceMock                      func(ri v1.Interface, subResourceName string, patches []map[string]interface{}) (*v1.ResourceInstance, error)
 	DeleteResourceInstanceMock                func(ri v1.Interface) error
@@ -121,6 +136,54 @@ func (m *Client) GetAPIV1ResourceCount(URL string) (int, error) {
 	return 0, nil
 }
 
+// WalkAPIV1ResourceInstances -
+func (m *Client) WalkAPIV1ResourceInstances(ctx context.Context, queryParams map[string]string, URL string, handler apic.ResourcePageHandler) error {
+	if m.WalkAPIV1ResourceInstancesMock != nil {
+		return m.WalkAPIV1ResourceInstancesMock(ctx, queryParams, URL, handler)
+	}
+	return nil
+}
+
+// IterateAPIV1ResourceInstances -
+func (m *Client) IterateAPIV1ResourceInstances(ctx context.Context, queryParams map[string]string, URL string) iter.Seq2[*v1.ResourceInstance, error] {
+	if m.IterateAPIV1ResourceInstancesMock != nil {
+		return m.IterateAPIV1ResourceInstancesMock(ctx, queryParams, URL)
+	}
+	return func(func(*v1.ResourceInstance, error) bool) {}
+}
+
+// ListAPIV1ResourceInstances -
+func (m *Client) ListAPIV1ResourceInstances(URL string, opts ...apic.ListOption) ([]*v1.ResourceInstance, error) {
+	if m.ListAPIV1ResourceInstancesMock != nil {
+		return m.ListAPIV1ResourceInstancesMock(URL, opts...)
+	}
+	return nil, nil
+}
+
+// ListAPIServices -
+func (m *Client) ListAPIServices(opts ...apic.ListOption) ([]*management.APIService, error) {
+	if m.ListAPIServicesMock != nil {
+		return m.ListAPIServicesMock(opts...)
+	}
+	return nil, nil
+}
+
+// ListAPIServiceRevisions -
+func (m *Client) ListAPIServiceRevisions(opts ...apic.ListOption) ([]*management.APIServiceRevision, error) {
+	if m.ListAPIServiceRevisionsMock != nil {
+		return m.ListAPIServiceRevisionsMock(opts...)
+	}
+	return nil, nil
+}
+
+// ListAPIServiceInstances -
+func (m *Client) ListAPIServiceInstances(opts ...apic.ListOption) ([]*management.APIServiceInstance, error) {
+	if m.ListAPIServiceInstancesMock != nil {
+		return m.ListAPIServiceInstancesMock(opts...)
+	}
+	return nil, nil
+}
+
 // GetAPIServiceByName -
 func (m *Client) GetAPIServiceByName(serviceName string) (*management.APIService, error) {
 	if m.GetAPIServiceByNameMock != nil {
@@ -160,6 +223,30 @@ func (m *Client) PublishService(serviceBody *apic.ServiceBody) (*management.APIS
 	return nil, nil
 }
 
+// PublishServices -
+func (m *Client) PublishServices(serviceBodies []*apic.ServiceBody, opts ...apic.PublishOption) []apic.PublishResult {
+	if m.PublishServicesMock != nil {
+		return m.PublishServicesMock(serviceBodies, opts...)
+	}
+	return nil
+}
+
+// GetDryRunPlan -
+func (m *Client) GetDryRunPlan() *apic.Plan {
+	if m.GetDryRunPlanMock != nil {
+		return m.GetDryRunPlanMock()
+	}
+	return nil
+}
+
+// ApplyResources -
+func (m *Client) ApplyResources(resources []*v1.ResourceInstance, opts ...apic.ApplyOption) ([]apic.PlanChange, error) {
+	if m.ApplyResourcesMock != nil {
+		return m.ApplyResourcesMock(resources, opts...)
+	}
+	return nil, nil
+}
+
 // DeleteServiceByName -
 func (m *Client) DeleteServiceByName(serviceName string) error {
 	if m.DeleteServiceByNameMock != nil {
@@ -255,6 +342,30 @@ func (m *Client) UpdateResourceInstance(ri v1.Interface) (*v1.ResourceInstance,
 	return nil, nil
 }
 
+// UpdateResourceInstanceIfMatch -
+func (m *Client) UpdateResourceInstanceIfMatch(ri v1.Interface) (*v1.ResourceInstance, error) {
+	if m.UpdateResourceInstanceIfMatchMock != nil {
+		return m.UpdateResourceInstanceIfMatchMock(ri)
+	}
+	return nil, nil
+}
+
+// UpdateResourceWithRetry -
+func (m *Client) UpdateResourceWithRetry(selfLink string, mutate apic.ResourceMutator, retries int) (*v1.ResourceInstance, error) {
+	if m.UpdateResourceWithRetryMock != nil {
+		return m.UpdateResourceWithRetryMock(selfLink, mutate, retries)
+	}
+	return nil, nil
+}
+
+// MergeSubResource -
+func (m *Client) MergeSubResource(ri v1.Interface, subResourceName string, values map[string]interface{}, opts ...apic.MergeOption) (*v1.ResourceInstance, error) {
+	if m.MergeSubResourceMock != nil {
+		return m.MergeSubResourceMock(ri, subResourceName, values, opts...)
+	}
+	return nil, nil
+}
+
 // CreateResourceInstance -
 func (m *Client) CreateResourceInstance(ri v1.Interface) (*v1.ResourceInstance, error) {
 	if m.CreateResourceInstanceMock != nil {
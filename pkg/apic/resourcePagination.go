@@ -1,9 +1,11 @@
 package apic
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"strconv"
 	"strings"
@@ -91,6 +93,94 @@ func (c *ServiceClient) GetAPIV1ResourceInstances(queryParams map[string]string,
 	}
 }
 
+// ResourcePageHandler - handles a page of resources read by WalkAPIV1ResourceInstances, an error stops the walk
+type ResourcePageHandler func(page []*apiv1.ResourceInstance) error
+
+// WalkAPIV1ResourceInstances - reads the resources at the url a page at a time, in order, and calls the handler with
+// each page, so only one page is held in memory. A page that times out is read again with half the page size, as
+// GetAPIV1ResourceInstances does, and the smaller page size is kept for the next pages. The walk stops when the
+// context is done, returning its error, or when the handler returns an error.
+func (c *ServiceClient) WalkAPIV1ResourceInstances(ctx context.Context, queryParams map[string]string, url string, handler ResourcePageHandler) error {
+	// the absolute index of the next resource to read, so the page size can change between pages
+	offset := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pageSize := c.cfg.GetPageSize()
+		if size, ok := c.getPageSize(url); ok {
+			pageSize = size
+		}
+		params := pageParams{pageSize: pageSize, page: offset / pageSize, skipFirstN: offset % pageSize}
+		page, err := c.fetchPage(queryParams, url, params)
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := handler(page); err != nil {
+				return err
+			}
+		}
+
+		offset += len(page)
+		if len(page) < params.pageSize-params.skipFirstN {
+			return nil
+		}
+	}
+}
+
+// IterateAPIV1ResourceInstances - an iterator over the resources at the url, read a page at a time with
+// WalkAPIV1ResourceInstances. An error reading a page is yielded with a nil resource and ends the iteration.
+func (c *ServiceClient) IterateAPIV1ResourceInstances(ctx context.Context, queryParams map[string]string, url string) iter.Seq2[*apiv1.ResourceInstance, error] {
+	return func(yield func(*apiv1.ResourceInstance, error) bool) {
+		errStop := errors.New("iteration stopped")
+		err := c.WalkAPIV1ResourceInstances(ctx, queryParams, url, func(page []*apiv1.ResourceInstance) error {
+			for _, ri := range page {
+				if !yield(ri, nil) {
+					return errStop
+				}
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(nil, err)
+		}
+	}
+}
+
+// fetchPage fetches the range described by params with fetchPageParams, so a range that times out is
+// fetched again in halves, and returns its resources in order.
+func (c *ServiceClient) fetchPage(queryParams map[string]string, url string, params pageParams) ([]*apiv1.ResourceInstance, error) {
+	riChan := make(chan apiv1.ResourceInstance)
+	errChan := make(chan error)
+	go func() {
+		c.fetchPageParams(queryParams, url, params, riChan, errChan, maxPageSizeRetries)
+		close(riChan)
+		close(errChan)
+	}()
+
+	resourceInstances := []*apiv1.ResourceInstance{}
+	var errs []error
+	for riChan != nil || errChan != nil {
+		select {
+		case ri, ok := <-riChan:
+			if !ok {
+				riChan = nil
+				continue
+			}
+			resourceInstances = append(resourceInstances, &ri)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return resourceInstances, errors.Join(errs...)
+}
+
 // getRemainingResourceInstancesConcurrently fetches pages [startPage, numPages) with a
 // worker pool, using the total count from a HEAD request to know how many pages remain,
 // and appends them to the already-fetched first (which may be nil).
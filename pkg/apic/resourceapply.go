@@ -0,0 +1,408 @@
+package apic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+const maxDiffValueLength = 80
+
+// resourceFileExtensions - the extensions of the files read from a resource bundle directory
+var resourceFileExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// ApplyOption - an option for applying a bundle of resources
+type ApplyOption func(*applyOptions)
+
+type applyOptions struct {
+	pruneCreatedBy string
+}
+
+// WithPrune - deletes the resources, of the kinds and scopes in the bundle, that are not in the bundle and were created
+// by createdBy, the createdBy value of their x-agent-details
+func WithPrune(createdBy string) ApplyOption {
+	return func(o *applyOptions) {
+		o.pruneCreatedBy = createdBy
+	}
+}
+
+// ReadResourceFiles - reads the resources of the yaml and json files in the directory and its sub directories
+func ReadResourceFiles(dir string) ([]*apiv1.ResourceInstance, error) {
+	resources := []*apiv1.ResourceInstance{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !resourceFileExtensions[strings.ToLower(filepath.Ext(path))] {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fileResources, err := UnmarshalResources(data)
+		if err != nil {
+			return fmt.Errorf("could not read the resources of %s: %s", path, err)
+		}
+		resources = append(resources, fileResources...)
+		return nil
+	})
+	return resources, err
+}
+
+// UnmarshalResources - the resources of a json array, a json object or yaml documents, as written by MarshalResources
+func UnmarshalResources(data []byte) ([]*apiv1.ResourceInstance, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		resources := []*apiv1.ResourceInstance{}
+		return resources, json.Unmarshal(trimmed, &resources)
+	}
+
+	resources := []*apiv1.ResourceInstance{}
+	decoder := yamlv3.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		docJSON, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		ri := &apiv1.ResourceInstance{}
+		if err := json.Unmarshal(docJSON, ri); err != nil {
+			return nil, err
+		}
+		resources = append(resources, ri)
+	}
+	return resources, nil
+}
+
+// resourceRank - the order the kinds are applied in when the resources do not reference each other, the kinds
+// referenced by others first. The other kinds, i.e. assets or subscriptions, may reference the services and instances.
+func resourceRank(kind string) int {
+	switch {
+	case kind == management.EnvironmentGVK().Kind:
+		return 0
+	case strings.HasSuffix(kind, "Definition"):
+		return 1
+	case kind == management.APIServiceGVK().Kind:
+		return 2
+	case kind == management.APIServiceRevisionGVK().Kind:
+		return 3
+	case kind == management.APIServiceInstanceGVK().Kind:
+		return 4
+	}
+	return 5
+}
+
+// resourceKey - the key of a resource of the bundle, by kind, scope and name
+func resourceKey(kind, scopeName, name string) string {
+	return kind + "/" + scopeName + "/" + name
+}
+
+// resourceDependencies - the keys of the resources a resource depends on, its scope and the resources it references
+func resourceDependencies(ri *apiv1.ResourceInstance) []string {
+	deps := []string{}
+	scope := ri.Metadata.Scope
+	if scope.Name != "" {
+		scopeKind := scope.Kind
+		if scopeKind == "" {
+			scopeKind, _ = apiv1.GetScope(ri.GetGroupVersionKind().GroupKind)
+		}
+		deps = append(deps, resourceKey(scopeKind, "", scope.Name))
+	}
+	for _, ref := range ri.Metadata.References {
+		deps = append(deps, resourceKey(ref.Kind, ref.ScopeName, ref.Name))
+	}
+	return deps
+}
+
+// sortResourcesByDependency - sorts the resources so their scope and the resources they reference, when in the
+// resources, come first. The resources are otherwise ordered by the rank of their kind.
+func sortResourcesByDependency(resources []*apiv1.ResourceInstance) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resourceRank(resources[i].Kind) < resourceRank(resources[j].Kind)
+	})
+
+	index := map[string]int{}
+	for i, ri := range resources {
+		index[resourceKey(ri.Kind, ri.Metadata.Scope.Name, ri.Name)] = i
+	}
+
+	// each resource is taken in rank order, after the resources it depends on. The resources in a reference cycle
+	// are kept in rank order.
+	sorted := make([]*apiv1.ResourceInstance, 0, len(resources))
+	visited := make([]bool, len(resources))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, dep := range resourceDependencies(resources[i]) {
+			if j, found := index[dep]; found {
+				visit(j)
+			}
+		}
+		sorted = append(sorted, resources[i])
+	}
+	for i := range resources {
+		visit(i)
+	}
+	copy(resources, sorted)
+}
+
+// ApplyResources - creates the resources of the bundle that do not exist and updates the resources that changed, in
+// dependency order, with CreateOrUpdateResource. The changes are returned with the fields that differ, the resources that
+// did not change are not updated, so applying a bundle again makes no changes.
+func (c *ServiceClient) ApplyResources(resources []*apiv1.ResourceInstance, opts ...ApplyOption) ([]PlanChange, error) {
+	o := &applyOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	sorted := make([]*apiv1.ResourceInstance, len(resources))
+	copy(sorted, resources)
+	sortResourcesByDependency(sorted)
+
+	changes := []PlanChange{}
+	applied := map[string]bool{}
+	for _, resource := range sorted {
+		if resource.Name == "" {
+			return changes, fmt.Errorf("the %s resource titled %s has no name", resource.Kind, resource.Title)
+		}
+		if resource.Metadata.Scope.Name == "" {
+			if scope, _ := apiv1.GetScope(resource.GetGroupVersionKind().GroupKind); scope != "" {
+				resource.SetScopeName(c.cfg.GetEnvironmentName())
+			}
+		}
+		if applied[resource.GetSelfLink()] {
+			continue
+		}
+		applied[resource.GetSelfLink()] = true
+
+		change, err := c.applyResource(resource)
+		if err != nil {
+			return changes, err
+		}
+		changes = append(changes, change)
+	}
+
+	if o.pruneCreatedBy == "" {
+		return changes, nil
+	}
+	pruned, err := c.pruneResources(sorted, applied, o.pruneCreatedBy)
+	return append(changes, pruned...), err
+}
+
+func (c *ServiceClient) applyResource(resource *apiv1.ResourceInstance) (PlanChange, error) {
+	change := PlanChange{
+		Kind:  resource.Kind,
+		Name:  resource.Name,
+		Scope: resource.Metadata.Scope.Name,
+	}
+	logger := c.logger.WithField("resourceName", resource.Name).WithField("resourceKind", resource.Kind)
+
+	existing, err := c.getExistingResource(resource.GetSelfLink())
+	if err != nil {
+		return change, err
+	}
+
+	var existingRI *apiv1.ResourceInstance
+	subResources := map[string]interface{}{}
+	if existing == nil {
+		change.Action = PlanCreate
+		for name, value := range resource.SubResources {
+			subResources[name] = value
+		}
+	} else {
+		change.Action = PlanUpdate
+		change.Diff = diffResources(existing, resource)
+		if len(change.Diff) == 0 {
+			change.Action = PlanUnchanged
+			logger.Debug("the resource did not change")
+			return change, nil
+		}
+		existingRI = existing
+		for name, value := range resource.SubResources {
+			if diffs := diffValues(name, toJSONValue(existing.SubResources[name]), toJSONValue(value)); len(diffs) > 0 {
+				subResources[name] = value
+			}
+		}
+	}
+	// x-agent-details is set by CreateOrUpdateResource
+	delete(subResources, defs.XAgentDetails)
+
+	// the resource is updated even when only fields not in its spec hash, i.e. the attributes, changed
+	ri, err := c.CreateOrUpdateResource(resource, WithExistingResourceInstance(existingRI), withForceUpdate())
+	if err != nil {
+		return change, err
+	}
+	if len(subResources) > 0 {
+		if err := c.CreateSubResource(ri.ResourceMeta, subResources); err != nil {
+			return change, err
+		}
+	}
+	logger.WithField("action", change.Action).Info("applied the resource")
+	return change, nil
+}
+
+// getExistingResource - the resource at the self link, nil when it does not exist
+func (c *ServiceClient) getExistingResource(selfLink string) (*apiv1.ResourceInstance, error) {
+	response, err := c.executeAPI(http.MethodGet, c.createAPIServerURL(selfLink), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch response.Code {
+	case http.StatusOK:
+		ri := &apiv1.ResourceInstance{}
+		return ri, json.Unmarshal(response.Body, ri)
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, errors.New(readResponseErrors(response.Code, response.Body))
+}
+
+// pruneResources - deletes the resources created by createdBy, of the kinds and scopes of the bundle, that are not in
+// the bundle. The resources are deleted in the reverse order they are applied in.
+func (c *ServiceClient) pruneResources(resources []*apiv1.ResourceInstance, applied map[string]bool, createdBy string) ([]PlanChange, error) {
+	changes := []PlanChange{}
+	listed := map[string]bool{}
+	for i := len(resources) - 1; i >= 0; i-- {
+		kindLink := resources[i].GetKindLink()
+		if listed[kindLink] {
+			continue
+		}
+		listed[kindLink] = true
+
+		existing, err := c.GetAPIV1ResourceInstances(nil, c.createAPIServerURL(kindLink))
+		if err != nil {
+			return changes, err
+		}
+		for _, ri := range existing {
+			if applied[ri.GetSelfLink()] {
+				continue
+			}
+			if owner, _ := util.GetAgentDetailsValue(ri, defs.AttrCreatedBy); owner != createdBy {
+				continue
+			}
+			if err := c.DeleteResourceInstance(ri); err != nil {
+				return changes, err
+			}
+			c.logger.WithField("resourceName", ri.Name).WithField("resourceKind", ri.Kind).Info("pruned the resource")
+			changes = append(changes, PlanChange{
+				Action: PlanDelete,
+				Kind:   ri.Kind,
+				Name:   ri.Name,
+				Scope:  ri.Metadata.Scope.Name,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// diffResources - the fields of the resource that differ from the existing resource. Only the fields set on the
+// resource are compared, so the fields the API server adds are not reported.
+func diffResources(existing, resource *apiv1.ResourceInstance) []string {
+	diffs := []string{}
+	if resource.Title != existing.Title {
+		diffs = append(diffs, formatDiff("title", existing.Title, resource.Title))
+	}
+	if !util.StringSlicesEqualUnordered(existing.GetTags(), resource.GetTags()) {
+		diffs = append(diffs, formatDiff("tags", existing.GetTags(), resource.GetTags()))
+	}
+	diffs = append(diffs, diffValues("attributes", toJSONValue(existing.Attributes), toJSONValue(resource.Attributes))...)
+	diffs = append(diffs, diffValues("owner", toJSONValue(existing.Owner), toJSONValue(resource.Owner))...)
+	diffs = append(diffs, diffValues("spec", toJSONValue(existing.Spec), toJSONValue(resource.Spec))...)
+
+	names := make([]string, 0, len(resource.SubResources))
+	for name := range resource.SubResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		diffs = append(diffs, diffValues(name, toJSONValue(existing.SubResources[name]), toJSONValue(resource.SubResources[name]))...)
+	}
+	return diffs
+}
+
+// diffValues - the paths, under path, of the values set in value that differ in existing
+func diffValues(path string, existing, value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		existingMap, ok := existing.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		diffs := []string{}
+		for _, key := range keys {
+			diffs = append(diffs, diffValues(path+"."+key, existingMap[key], v[key])...)
+		}
+		return diffs
+	case []interface{}:
+		existingList, ok := existing.([]interface{})
+		if !ok || len(existingList) != len(v) {
+			break
+		}
+		diffs := []string{}
+		for i := range v {
+			diffs = append(diffs, diffValues(fmt.Sprintf("%s[%d]", path, i), existingList[i], v[i])...)
+		}
+		return diffs
+	case nil:
+		return nil
+	default:
+		if fmt.Sprint(existing) == fmt.Sprint(value) {
+			return nil
+		}
+	}
+	return []string{formatDiff(path, existing, value)}
+}
+
+func formatDiff(path string, existing, value interface{}) string {
+	return fmt.Sprintf("%s: %s -> %s", path, formatDiffValue(existing), formatDiffValue(value))
+}
+
+func formatDiffValue(value interface{}) string {
+	data, _ := json.Marshal(value)
+	if len(data) > maxDiffValueLength {
+		return string(data[:maxDiffValueLength-3]) + "..."
+	}
+	return string(data)
+}
+
+// toJSONValue - the value as decoded from json, so values of different types can be compared
+func toJSONValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var v interface{}
+	json.Unmarshal(data, &v)
+	return v
+}
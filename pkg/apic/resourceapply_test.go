@@ -0,0 +1,210 @@
+package apic
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+func resourceKinds(resources []*apiv1.ResourceInstance) []string {
+	kinds := []string{}
+	for _, ri := range resources {
+		kinds = append(kinds, ri.Kind)
+	}
+	return kinds
+}
+
+func TestReadResourceFiles(t *testing.T) {
+	client, _ := GetTestServiceClient()
+	dir := t.TempDir()
+	for _, format := range []ExportFormat{ExportYAML, ExportJSON} {
+		exporter := NewServiceExporter(client.cfg, filepath.Join(dir, string(format)), format)
+		_, err := exporter.ExportService(buildExportServiceBody(t))
+		assert.Nil(t, err)
+	}
+	// files of other types are ignored
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# resources"), 0644))
+
+	resources, err := ReadResourceFiles(dir)
+	assert.Nil(t, err)
+	assert.Len(t, resources, 8)
+	for _, ri := range resources {
+		assert.NotEmpty(t, ri.Name)
+		assert.NotEmpty(t, ri.Kind)
+	}
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("kind: [APIService"), 0644))
+	_, err = ReadResourceFiles(dir)
+	assert.NotNil(t, err)
+}
+
+func TestSortResourcesByDependency(t *testing.T) {
+	newResource := func(kind, name string) *apiv1.ResourceInstance {
+		return &apiv1.ResourceInstance{ResourceMeta: apiv1.ResourceMeta{GroupVersionKind: apiv1.GroupVersionKind{GroupKind: apiv1.GroupKind{Kind: kind}}, Name: name}}
+	}
+	// the service references the secret of the environment
+	service := newResource(management.APIServiceGVK().Kind, "service-2")
+	service.Metadata.References = []apiv1.Reference{{Kind: management.SecretGVK().Kind, Name: "credentials", ScopeName: "env"}}
+	credentials := newResource(management.SecretGVK().Kind, "credentials")
+	credentials.Metadata.Scope.Name = "env"
+
+	resources := []*apiv1.ResourceInstance{
+		newResource("AssetMapping", "mapping"),
+		newResource(management.APIServiceInstanceGVK().Kind, "instance"),
+		newResource(management.APIServiceRevisionGVK().Kind, "revision"),
+		newResource(management.APIServiceGVK().Kind, "service-1"),
+		newResource(management.AccessRequestDefinitionGVK().Kind, "ard"),
+		newResource(management.SecretGVK().Kind, "secret"),
+		service,
+		credentials,
+		newResource(management.EnvironmentGVK().Kind, "env"),
+	}
+	sortResourcesByDependency(resources)
+
+	names := []string{}
+	for _, ri := range resources {
+		names = append(names, ri.Name)
+	}
+	// the kinds that may reference the services and instances are applied after them, unless referenced
+	assert.Equal(t, []string{"env", "ard", "service-1", "credentials", "service-2", "revision", "instance", "mapping", "secret"}, names)
+}
+
+func TestApplyResources(t *testing.T) {
+	notFound := api.MockResponse{RespCode: http.StatusNotFound, RespData: "{}"}
+	existing := api.MockResponse{RespCode: http.StatusOK}
+
+	tests := map[string]struct {
+		responses []api.MockResponse
+		title     string
+		actions   []PlanAction
+		diff      []string
+	}{
+		"creates the resources that do not exist": {
+			responses: []api.MockResponse{notFound, notFound, notFound, notFound},
+			actions:   []PlanAction{PlanCreate, PlanCreate, PlanCreate, PlanCreate},
+		},
+		"does not change the resources that exist": {
+			responses: []api.MockResponse{existing, existing, existing, existing},
+			actions:   []PlanAction{PlanUnchanged, PlanUnchanged, PlanUnchanged, PlanUnchanged},
+		},
+		"updates the resources that changed": {
+			responses: []api.MockResponse{existing, existing, existing, existing},
+			title:     "Petstore",
+			actions:   []PlanAction{PlanUnchanged, PlanUpdate, PlanUnchanged, PlanUnchanged},
+			diff:      []string{`title: "" -> "Petstore"`},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, httpClient := GetTestServiceClient()
+			exporter := NewServiceExporter(client.cfg, t.TempDir(), ExportYAML)
+			resources, err := exporter.BuildServiceResources(buildExportServiceBody(t))
+			assert.Nil(t, err)
+
+			// the existing resources are the resources of the bundle before it changed
+			responses := make([]api.MockResponse, len(tc.responses))
+			for i, response := range tc.responses {
+				if response.RespCode == http.StatusOK {
+					data, _ := json.Marshal(resources[i])
+					response.RespData = string(data)
+				}
+				responses[i] = response
+			}
+			resources[1].Title = tc.title
+
+			// the changes are planned, so only the existing resources are read from the server
+			client.dryRun = newDryRunPlanner(client.logger)
+			httpClient.SetResponses(responses)
+
+			// the bundle is applied in dependency order whatever the order of the files
+			changes, err := client.ApplyResources([]*apiv1.ResourceInstance{resources[3], resources[2], resources[1], resources[0]})
+			assert.Nil(t, err)
+			assert.Len(t, httpClient.Requests, len(responses))
+
+			actions := []PlanAction{}
+			kinds := []string{}
+			for _, change := range changes {
+				actions = append(actions, change.Action)
+				kinds = append(kinds, change.Kind)
+			}
+			assert.Equal(t, tc.actions, actions)
+			assert.Equal(t, resourceKinds(resources), kinds)
+			assert.Equal(t, len(tc.diff), len(changes[1].Diff))
+			if len(tc.diff) > 0 {
+				assert.Equal(t, tc.diff, changes[1].Diff)
+			}
+		})
+	}
+}
+
+func TestApplyResourcesAttributes(t *testing.T) {
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = "Petstore"
+	existing, _ := svc.AsInstance()
+	existingData, _ := json.Marshal(existing)
+
+	client, httpClient := GetTestServiceClient()
+	httpClient.SetResponses([]api.MockResponse{
+		{RespCode: http.StatusOK, RespData: string(existingData)},
+		{RespCode: http.StatusOK, RespData: string(existingData)},
+		{RespCode: http.StatusOK, RespData: string(existingData)},
+	})
+
+	// only the attributes changed, not the spec hash, title or tags
+	resource, _ := svc.AsInstance()
+	resource.Attributes = map[string]string{"team": "pets"}
+	changes, err := client.ApplyResources([]*apiv1.ResourceInstance{resource})
+	assert.Nil(t, err)
+	assert.Equal(t, PlanUpdate, changes[0].Action)
+	assert.Equal(t, []string{`attributes: null -> {"team":"pets"}`}, changes[0].Diff)
+
+	assert.GreaterOrEqual(t, len(httpClient.Requests), 2)
+	assert.Equal(t, http.MethodPut, httpClient.Requests[1].Method)
+	sent := &apiv1.ResourceInstance{}
+	assert.Nil(t, json.Unmarshal(httpClient.Requests[1].Body, sent))
+	assert.Equal(t, map[string]string{"team": "pets"}, sent.Attributes)
+}
+
+func TestApplyResourcesPrune(t *testing.T) {
+	newService := func(name, createdBy string) *apiv1.ResourceInstance {
+		svc := management.NewAPIService(name, "v7envandcat")
+		util.SetAgentDetails(svc, map[string]interface{}{defs.AttrCreatedBy: createdBy})
+		ri, _ := svc.AsInstance()
+		return ri
+	}
+	petstore := newService("petstore", "discovery-agent")
+	existing, _ := json.Marshal([]*apiv1.ResourceInstance{
+		petstore,
+		newService("orders", "discovery-agent"),
+		newService("users", "other-agent"),
+	})
+	petstoreData, _ := json.Marshal(petstore)
+
+	client, httpClient := GetTestServiceClient()
+	client.dryRun = newDryRunPlanner(client.logger)
+	httpClient.SetResponses([]api.MockResponse{
+		{RespCode: http.StatusOK, RespData: string(petstoreData)},
+		{RespCode: http.StatusOK, RespData: string(existing)},
+	})
+
+	changes, err := client.ApplyResources([]*apiv1.ResourceInstance{petstore}, WithPrune("discovery-agent"))
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, PlanUnchanged, changes[0].Action)
+	assert.Equal(t, PlanChange{Action: PlanDelete, Kind: management.APIServiceGVK().Kind, Name: "orders", Scope: "v7envandcat"}, changes[1])
+
+	plan := client.GetDryRunPlan()
+	assert.Len(t, plan.Changes, 1)
+	assert.Equal(t, PlanDelete, plan.Changes[0].Action)
+	assert.Equal(t, "orders", plan.Changes[0].Name)
+}
@@ -0,0 +1,115 @@
+package apic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiclient "github.com/Axway/agent-sdk/pkg/apic/apiserver/clients/api/v1"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+)
+
+// ListOption - an option for the typed list calls of the client
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	query  apiclient.QueryNode
+	sort   string
+	fields []string
+}
+
+// WithListQuery - lists the resources matching the query, built with the query nodes of the api server client, as
+// apiclient.And(apiclient.TagsIn("prod"), apiclient.CreatedBetween(from, time.Time{}))
+func WithListQuery(query apiclient.QueryNode) ListOption {
+	return func(o *listOptions) {
+		o.query = query
+	}
+}
+
+// WithListSort - lists the resources sorted by the field, a dot separated path like metadata.audit.createTimestamp
+func WithListSort(field string, descending bool) ListOption {
+	return func(o *listOptions) {
+		order := "ASC"
+		if descending {
+			order = "DESC"
+		}
+		o.sort = fmt.Sprintf("%s,%s", field, order)
+	}
+}
+
+// WithListFields - lists only the fields of the resources, like name, tags or x-agent-details
+func WithListFields(fields ...string) ListOption {
+	return func(o *listOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+func newListOptions(opts []ListOption) *listOptions {
+	o := &listOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// queryParams - the query parameters of the list request
+func (o *listOptions) queryParams() map[string]string {
+	params := map[string]string{}
+	if o.query != nil {
+		params[QueryKey] = apiclient.QueryStringer{QueryNode: o.query}.String()
+	}
+	if o.sort != "" {
+		params[SortKey] = o.sort
+	}
+	if len(o.fields) > 0 {
+		params[FieldsKey] = strings.Join(o.fields, ",")
+	}
+	return params
+}
+
+// ListAPIV1ResourceInstances - the resources at the url matching the list options. Sorted lists are read a page at
+// a time with WalkAPIV1ResourceInstances so the order is kept, other lists are read as GetAPIV1ResourceInstances does.
+func (c *ServiceClient) ListAPIV1ResourceInstances(url string, opts ...ListOption) ([]*apiv1.ResourceInstance, error) {
+	o := newListOptions(opts)
+	if o.sort == "" {
+		return c.GetAPIV1ResourceInstances(o.queryParams(), url)
+	}
+
+	resources := []*apiv1.ResourceInstance{}
+	err := c.WalkAPIV1ResourceInstances(context.Background(), o.queryParams(), url, func(page []*apiv1.ResourceInstance) error {
+		resources = append(resources, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// ListAPIServices - the API services of the environment matching the list options
+func (c *ServiceClient) ListAPIServices(opts ...ListOption) ([]*management.APIService, error) {
+	resources, err := c.ListAPIV1ResourceInstances(c.cfg.GetServicesURL(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return management.APIServiceFromInstanceArray(resources)
+}
+
+// ListAPIServiceRevisions - the API service revisions of the environment matching the list options
+func (c *ServiceClient) ListAPIServiceRevisions(opts ...ListOption) ([]*management.APIServiceRevision, error) {
+	resources, err := c.ListAPIV1ResourceInstances(c.cfg.GetRevisionsURL(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return management.APIServiceRevisionFromInstanceArray(resources)
+}
+
+// ListAPIServiceInstances - the API service instances of the environment matching the list options
+func (c *ServiceClient) ListAPIServiceInstances(opts ...ListOption) ([]*management.APIServiceInstance, error) {
+	resources, err := c.ListAPIV1ResourceInstances(c.cfg.GetInstancesURL(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return management.APIServiceInstanceFromInstanceArray(resources)
+}
@@ -0,0 +1,72 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	apiclient "github.com/Axway/agent-sdk/pkg/apic/apiserver/clients/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+)
+
+func servicesResponse(t *testing.T, names ...string) api.MockResponse {
+	services := []*management.APIService{}
+	for _, name := range names {
+		services = append(services, management.NewAPIService(name, "v7envandcat"))
+	}
+	data, err := json.Marshal(services)
+	assert.Nil(t, err)
+	return api.MockResponse{RespCode: http.StatusOK, RespData: string(data)}
+}
+
+func TestListAPIServices(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	httpClient.SetResponses([]api.MockResponse{servicesResponse(t, "petstore", "orders")})
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	services, err := client.ListAPIServices(
+		WithListQuery(apiclient.And(apiclient.OwnerIn("team-id"), apiclient.CreatedBetween(from, time.Time{}))),
+		WithListFields("name", "tags"),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, services, 2)
+	assert.Equal(t, "petstore", services[0].Name)
+
+	assert.Len(t, httpClient.Requests, 1)
+	request := httpClient.Requests[0]
+	assert.Equal(t, client.cfg.GetServicesURL(), request.URL)
+	assert.Equal(t, `(owner.id=="team-id";metadata.audit.createTimestamp>="2024-01-02T00:00:00.000+0000")`, request.QueryParams[QueryKey])
+	assert.Equal(t, "name,tags", request.QueryParams[FieldsKey])
+	assert.NotContains(t, request.QueryParams, SortKey)
+}
+
+func TestListAPIV1ResourceInstancesSorted(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	GetTestServiceClientCentralConfiguration(client).PageSize = 2
+	httpClient.SetResponses([]api.MockResponse{
+		servicesResponse(t, "a", "b"),
+		servicesResponse(t, "c", "d"),
+		servicesResponse(t, "e"),
+	})
+
+	resources, err := client.ListAPIV1ResourceInstances(client.cfg.GetServicesURL(), WithListSort(CreateTimestampQueryKey, true))
+	assert.Nil(t, err)
+
+	// the pages are read in order, without counting the resources first
+	names := []string{}
+	for _, ri := range resources {
+		names = append(names, ri.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+	assert.Len(t, httpClient.Requests, 3)
+	for i, request := range httpClient.Requests {
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "metadata.audit.createTimestamp,DESC", request.QueryParams[SortKey])
+		assert.Equal(t, fmt.Sprint(i+1), request.QueryParams["page"])
+	}
+}
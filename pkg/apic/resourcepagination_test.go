@@ -1,6 +1,7 @@
 package apic
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -128,6 +129,136 @@ func TestGetAPIV1ResourceInstances_ConcurrentSmoke(t *testing.T) {
 	assert.Len(t, ris, 23)
 }
 
+// TestWalkAPIV1ResourceInstances reads the pages in order, one request per page and no HEAD
+// count call, stopping at the first page that is not full.
+func TestWalkAPIV1ResourceInstances(t *testing.T) {
+	const url = "/test"
+	client, httpClient := GetTestServiceClient()
+	client.setPageSizeIfSmaller(url, 5)
+
+	httpClient.SetResponses([]api.MockResponse{
+		itemsResponse("i0", "i1", "i2", "i3", "i4"),
+		itemsResponse("i5", "i6", "i7", "i8", "i9"),
+		itemsResponse("i10", "i11"),
+	})
+
+	pages := [][]string{}
+	err := client.WalkAPIV1ResourceInstances(context.Background(), map[string]string{}, url, func(page []*apiv1.ResourceInstance) error {
+		pages = append(pages, namesOf(page))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"i0", "i1", "i2", "i3", "i4"},
+		{"i5", "i6", "i7", "i8", "i9"},
+		{"i10", "i11"},
+	}, pages)
+	assert.Len(t, httpClient.Requests, 3)
+	for i, request := range httpClient.Requests {
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, strconv.Itoa(i+1), request.QueryParams["page"])
+	}
+}
+
+// TestWalkAPIV1ResourceInstances_RetryOnTimeout verifies a page that times out is read again
+// in halves, and the halved size is kept for the next pages.
+func TestWalkAPIV1ResourceInstances_RetryOnTimeout(t *testing.T) {
+	const url = "/test"
+	client, httpClient := GetTestServiceClient()
+	client.setPageSizeIfSmaller(url, 10)
+
+	httpClient.SetResponses([]api.MockResponse{
+		{RespCode: http.StatusRequestTimeout, ErrString: "context deadline exceeded"}, // page 0 size 10 - times out
+		itemsResponse("a0", "a1", "a2", "a3", "a4"),                                   // page 0 reduced sub-range 1, size 5
+		itemsResponse("a5", "a6", "a7", "a8", "a9"),                                   // page 0 reduced sub-range 2, size 5
+		itemsResponse("a10", "a11", "a12"),                                            // page 2 at size 5
+	})
+
+	names := []string{}
+	err := client.WalkAPIV1ResourceInstances(context.Background(), map[string]string{}, url, func(page []*apiv1.ResourceInstance) error {
+		names = append(names, namesOf(page)...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a0", "a1", "a2", "a3", "a4", "a5", "a6", "a7", "a8", "a9", "a10", "a11", "a12"}, names)
+	assert.Len(t, httpClient.Requests, 4)
+	assert.Equal(t, "3", httpClient.Requests[3].QueryParams["page"])
+
+	size, ok := client.getPageSize(url)
+	assert.True(t, ok)
+	assert.Equal(t, 5, size)
+}
+
+func TestWalkAPIV1ResourceInstances_Stops(t *testing.T) {
+	const url = "/test"
+
+	t.Run("when the context is done", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := client.WalkAPIV1ResourceInstances(ctx, map[string]string{}, url, func([]*apiv1.ResourceInstance) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, httpClient.Requests)
+	})
+
+	t.Run("when the handler returns an error", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		client.setPageSizeIfSmaller(url, 2)
+		httpClient.SetResponses([]api.MockResponse{itemsResponse("i0", "i1"), itemsResponse("i2")})
+		handlerErr := errors.New("handler error")
+
+		err := client.WalkAPIV1ResourceInstances(context.Background(), map[string]string{}, url, func([]*apiv1.ResourceInstance) error {
+			return handlerErr
+		})
+		assert.ErrorIs(t, err, handlerErr)
+		assert.Len(t, httpClient.Requests, 1)
+	})
+}
+
+func TestIterateAPIV1ResourceInstances(t *testing.T) {
+	const url = "/test"
+	client, httpClient := GetTestServiceClient()
+	client.setPageSizeIfSmaller(url, 2)
+	httpClient.SetResponses([]api.MockResponse{
+		itemsResponse("i0", "i1"),
+		itemsResponse("i2", "i3"),
+	})
+
+	// breaking out of the loop stops reading pages
+	names := []string{}
+	for ri, err := range client.IterateAPIV1ResourceInstances(context.Background(), map[string]string{}, url) {
+		assert.NoError(t, err)
+		names = append(names, ri.Name)
+		if len(names) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"i0", "i1", "i2"}, names)
+	assert.Len(t, httpClient.Requests, 2)
+
+	// an error reading a page ends the iteration
+	client, httpClient = GetTestServiceClient()
+	client.setPageSizeIfSmaller(url, 2)
+	httpClient.SetResponses([]api.MockResponse{
+		itemsResponse("i0", "i1"),
+		{RespCode: http.StatusInternalServerError, RespData: "{}"},
+	})
+	names = []string{}
+	var iterErr error
+	for ri, err := range client.IterateAPIV1ResourceInstances(context.Background(), map[string]string{}, url) {
+		if err != nil {
+			iterErr = err
+			continue
+		}
+		names = append(names, ri.Name)
+	}
+	assert.Equal(t, []string{"i0", "i1"}, names)
+	assert.Error(t, iterErr)
+}
+
 // TestGetAPIV1ResourceInstances_CountSmallerThanFirstPage covers a HEAD count that comes
 // back smaller than the already-fetched first page - e.g. the X-Axway-total-count header is
 // absent (GetAPIV1ResourceCount then returns 0, not an error) or items were deleted between
@@ -50,6 +50,13 @@ func (c *ServiceClient) PublishService(serviceBody *ServiceBody) (*management.AP
 		return nil, err
 	}
 
+	// do not publish a spec that failed validation in block mode
+	if report := serviceBody.specValidationReport; serviceBody.specValidationMode == SpecValidationBlock && report != nil && report.HasErrors() {
+		err := ErrSpecValidation.FormatError(serviceBody.APIName, report.summary())
+		logger.WithError(err).Error("error processing service")
+		return nil, err
+	}
+
 	// API Service
 	logger.Trace("processing service")
 	apiSvc, err := c.processService(serviceBody)
@@ -196,10 +203,17 @@ func (c *ServiceClient) executeAPIServiceAPI(method, url string, buffer []byte)
 		Headers:     headers,
 		Body:        buffer,
 	}
-	response, err := c.apiClient.Send(request)
+	var response *coreapi.Response
+	if c.dryRun != nil {
+		response = c.dryRun.send(request)
+	}
+	if response == nil {
+		response, err = c.apiClient.Send(request)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.checkRateLimit(response)
 	//  Check to see if rollback was processed
 	if method == http.MethodDelete && response.Code == http.StatusNoContent {
 		return nil, nil
@@ -207,6 +221,9 @@ func (c *ServiceClient) executeAPIServiceAPI(method, url string, buffer []byte)
 
 	if response.Code >= http.StatusBadRequest {
 		responseErr := readResponseErrors(response.Code, response.Body)
+		if response.Code == http.StatusTooManyRequests {
+			return nil, utilerrors.Wrap(ErrRateLimited, responseErr)
+		}
 		return nil, utilerrors.Wrap(ErrRequestQuery, responseErr)
 	}
 	ri := &v1.ResourceInstance{}
@@ -0,0 +1,215 @@
+package apic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	utilerrors "github.com/Axway/agent-sdk/pkg/util/errors"
+)
+
+const (
+	defaultPublishRetries    = 5
+	defaultPublishBackoff    = time.Second
+	defaultPublishMaxBackoff = time.Minute
+)
+
+// PublishResult - the result of publishing one of the service bodies passed to PublishServices
+type PublishResult struct {
+	ServiceBody *ServiceBody
+	APIService  *management.APIService
+	Err         error
+	// Attempts - the number of times the service body was published, retries are made when rate limited
+	Attempts int
+	// Duplicate - the service body has the same API, stage and spec hash as the service body at index DuplicateOf,
+	// which was published in its place. The result of that service body is copied to this one.
+	Duplicate   bool
+	DuplicateOf int
+}
+
+// PublishOption - an option for publishing a batch of services
+type PublishOption func(*publishOptions)
+
+type publishOptions struct {
+	workers       int
+	maxRetries    int
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	beforePublish []func(*ServiceBody) error
+}
+
+// WithPublishWorkers - the number of services published concurrently, defaults to the central.apiClientWorkers config
+func WithPublishWorkers(workers int) PublishOption {
+	return func(o *publishOptions) {
+		o.workers = workers
+	}
+}
+
+// WithPublishRetries - the number of retries when a service is rate limited, and the backoff between the retries, which
+// doubles with each retry up to maxBackoff
+func WithPublishRetries(maxRetries int, backoff, maxBackoff time.Duration) PublishOption {
+	return func(o *publishOptions) {
+		o.maxRetries = maxRetries
+		o.backoff = backoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithBeforePublish - adds a function called before each service is published, an error fails the service without publishing it
+func WithBeforePublish(beforePublish func(*ServiceBody) error) PublishOption {
+	return func(o *publishOptions) {
+		o.beforePublish = append(o.beforePublish, beforePublish)
+	}
+}
+
+// PublishServices - publishes the service bodies with a pool of workers, returning a result for each service body in the
+// same order. The service bodies for the same API are published in order by the same worker, and service bodies with
+// the same API, stage and spec hash as an earlier one are not published again. When Amplify Central rate limits the
+// requests the workers pause for the time it asks, and the rate limited service is retried with a backoff.
+func (c *ServiceClient) PublishServices(serviceBodies []*ServiceBody, opts ...PublishOption) []PublishResult {
+	o := &publishOptions{
+		workers:    c.apiClientWorkers,
+		maxRetries: defaultPublishRetries,
+		backoff:    defaultPublishBackoff,
+		maxBackoff: defaultPublishMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+
+	results := make([]PublishResult, len(serviceBodies))
+	groups := [][]int{}
+	groupIndexes := map[string]int{}
+	published := map[string]int{}
+	for i, serviceBody := range serviceBodies {
+		results[i] = PublishResult{ServiceBody: serviceBody}
+		if serviceBody == nil {
+			results[i].Err = errors.New("the service body is nil")
+			continue
+		}
+
+		apiKey := serviceBody.PrimaryKey
+		if apiKey == "" {
+			apiKey = serviceBody.RestAPIID
+		}
+		specKey := fmt.Sprintf("%s/%s/%s", apiKey, serviceBody.Stage, serviceBody.specHash)
+		if j, found := published[specKey]; found {
+			results[i].Duplicate = true
+			results[i].DuplicateOf = j
+			continue
+		}
+		published[specKey] = i
+
+		if g, found := groupIndexes[apiKey]; found {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		groupIndexes[apiKey] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	groupChan := make(chan []int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < o.workers && w < len(groups); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range groupChan {
+				for _, i := range group {
+					c.publishWithBackoff(&results[i], o)
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		groupChan <- group
+	}
+	close(groupChan)
+	wg.Wait()
+
+	for i := range results {
+		if results[i].Duplicate {
+			results[i].APIService = results[results[i].DuplicateOf].APIService
+			results[i].Err = results[results[i].DuplicateOf].Err
+		}
+	}
+	return results
+}
+
+func (c *ServiceClient) publishWithBackoff(result *PublishResult, o *publishOptions) {
+	logger := c.logger.WithField("serviceName", result.ServiceBody.NameToPush).WithField("apiID", result.ServiceBody.RestAPIID)
+	backoff := o.backoff
+	for {
+		c.waitForRateLimit()
+		result.Attempts++
+
+		var err error
+		for _, beforePublish := range o.beforePublish {
+			if err = beforePublish(result.ServiceBody); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			result.APIService, err = c.PublishService(result.ServiceBody)
+		}
+		result.Err = err
+		if err == nil || !isRateLimited(err) || result.Attempts > o.maxRetries {
+			return
+		}
+
+		logger.WithField("attempt", result.Attempts).WithField("backoff", backoff).Warn("publishing was rate limited, retrying")
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > o.maxBackoff {
+			backoff = o.maxBackoff
+		}
+	}
+}
+
+// checkRateLimit - records the time to pause the batch publishing for, from the Retry-After header of a rate limited response
+func (c *ServiceClient) checkRateLimit(response *coreapi.Response) {
+	if response == nil || response.Code != http.StatusTooManyRequests {
+		return
+	}
+	values := response.Headers[http.CanonicalHeaderKey("Retry-After")]
+	if len(values) == 0 {
+		return
+	}
+
+	var until time.Time
+	if seconds, err := strconv.Atoi(values[0]); err == nil {
+		until = time.Now().Add(time.Duration(seconds) * time.Second)
+	} else if date, err := http.ParseTime(values[0]); err == nil {
+		until = date
+	} else {
+		return
+	}
+	// the latest deadline is kept when rate limited responses are checked concurrently
+	for {
+		current := c.rateLimitedUntil.Load()
+		if until.UnixNano() <= current || c.rateLimitedUntil.CompareAndSwap(current, until.UnixNano()) {
+			return
+		}
+	}
+}
+
+// waitForRateLimit - waits until the time set by the last rate limited response
+func (c *ServiceClient) waitForRateLimit() {
+	if wait := time.Until(time.Unix(0, c.rateLimitedUntil.Load())); wait > 0 {
+		c.logger.WithField("wait", wait).Debug("waiting for the rate limit of Amplify Central")
+		time.Sleep(wait)
+	}
+}
+
+func isRateLimited(err error) bool {
+	agentErr := &utilerrors.AgentError{}
+	return errors.As(err, &agentErr) && agentErr.GetErrorCode() == ErrRateLimited.GetErrorCode()
+}
@@ -0,0 +1,163 @@
+package apic
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+)
+
+func TestPublishServices(t *testing.T) {
+	oas2Bytes, err := os.ReadFile(testPetstoreSpec)
+	assert.Nil(t, err)
+
+	rateLimited := api.MockResponse{RespData: `{}`, RespCode: http.StatusTooManyRequests}
+	fullPublish := []api.MockResponse{
+		{FileName: testAPIServiceFile, RespCode: http.StatusCreated}, // POST service
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // service source subresource
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // service x-agent-details subresource
+		emptyRevisionListResponse,                                    // GET revisions (getExistingRevision)
+		{FileName: testRevisionFile, RespCode: http.StatusCreated},   // POST revision
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // revision information subresource
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // revision x-agent-details subresource
+		{FileName: testInstanceFile, RespCode: http.StatusCreated},   // POST instance
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // instance x-agent-details subresource
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // instance source subresource
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // instance x-agent-details subresource
+		{FileName: testAgentDetailsFile, RespCode: http.StatusOK},    // final service x-agent-details subresource
+	}
+
+	tests := map[string]struct {
+		responses        []api.MockResponse
+		beforePublishErr error
+		expectErr        bool
+		expectAttempts   int
+	}{
+		"published after a rate limited attempt": {
+			responses:      append([]api.MockResponse{rateLimited}, fullPublish...),
+			expectAttempts: 2,
+		},
+		"rate limited until the retries are exhausted": {
+			responses:      []api.MockResponse{rateLimited, rateLimited, rateLimited},
+			expectErr:      true,
+			expectAttempts: 3,
+		},
+		"not published when before publish fails": {
+			beforePublishErr: errors.New("access request definition"),
+			expectErr:        true,
+			expectAttempts:   1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, httpClient := GetTestServiceClient()
+			httpClient.SetResponses(tc.responses)
+
+			body, err := NewServiceBodyBuilder().
+				SetAPIName(serviceBody.APIName).
+				SetResourceType(serviceBody.ResourceType).
+				SetID(serviceBody.RestAPIID).
+				SetAPISpec(oas2Bytes).
+				Build()
+			assert.Nil(t, err)
+			duplicate := body
+
+			beforePublishCalls := 0
+			results := client.PublishServices([]*ServiceBody{&body, &duplicate, nil},
+				WithPublishWorkers(1),
+				WithPublishRetries(2, time.Millisecond, time.Millisecond),
+				WithBeforePublish(func(*ServiceBody) error {
+					beforePublishCalls++
+					return tc.beforePublishErr
+				}),
+			)
+			assert.Len(t, results, 3)
+			assert.Equal(t, tc.expectAttempts, results[0].Attempts)
+			assert.Equal(t, tc.expectAttempts, beforePublishCalls)
+			if tc.expectErr {
+				assert.NotNil(t, results[0].Err)
+				assert.Nil(t, results[0].APIService)
+			} else {
+				assert.Nil(t, results[0].Err)
+				assert.NotNil(t, results[0].APIService)
+			}
+
+			// the duplicate is not published, it shares the result of the first service body
+			assert.True(t, results[1].Duplicate)
+			assert.Equal(t, 0, results[1].DuplicateOf)
+			assert.Equal(t, 0, results[1].Attempts)
+			assert.Equal(t, results[0].APIService, results[1].APIService)
+			assert.Equal(t, results[0].Err, results[1].Err)
+
+			assert.NotNil(t, results[2].Err)
+		})
+	}
+}
+
+func TestPublishServicesGroupsByAPI(t *testing.T) {
+	client, _ := GetTestServiceClient()
+	bodies := []*ServiceBody{
+		{RestAPIID: "a", Stage: "dev", specHash: "1"},
+		{RestAPIID: "b", specHash: "1"},
+		{RestAPIID: "a", Stage: "prod", specHash: "1"},
+		{RestAPIID: "a", Stage: "dev", specHash: "2"},
+		{RestAPIID: "b", specHash: "1"},
+	}
+
+	published := []string{}
+	results := client.PublishServices(bodies,
+		WithPublishWorkers(1),
+		WithBeforePublish(func(sb *ServiceBody) error {
+			published = append(published, sb.RestAPIID+"/"+sb.Stage+"/"+sb.specHash)
+			return errors.New("skip publishing")
+		}),
+	)
+	// the service bodies of API a are published in order, before those of API b
+	assert.Equal(t, []string{"a/dev/1", "a/prod/1", "a/dev/2", "b//1"}, published)
+	assert.True(t, results[4].Duplicate)
+	assert.Equal(t, 1, results[4].DuplicateOf)
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	client, _ := GetTestServiceClient()
+
+	client.checkRateLimit(&api.Response{Code: http.StatusOK, Headers: map[string][]string{"Retry-After": {"120"}}})
+	assert.Equal(t, int64(0), client.rateLimitedUntil.Load())
+
+	client.checkRateLimit(&api.Response{Code: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {"120"}}})
+	until := time.Unix(0, client.rateLimitedUntil.Load())
+	assert.WithinDuration(t, time.Now().Add(120*time.Second), until, 5*time.Second)
+
+	// an earlier time does not shorten the pause
+	client.checkRateLimit(&api.Response{Code: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {"1"}}})
+	assert.Equal(t, until.UnixNano(), client.rateLimitedUntil.Load())
+
+	date := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client.checkRateLimit(&api.Response{Code: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {date.Format(http.TimeFormat)}}})
+	assert.Equal(t, date.UnixNano(), client.rateLimitedUntil.Load())
+
+	// the latest deadline is kept whatever the order of concurrent responses
+	client, _ = GetTestServiceClient()
+	wg := sync.WaitGroup{}
+	for i := 1; i <= 50; i++ {
+		wg.Add(1)
+		go func(seconds int) {
+			defer wg.Done()
+			client.checkRateLimit(&api.Response{Code: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {strconv.Itoa(seconds * 60)}}})
+		}(i)
+	}
+	wg.Wait()
+	assert.WithinDuration(t, time.Now().Add(50*time.Minute), time.Unix(0, client.rateLimitedUntil.Load()), 5*time.Second)
+
+	_, err := client.ExecuteAPI(http.MethodGet, "http://foo.bar", nil, nil)
+	assert.False(t, isRateLimited(err))
+	assert.True(t, isRateLimited(ErrRateLimited))
+}
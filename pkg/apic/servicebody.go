@@ -57,6 +57,11 @@ type ServiceBody struct {
 	ignoreSpecBasesCreds         bool
 	stripOASExtensions           bool
 	stripOASServersBeforePublish bool
+	specBundle                   map[string][]byte
+	specValidationMode           SpecValidationMode
+	specValidationReport         *SpecValidationReport
+	computeAPIUpdateSeverity     bool
+	specDiff                     *SpecDiff
 	specHash                     string
 	specVersion                  string
 	accessRequestDefinition      *management.AccessRequestDefinition
@@ -154,6 +159,16 @@ func (s *ServiceBody) GetSpecVersion() string {
 	return s.specVersion
 }
 
+// GetSpecValidationReport - returns the report of the spec validation, nil when the spec was not validated
+func (s *ServiceBody) GetSpecValidationReport() *SpecValidationReport {
+	return s.specValidationReport
+}
+
+// GetSpecDiff - returns the changes from the spec of the previous revision, nil when they were not computed
+func (s *ServiceBody) GetSpecDiff() *SpecDiff {
+	return s.specDiff
+}
+
 // GetDataplaneType - returns dataplane type
 func (s *ServiceBody) GetDataplaneType() DataplaneType {
 	return s.dataplaneType
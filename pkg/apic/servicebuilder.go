@@ -37,6 +37,8 @@ type ServiceBuilder interface {
 	SetVersion(version string) ServiceBuilder
 	SetAuthPolicy(authPolicy string) ServiceBuilder
 	SetAPISpec(spec []byte) ServiceBuilder
+	SetSpecBundle(files map[string][]byte) ServiceBuilder
+	SetSpecArchive(archive []byte, specPath string) ServiceBuilder
 	SetDocumentation(documentation []byte) ServiceBuilder
 	SetTags(tags map[string]interface{}) ServiceBuilder
 	SetImage(image string) ServiceBuilder
@@ -44,6 +46,7 @@ type ServiceBuilder interface {
 	SetResourceType(resourceType string) ServiceBuilder
 	SetSubscriptionName(subscriptionName string) ServiceBuilder
 	SetAPIUpdateSeverity(apiUpdateSeverity string) ServiceBuilder
+	SetComputeAPIUpdateSeverity(compute bool) ServiceBuilder
 	SetState(state string) ServiceBuilder
 	SetStatus(status string) ServiceBuilder
 	SetServiceAttribute(serviceAttribute map[string]string) ServiceBuilder
@@ -57,6 +60,7 @@ type ServiceBuilder interface {
 	SetIgnoreSpecBasedCreds(ignore bool) ServiceBuilder
 	SetStripOASExtensions(strip bool) ServiceBuilder
 	SetStripOASServersBeforePublish() ServiceBuilder
+	SetSpecValidationMode(mode SpecValidationMode) ServiceBuilder
 
 	SetUnstructuredType(assetType string) ServiceBuilder
 	SetUnstructuredContentType(contentType string) ServiceBuilder
@@ -175,6 +179,24 @@ func (b *serviceBodyBuilder) SetAPISpec(spec []byte) ServiceBuilder {
 	return b
 }
 
+// SetSpecBundle - the files the spec references, keyed by their path relative to the spec, the bundle may also hold the spec itself
+func (b *serviceBodyBuilder) SetSpecBundle(files map[string][]byte) ServiceBuilder {
+	b.serviceBody.specBundle = files
+	return b
+}
+
+// SetSpecArchive - sets the spec, at specPath in the zip, tar or gzipped tar archive, and the files in its directory as the spec bundle
+func (b *serviceBodyBuilder) SetSpecArchive(archive []byte, specPath string) ServiceBuilder {
+	spec, bundle, err := readSpecArchive(archive, specPath)
+	if err != nil {
+		b.err = fmt.Errorf("could not read the spec archive for '%s': %s", b.serviceBody.APIName, err)
+		return b
+	}
+	b.serviceBody.SpecDefinition = spec
+	b.serviceBody.specBundle = bundle
+	return b
+}
+
 func (b *serviceBodyBuilder) SetDocumentation(documentation []byte) ServiceBuilder {
 	b.serviceBody.Documentation = documentation
 	return b
@@ -215,6 +237,11 @@ func (b *serviceBodyBuilder) SetAPIUpdateSeverity(apiUpdateSeverity string) Serv
 	return b
 }
 
+func (b *serviceBodyBuilder) SetComputeAPIUpdateSeverity(compute bool) ServiceBuilder {
+	b.serviceBody.computeAPIUpdateSeverity = compute
+	return b
+}
+
 func (b *serviceBodyBuilder) SetState(state string) ServiceBuilder {
 	b.serviceBody.State = state
 	return b
@@ -302,10 +329,15 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 	}
 
 	specParser := NewSpecResourceParser(b.serviceBody.SpecDefinition, b.serviceBody.ResourceType)
+	specParser.bundle = b.serviceBody.specBundle
 	err := specParser.Parse()
 	if err != nil {
 		return b.serviceBody, fmt.Errorf("failed to parse service specification for '%s': %s", b.serviceBody.APIName, err)
 	}
+	if specParser.inlinedSpec != nil {
+		// publish the spec with the files of the bundle inlined
+		b.serviceBody.SpecDefinition = specParser.inlinedSpec
+	}
 	specProcessor := specParser.GetSpecProcessor()
 	if b.serviceBody.ResourceContentType == "" {
 		b.serviceBody.ResourceContentType = specParser.getResourceContentType()
@@ -349,16 +381,24 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 		b.serviceBody.dataplaneType = "Unidentified"
 	}
 
-	var i interface{} = specProcessor
-	val, ok := i.(OasSpecProcessor)
+	// add any metadata from the spec to the revision, values set by the agent take precedence
+	if detailsProcessor, ok := specProcessor.(revisionDetailsProcessor); ok {
+		b.serviceBody.RevisionAgentDetails = util.MergeMapStringInterface(detailsProcessor.getRevisionDetails(), b.serviceBody.RevisionAgentDetails)
+	}
+
+	if err := b.validateSpec(specProcessor); err != nil {
+		return b.serviceBody, err
+	}
+
+	authProcessor, ok := specProcessor.(SpecAuthProcessor)
 	if !ok {
 		return b.serviceBody, nil
 	}
 
-	val.ParseAuthInfo()
+	authProcessor.ParseAuthInfo()
 
 	// get the auth policy from the spec
-	b.serviceBody.authPolicies = val.GetAuthPolicies()
+	b.serviceBody.authPolicies = authProcessor.GetAuthPolicies()
 
 	// use the first auth policy in the list as the AuthPolicy for determining if subscriptions are enabled
 	if len(b.serviceBody.authPolicies) > 0 {
@@ -366,10 +406,10 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 	}
 
 	// get the apikey info
-	b.serviceBody.apiKeyInfo = val.GetAPIKeyInfo()
+	b.serviceBody.apiKeyInfo = authProcessor.GetAPIKeyInfo()
 
 	// get oauth scopes
-	b.serviceBody.scopes = val.GetOAuthScopes()
+	b.serviceBody.scopes = authProcessor.GetOAuthScopes()
 
 	// if the spec has multiple then use the oauth ard
 	err = b.serviceBody.createAccessRequestDefinition()
@@ -377,23 +417,8 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 		return b.serviceBody, err
 	}
 
-	if b.serviceBody.stripOASExtensions {
-		val.StripExtensions()
-	}
-
-	if b.serviceBody.stripOASServersBeforePublish {
-		val.stripEndpoints()
-	}
-
-	if len(specParser.tagsToStrip) > 0 {
-		val.stripTags(specParser.tagsToStrip)
-	}
-
-	if b.serviceBody.stripOASServersBeforePublish || len(specParser.tagsToStrip) > 0 || b.serviceBody.stripOASExtensions {
-		b.serviceBody.originalSpecHash = b.serviceBody.specHash
-		b.serviceBody.SpecDefinition = val.GetSpecBytes()
-		newHash, _ := util.ComputeHash(val.GetSpecBytes())
-		b.serviceBody.specHash = fmt.Sprintf("%v", newHash)
+	if val, ok := specProcessor.(OasSpecProcessor); ok {
+		b.updateOASSpec(val, specParser.tagsToStrip)
 	}
 
 	// only set ard name based on spec if not already set, use first auth we find
@@ -419,6 +444,64 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 	return b.serviceBody, nil
 }
 
+// validateSpec - validates the spec, when a validation mode is set and the spec processor supports it, and handles the report based on the mode
+func (b *serviceBodyBuilder) validateSpec(specProcessor SpecProcessor) error {
+	mode := b.serviceBody.specValidationMode
+	validator, ok := specProcessor.(SpecValidator)
+	if mode == SpecValidationOff || !ok {
+		return nil
+	}
+
+	report := newSpecValidationReport(specProcessor.GetResourceType(), validator.ValidateSpec())
+	b.serviceBody.specValidationReport = report
+
+	switch mode {
+	case SpecValidationAttach:
+		// copy the details, the map set by the agent is not updated
+		details := make(map[string]interface{}, len(b.serviceBody.RevisionAgentDetails)+1)
+		for key, value := range b.serviceBody.RevisionAgentDetails {
+			details[key] = value
+		}
+		details[specValidationDetailKey] = report.toDetails()
+		b.serviceBody.RevisionAgentDetails = details
+	default:
+		for _, issue := range report.Issues {
+			b.serviceBody.logger.
+				WithField("apiName", b.serviceBody.APIName).
+				WithField("severity", issue.Severity).
+				WithField("location", issue.Location).
+				Warn(issue.Message)
+		}
+	}
+
+	if mode == SpecValidationBlock && report.HasErrors() {
+		return ErrSpecValidation.FormatError(b.serviceBody.APIName, report.summary())
+	}
+	return nil
+}
+
+// updateOASSpec - strips the configured content from the OAS spec and updates the spec hash
+func (b *serviceBodyBuilder) updateOASSpec(val OasSpecProcessor, tagsToStrip []string) {
+	if b.serviceBody.stripOASExtensions {
+		val.StripExtensions()
+	}
+
+	if b.serviceBody.stripOASServersBeforePublish {
+		val.stripEndpoints()
+	}
+
+	if len(tagsToStrip) > 0 {
+		val.stripTags(tagsToStrip)
+	}
+
+	if b.serviceBody.stripOASServersBeforePublish || len(tagsToStrip) > 0 || b.serviceBody.stripOASExtensions {
+		b.serviceBody.originalSpecHash = b.serviceBody.specHash
+		b.serviceBody.SpecDefinition = val.GetSpecBytes()
+		newHash, _ := util.ComputeHash(val.GetSpecBytes())
+		b.serviceBody.specHash = fmt.Sprintf("%v", newHash)
+	}
+}
+
 // SetCredentialRequestDefinitions -
 func (b *serviceBodyBuilder) SetCredentialRequestDefinitions(credentialRequestDefNames []string) ServiceBuilder {
 	b.serviceBody.credentialRequestPolicies = credentialRequestDefNames
@@ -448,6 +531,11 @@ func (b *serviceBodyBuilder) SetStripOASServersBeforePublish() ServiceBuilder {
 	return b
 }
 
+func (b *serviceBodyBuilder) SetSpecValidationMode(mode SpecValidationMode) ServiceBuilder {
+	b.serviceBody.specValidationMode = mode
+	return b
+}
+
 func (b *serviceBodyBuilder) SetStripOASExtensions(strip bool) ServiceBuilder {
 	b.serviceBody.stripOASExtensions = strip
 	return b
@@ -222,6 +222,92 @@ func TestServiceBodyWithParseError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestServiceBodyWithMCPManifest(t *testing.T) {
+	specBytes, err := os.ReadFile(filepath.Join("testdata", "mcp-server.json"))
+	assert.Nil(t, err)
+
+	sb, err := NewServiceBodyBuilder().
+		SetAPIName("weather").
+		SetVersion("1.0.0").
+		SetAPISpec(specBytes).
+		SetRevisionAgentDetails(map[string]interface{}{mcpPromptsDetailKey: "agent"}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, Mcp, sb.ResourceType)
+	// the revision version is the one set by the agent, not the version of the manifest
+	assert.Equal(t, "1.0.0", sb.GetSpecVersion())
+	assert.Len(t, sb.Endpoints, 2)
+	assert.Len(t, sb.RevisionAgentDetails[mcpToolsDetailKey], 1)
+	assert.Equal(t, "agent", sb.RevisionAgentDetails[mcpPromptsDetailKey])
+}
+
+func TestServiceBodyWithA2AAgentCard(t *testing.T) {
+	specBytes, err := os.ReadFile(filepath.Join("testdata", "a2a-agent-card.json"))
+	assert.Nil(t, err)
+
+	sb, err := NewServiceBodyBuilder().
+		SetAPIName("travel").
+		SetVersion("2.0.0").
+		SetAPISpec(specBytes).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, A2a, sb.ResourceType)
+	assert.Equal(t, "2.0.0", sb.GetSpecVersion())
+	assert.Len(t, sb.Endpoints, 3)
+	assert.Equal(t, []string{Apikey, Oauth}, sb.GetAuthPolicies())
+	assert.Equal(t, Apikey, sb.AuthPolicy)
+	assert.Len(t, sb.GetScopes(), 2)
+	assert.Len(t, sb.RevisionAgentDetails[a2aSkillsDetailKey], 2)
+}
+
+func TestServiceBodyWithWSDLBundle(t *testing.T) {
+	specBytes, err := os.ReadFile(filepath.Join("testdata", "wsdl-bundle", "pets.wsdl"))
+	assert.Nil(t, err)
+	bundle := map[string][]byte{}
+	for _, name := range []string{"pets.wsdl", "bindings/pets-bindings.wsdl", "schemas/pet.xsd", "schemas/common.xsd"} {
+		bundle[name], err = os.ReadFile(filepath.Join("testdata", "wsdl-bundle", name))
+		assert.Nil(t, err)
+	}
+
+	// the bindings of the ports are in the imported file
+	_, err = NewServiceBodyBuilder().
+		SetAPIName("pets").
+		SetAPISpec(specBytes).
+		SetSpecValidationMode(SpecValidationBlock).
+		Build()
+	assert.NotNil(t, err)
+
+	sb, err := NewServiceBodyBuilder().
+		SetAPIName("pets").
+		SetVersion("2.0").
+		SetAPISpec(specBytes).
+		SetSpecBundle(bundle).
+		SetSpecValidationMode(SpecValidationBlock).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, Wsdl, sb.ResourceType)
+	assert.Equal(t, "2.0", sb.GetSpecVersion())
+	assert.Len(t, sb.Endpoints, 3)
+	assert.Equal(t, "admin.pets.example.com", sb.Endpoints[2].Host)
+	assert.Equal(t, int32(443), sb.Endpoints[2].Port)
+
+	services, ok := sb.RevisionAgentDetails[wsdlServicesDetailKey].([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, services, 2)
+	ports := services[0].(WsdlService).Ports
+	assert.Equal(t, "1.1", ports[0].SOAPVersion)
+	assert.Equal(t, "1.2", ports[1].SOAPVersion)
+
+	// a file missing from the bundle is an error
+	delete(bundle, "schemas/common.xsd")
+	_, err = NewServiceBodyBuilder().
+		SetAPIName("pets").
+		SetAPISpec(specBytes).
+		SetSpecBundle(bundle).
+		Build()
+	assert.NotNil(t, err)
+}
+
 func TestServiceBodyBuilderWithLargeSpec(t *testing.T) {
 	// Setup test data
 	specPath := filepath.Join("testdata", "petstore-openapi3-large.json")
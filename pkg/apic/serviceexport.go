@@ -0,0 +1,167 @@
+package apic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/invopop/yaml"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// ExportFormat - the format of the resource files written by the ServiceExporter
+type ExportFormat string
+
+const (
+	ExportYAML ExportFormat = "yaml"
+	ExportJSON ExportFormat = "json"
+)
+
+const yamlDocumentSeparator = "---\n"
+
+// ServiceExporter - builds the API server resources PublishService would create for a service body, without
+// connecting to Amplify Central, and writes them to files that can be reviewed, version controlled and imported later
+type ServiceExporter struct {
+	client *ServiceClient
+	dir    string
+	format ExportFormat
+}
+
+// NewServiceExporter - creates an exporter writing the resource files to the directory, in yaml or json. The
+// environment, additional tags, revision title pattern and credential config are used as when publishing.
+func NewServiceExporter(cfg corecfg.CentralConfig, dir string, format ExportFormat) *ServiceExporter {
+	if format != ExportJSON {
+		format = ExportYAML
+	}
+	return &ServiceExporter{
+		// an offline client, without caches or an api client, only used to build the resources
+		client: &ServiceClient{
+			cfg: cfg,
+			logger: log.NewFieldLogger().
+				WithComponent("serviceExporter").
+				WithPackage("sdk.apic"),
+		},
+		dir:    dir,
+		format: format,
+	}
+}
+
+// BuildServiceResources - the access request definition, APIService, APIServiceRevision and APIServiceInstance
+// resources of the service body, in the order they are created. The service is named after the API name and the
+// revision after the instance and the spec hash, as Amplify Central does not name them offline.
+func (e *ServiceExporter) BuildServiceResources(serviceBody *ServiceBody) ([]*apiv1.ResourceInstance, error) {
+	c := e.client
+	resources := []apiv1.Interface{}
+
+	if ard := serviceBody.GetAccessRequestDefinition(); ard != nil {
+		if ard.Metadata.Scope.Name == "" {
+			ard.SetScopeName(c.cfg.GetEnvironmentName())
+		}
+		serviceBody.SetAccessRequestDefinitionName(ard.Name, true)
+		resources = append(resources, ard)
+	}
+
+	serviceName := sanitizeAPIName(serviceBody.APIName)
+	if serviceName == "" {
+		serviceName = sanitizeAPIName(serviceBody.RestAPIID)
+	}
+	if serviceName == "" {
+		return nil, fmt.Errorf("could not export the service, it has no API name or ID")
+	}
+	serviceBody.serviceContext = serviceContext{
+		serviceAction: addAPI,
+		serviceName:   serviceName,
+		revisionCount: 1,
+	}
+
+	svc := c.buildAPIService(serviceBody)
+	svc.Name = serviceName
+	addSpecHashToResource(svc)
+	resources = append(resources, svc)
+	c.postAPIServiceUpdate(serviceBody)
+
+	revision := c.buildAPIServiceRevision(serviceBody)
+	revision.Name = getRevisionPrefix(serviceBody)
+	if serviceBody.specHash != "" {
+		revision.Name = fmt.Sprintf("%s-%s", revision.Name, serviceBody.specHash)
+	}
+	revision.Information = &management.ApiServiceRevisionInformation{Hash: serviceBody.specHash}
+	addSpecHashToResource(revision)
+	resources = append(resources, revision)
+	serviceBody.serviceContext.revisionName = revision.Name
+
+	if !serviceBody.IsRevisionOnly() {
+		endpoints, err := createInstanceEndpoint(serviceBody.Endpoints)
+		if err != nil {
+			return nil, err
+		}
+		instance := c.buildAPIServiceInstance(serviceBody, getRevisionPrefix(serviceBody), endpoints)
+		addSpecHashToResource(instance)
+		resources = append(resources, instance)
+		serviceBody.serviceContext.instanceName = instance.Name
+	}
+
+	instances := make([]*apiv1.ResourceInstance, 0, len(resources))
+	for _, resource := range resources {
+		ri, err := resource.AsInstance()
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, ri)
+	}
+	return instances, nil
+}
+
+// ExportService - writes the resources of the service body to a file in the directory of the exporter, named after
+// the instance, and returns the path of the file
+func (e *ServiceExporter) ExportService(serviceBody *ServiceBody) (string, error) {
+	resources, err := e.BuildServiceResources(serviceBody)
+	if err != nil {
+		return "", err
+	}
+	data, err := MarshalResources(resources, e.format)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(e.dir, fmt.Sprintf("%s.%s", getRevisionPrefix(serviceBody), e.format))
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return "", err
+	}
+	e.client.logger.
+		WithField("serviceName", serviceBody.serviceContext.serviceName).
+		WithField("file", file).
+		Info("exported the service resources")
+	return file, nil
+}
+
+// MarshalResources - the resources in the API server format, as a json array or as yaml documents
+func MarshalResources(resources []*apiv1.ResourceInstance, format ExportFormat) ([]byte, error) {
+	if format == ExportJSON {
+		return json.MarshalIndent(resources, "", "  ")
+	}
+
+	buf := &bytes.Buffer{}
+	for _, resource := range resources {
+		data, err := json.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := yaml.JSONToYAML(data)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(yamlDocumentSeparator)
+		buf.Write(doc)
+	}
+	return buf.Bytes(), nil
+}
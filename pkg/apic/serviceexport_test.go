@@ -0,0 +1,111 @@
+package apic
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/invopop/yaml"
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+	"github.com/Axway/agent-sdk/pkg/apic/provisioning"
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+func buildExportServiceBody(t *testing.T) *ServiceBody {
+	oas2Bytes, err := os.ReadFile(testPetstoreSpec)
+	assert.Nil(t, err)
+	body, err := NewServiceBodyBuilder().
+		SetAPIName("Swagger Petstore").
+		SetID("petstore-id").
+		SetStage("prod").
+		SetAuthPolicy(Apikey).
+		SetAPISpec(oas2Bytes).
+		SetServiceEndpoints([]EndpointDefinition{{Host: "petstore.swagger.io", Port: 443, Protocol: "https", BasePath: "/v2"}}).
+		Build()
+	assert.Nil(t, err)
+	return &body
+}
+
+func TestBuildServiceResources(t *testing.T) {
+	client, _ := GetTestServiceClient()
+	exporter := NewServiceExporter(client.cfg, t.TempDir(), ExportYAML)
+	body := buildExportServiceBody(t)
+
+	resources, err := exporter.BuildServiceResources(body)
+	assert.Nil(t, err)
+	assert.Len(t, resources, 4)
+
+	// the petstore spec has oauth scopes, so the service has an access request definition
+	ard := &management.AccessRequestDefinition{}
+	assert.Nil(t, ard.FromInstance(resources[0]))
+	assert.NotEmpty(t, ard.Name)
+	assert.Equal(t, "v7envandcat", ard.Metadata.Scope.Name)
+
+	svc := &management.APIService{}
+	assert.Nil(t, svc.FromInstance(resources[1]))
+	assert.Equal(t, "swagger-petstore", svc.Name)
+	assert.Equal(t, "v7envandcat", svc.Metadata.Scope.Name)
+	assert.Nil(t, svc.Owner)
+	apiID, _ := util.GetAgentDetailsValue(svc, defs.AttrExternalAPIID)
+	assert.Equal(t, "petstore-id", apiID)
+
+	revision := &management.APIServiceRevision{}
+	assert.Nil(t, revision.FromInstance(resources[2]))
+	assert.Equal(t, "swagger-petstore-prod-"+body.specHash, revision.Name)
+	assert.Equal(t, svc.Name, revision.Spec.ApiService)
+	assert.Equal(t, body.specHash, revision.Information.Hash)
+	specHash, _ := util.GetAgentDetailsValue(revision, defs.AttrSpecHash)
+	assert.NotEmpty(t, specHash)
+
+	instance := &management.APIServiceInstance{}
+	assert.Nil(t, instance.FromInstance(resources[3]))
+	assert.Equal(t, "swagger-petstore-prod", instance.Name)
+	assert.Equal(t, revision.Name, instance.Spec.ApiServiceRevision)
+	assert.Equal(t, ard.Name, instance.Spec.AccessRequestDefinition)
+	assert.Contains(t, instance.Spec.CredentialRequestDefinitions, provisioning.APIKeyCRD)
+	assert.Len(t, instance.Spec.Endpoint, 1)
+
+	// without an API name or ID the service can not be named
+	_, err = exporter.BuildServiceResources(&ServiceBody{})
+	assert.NotNil(t, err)
+}
+
+func TestExportService(t *testing.T) {
+	for _, format := range []ExportFormat{ExportYAML, ExportJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			client, _ := GetTestServiceClient()
+			dir := filepath.Join(t.TempDir(), "export")
+			exporter := NewServiceExporter(client.cfg, dir, format)
+
+			file, err := exporter.ExportService(buildExportServiceBody(t))
+			assert.Nil(t, err)
+			assert.Equal(t, filepath.Join(dir, "swagger-petstore-prod."+string(format)), file)
+
+			data, err := os.ReadFile(file)
+			assert.Nil(t, err)
+			resources := []*apiv1.ResourceInstance{}
+			if format == ExportJSON {
+				assert.Nil(t, json.Unmarshal(data, &resources))
+			} else {
+				docs := bytes.Split(data, []byte(yamlDocumentSeparator))
+				for _, doc := range docs[1:] {
+					ri := &apiv1.ResourceInstance{}
+					assert.Nil(t, yaml.Unmarshal(doc, ri))
+					resources = append(resources, ri)
+				}
+			}
+
+			kinds := []string{}
+			for _, ri := range resources {
+				kinds = append(kinds, ri.Kind)
+			}
+			assert.Equal(t, []string{management.AccessRequestDefinitionGVK().Kind, management.APIServiceGVK().Kind, management.APIServiceRevisionGVK().Kind, management.APIServiceInstanceGVK().Kind}, kinds)
+		})
+	}
+}
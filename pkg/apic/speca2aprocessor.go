@@ -1,29 +1,238 @@
 package apic
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/util"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// A2A transports found in agent cards
+const (
+	A2ATransportJSONRPC  = "JSONRPC"
+	A2ATransportGRPC     = "GRPC"
+	A2ATransportHTTPJSON = "HTTP+JSON"
+)
+
+const (
+	a2aSkillsDetailKey       = "a2aSkills"
+	a2aCapabilitiesDetailKey = "a2aCapabilities"
+	a2aTransportDetailKey    = "transport"
+	a2aSecurityOpenIDConnect = "openIdConnect"
+)
+
+// A2ASkill - a skill the agent can perform
+type A2ASkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
+}
+
+// A2AInterface - a url and transport combination the agent is reachable on
+type A2AInterface struct {
+	URL       string `json:"url"`
+	Transport string `json:"transport"`
+}
+
+// A2ACapabilities - the optional capabilities supported by the agent
+type A2ACapabilities struct {
+	Streaming              bool `json:"streaming,omitempty"`
+	PushNotifications      bool `json:"pushNotifications,omitempty"`
+	StateTransitionHistory bool `json:"stateTransitionHistory,omitempty"`
+}
+
+// a2aAgentCard - the Agent2Agent agent card
+type a2aAgentCard struct {
+	ProtocolVersion      string                              `json:"protocolVersion,omitempty"`
+	Name                 string                              `json:"name"`
+	Description          string                              `json:"description,omitempty"`
+	Version              string                              `json:"version,omitempty"`
+	URL                  string                              `json:"url"`
+	PreferredTransport   string                              `json:"preferredTransport,omitempty"`
+	AdditionalInterfaces []A2AInterface                      `json:"additionalInterfaces,omitempty"`
+	Capabilities         *A2ACapabilities                    `json:"capabilities,omitempty"`
+	SecuritySchemes      map[string]*openapi3.SecurityScheme `json:"securitySchemes,omitempty"`
+	Security             []map[string][]string               `json:"security,omitempty"`
+	DefaultInputModes    []string                            `json:"defaultInputModes,omitempty"`
+	DefaultOutputModes   []string                            `json:"defaultOutputModes,omitempty"`
+	Skills               []A2ASkill                          `json:"skills,omitempty"`
+}
+
 type a2aProcessor struct {
-	spec []byte
+	card         *a2aAgentCard
+	spec         []byte
+	scopes       map[string]string
+	authPolicies []string
+	apiKeyInfo   []APIKeyInfo
+}
+
+func newA2ASpecProcessor(card *a2aAgentCard, resourceSpec []byte) *a2aProcessor {
+	return &a2aProcessor{card: card, spec: resourceSpec}
+}
+
+// parseA2AAgentCard - unmarshal the agent card
+func parseA2AAgentCard(spec []byte) (*a2aAgentCard, error) {
+	card := &a2aAgentCard{}
+	if err := json.Unmarshal(spec, card); err != nil {
+		return nil, fmt.Errorf("invalid a2a agent card: %s", err)
+	}
+	if card.Name == "" {
+		return nil, fmt.Errorf("invalid a2a agent card: 'name' key not found")
+	}
+	return card, nil
 }
 
-func newA2ASpecProcessor(resourceSpec []byte) SpecProcessor {
-	return &a2aProcessor{spec: resourceSpec}
+// isA2AAgentCard - returns true when the parsed yaml or json document looks like an agent card
+func isA2AAgentCard(specDef map[string]interface{}) bool {
+	if _, ok := specDef["skills"].([]interface{}); !ok {
+		return false
+	}
+	if _, ok := specDef["protocolVersion"]; ok {
+		return true
+	}
+	if _, ok := specDef["capabilities"].(map[string]interface{}); ok {
+		return true
+	}
+	_, ok := specDef["url"].(string)
+	return ok
 }
 
 func (p *a2aProcessor) GetResourceType() string {
 	return A2a
 }
 
+// GetVersion -
 func (p *a2aProcessor) GetVersion() string {
-	return ""
+	return p.card.Version
 }
 
+// GetDescription -
 func (p *a2aProcessor) GetDescription() string {
-	return ""
+	return p.card.Description
 }
 
+// GetTitle -
+func (p *a2aProcessor) GetTitle() string {
+	return p.card.Name
+}
+
+// GetEndpoints - an endpoint for the agent url and each of the additional interfaces
 func (p *a2aProcessor) GetEndpoints() ([]EndpointDefinition, error) {
-	return []EndpointDefinition{}, nil
+	interfaces := []A2AInterface{}
+	if p.card.URL != "" {
+		transport := p.card.PreferredTransport
+		if transport == "" {
+			transport = A2ATransportJSONRPC
+		}
+		interfaces = append(interfaces, A2AInterface{URL: p.card.URL, Transport: transport})
+	}
+	interfaces = append(interfaces, p.card.AdditionalInterfaces...)
+
+	urls := []endpointURL{}
+	for _, i := range interfaces {
+		if i.URL == "" {
+			continue
+		}
+		// grpc interfaces may be given as host:port
+		urls = append(urls, endpointURL{url: i.URL, details: map[string]interface{}{a2aTransportDetailKey: i.Transport}})
+	}
+	return endpointsFromURLs(urls, "https", 0)
+}
+
+// GetSkills -
+func (p *a2aProcessor) GetSkills() []A2ASkill {
+	return p.card.Skills
+}
+
+// ParseAuthInfo - maps the agent card security schemes to the auth policies used for credential request definitions
+func (p *a2aProcessor) ParseAuthInfo() {
+	p.authPolicies = []string{}
+	p.apiKeyInfo = []APIKeyInfo{}
+	p.scopes = make(map[string]string)
+
+	for _, name := range util.OrderedKeys(p.card.SecuritySchemes) {
+		scheme := p.card.SecuritySchemes[name]
+		if scheme == nil {
+			continue
+		}
+		switch scheme.Type {
+		case oasSecurityHttp:
+			if strings.EqualFold(scheme.Scheme, oasSecurityBasic) {
+				p.authPolicies = append(p.authPolicies, Basic)
+			}
+		case oasSecurityAPIKey:
+			p.authPolicies = append(p.authPolicies, Apikey)
+			p.apiKeyInfo = append(p.apiKeyInfo, APIKeyInfo{
+				Location: scheme.In,
+				Name:     scheme.Name,
+			})
+		case oasSecurityOauth:
+			p.authPolicies = append(p.authPolicies, Oauth)
+			if scheme.Flows != nil {
+				for _, flow := range []*openapi3.OAuthFlow{scheme.Flows.ClientCredentials, scheme.Flows.AuthorizationCode, scheme.Flows.Implicit, scheme.Flows.Password} {
+					if flow != nil {
+						p.scopes = util.MergeMapStringString(p.scopes, flow.Scopes)
+					}
+				}
+			}
+		case a2aSecurityOpenIDConnect:
+			p.authPolicies = append(p.authPolicies, Oauth)
+		}
+	}
+
+	// add the scopes the agent requires that are not described on the scheme
+	for _, requirement := range p.card.Security {
+		for _, scopes := range requirement {
+			for _, scope := range scopes {
+				if _, found := p.scopes[scope]; !found {
+					p.scopes[scope] = ""
+				}
+			}
+		}
+	}
+	p.authPolicies = util.RemoveDuplicateValuesFromStringSlice(p.authPolicies)
+	sort.Strings(p.authPolicies)
+}
+
+// GetAuthPolicies -
+func (p *a2aProcessor) GetAuthPolicies() []string {
+	return p.authPolicies
+}
+
+// GetOAuthScopes -
+func (p *a2aProcessor) GetOAuthScopes() map[string]string {
+	return p.scopes
+}
+
+// GetAPIKeyInfo -
+func (p *a2aProcessor) GetAPIKeyInfo() []APIKeyInfo {
+	return p.apiKeyInfo
+}
+
+// getRevisionDetails - the skill and capability metadata to add to the revision x-agent-details
+func (p *a2aProcessor) getRevisionDetails() map[string]interface{} {
+	details := map[string]interface{}{}
+	if len(p.card.Skills) > 0 {
+		details[a2aSkillsDetailKey] = toDetailList(p.card.Skills)
+	}
+	if p.card.Capabilities != nil {
+		details[a2aCapabilitiesDetailKey] = map[string]interface{}{
+			"streaming":              p.card.Capabilities.Streaming,
+			"pushNotifications":      p.card.Capabilities.PushNotifications,
+			"stateTransitionHistory": p.card.Capabilities.StateTransitionHistory,
+		}
+	}
+	return details
 }
 
+// GetSpecBytes -
 func (p *a2aProcessor) GetSpecBytes() []byte {
 	return p.spec
 }
@@ -4,6 +4,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/util"
 )
 
 type asyncAPIProcessor struct {
@@ -83,16 +85,23 @@ func (p *asyncAPIProcessor) parseServerObject(serverObjInterface map[string]inte
 	var err error
 	protocol := ""
 	serverURL := ""
+	host := ""
+	pathname := ""
 	var serverVariables map[string]string
 	serverDetails := map[string]interface{}{}
 	for key, valueInterface := range serverObjInterface {
 		value, ok := valueInterface.(string)
 		if ok {
-			if key == "protocol" {
+			switch key {
+			case "protocol":
 				protocol = value
-			}
-			if key == "url" {
+			case "url":
 				serverURL = value
+			case "host":
+				// asyncapi 3.0 splits the server url into host and pathname
+				host = value
+			case "pathname":
+				pathname = value
 			}
 		}
 		if key == "variables" {
@@ -105,6 +114,9 @@ func (p *asyncAPIProcessor) parseServerObject(serverObjInterface map[string]inte
 			serverDetails = valueInterface.(map[string]interface{})
 		}
 	}
+	if host != "" {
+		serverURL = host + pathname
+	}
 	endpoint := EndpointDefinition{}
 	endpoint.Protocol = protocol
 	// variable substitution
@@ -150,3 +162,91 @@ func (p *asyncAPIProcessor) parseVariableObject(serverObjInterface map[string]in
 func (p *asyncAPIProcessor) GetSpecBytes() []byte {
 	return p.spec
 }
+
+// ValidateSpec - validates the required fields, servers, operations and local references of the spec
+func (p *asyncAPIProcessor) ValidateSpec() []SpecValidationIssue {
+	issues := specIssues{}
+	version, _ := p.asyncapiDef["asyncapi"].(string)
+	if version == "" {
+		issues.addError("#/asyncapi", "the asyncapi version must be a non-empty string")
+	}
+	isV3 := strings.HasPrefix(version, "3.")
+
+	info, ok := p.asyncapiDef["info"].(map[string]interface{})
+	if !ok {
+		issues.addError("#/info", "the info object must be provided")
+		info = map[string]interface{}{}
+	}
+	for _, field := range []string{"title", "version"} {
+		if value, _ := info[field].(string); value == "" {
+			issues.addError("#/info/"+field, "value of %s must be a non-empty string", field)
+		}
+	}
+
+	components, _ := p.asyncapiDef["components"].(map[string]interface{})
+	securitySchemes, _ := components["securitySchemes"].(map[string]interface{})
+	servers, _ := p.asyncapiDef["servers"].(map[string]interface{})
+	for _, name := range util.OrderedKeys(servers) {
+		location := "#/servers/" + escapeJSONPointer(name)
+		server, ok := servers[name].(map[string]interface{})
+		if !ok {
+			issues.addError(location, "the server must be an object")
+			continue
+		}
+		if protocol, _ := server["protocol"].(string); protocol == "" {
+			issues.addError(location+"/protocol", "the server protocol must be provided")
+		}
+		addressField := "url"
+		if isV3 {
+			addressField = "host"
+		}
+		if address, _ := server[addressField].(string); address == "" {
+			issues.addError(location+"/"+addressField, "the server %s must be provided", addressField)
+		}
+		if isV3 {
+			continue
+		}
+		// asyncapi 2.x server security requirements refer to the security schemes by name
+		requirements, _ := server["security"].([]interface{})
+		for _, r := range requirements {
+			requirement, _ := r.(map[string]interface{})
+			for _, scheme := range util.OrderedKeys(requirement) {
+				if _, found := securitySchemes[scheme]; !found {
+					issues.addError(location+"/security", "security scheme %s is not defined", scheme)
+				}
+			}
+		}
+	}
+
+	if isV3 {
+		operations, _ := p.asyncapiDef["operations"].(map[string]interface{})
+		for _, name := range util.OrderedKeys(operations) {
+			operation, _ := operations[name].(map[string]interface{})
+			if action, _ := operation["action"].(string); action != "send" && action != "receive" {
+				issues.addError("#/operations/"+escapeJSONPointer(name)+"/action", "the operation action must be send or receive, found %q", action)
+			}
+		}
+	} else {
+		operationIDs := map[string]string{}
+		channels, _ := p.asyncapiDef["channels"].(map[string]interface{})
+		for _, name := range util.OrderedKeys(channels) {
+			channel, _ := channels[name].(map[string]interface{})
+			for _, action := range []string{"publish", "subscribe"} {
+				operation, _ := channel[action].(map[string]interface{})
+				operationID, _ := operation["operationId"].(string)
+				if operationID == "" {
+					continue
+				}
+				location := "#/channels/" + escapeJSONPointer(name) + "/" + action
+				if other, found := operationIDs[operationID]; found {
+					issues.addError(location+"/operationId", "operation id %s is also used by %s", operationID, other)
+					continue
+				}
+				operationIDs[operationID] = location
+			}
+		}
+	}
+
+	validateLocalRefs(p.asyncapiDef, &issues)
+	return issues
+}
@@ -0,0 +1,489 @@
+package apic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/util"
+	"github.com/invopop/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	ramlHeaderPrefix = "#%RAML"
+	ramlIncludeTag   = "!include"
+	// the limits of the decompressed files of a spec archive
+	maxArchiveSize  = tenMB
+	maxArchiveFiles = 1000
+)
+
+// invalidSchemaNameChars - the characters not allowed in the names of the component schemas
+var invalidSchemaNameChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+// ramlYAMLExtensions - the included RAML files that are parsed and inlined as yaml, other files are inlined as strings
+var ramlYAMLExtensions = map[string]bool{".raml": true, ".yaml": true, ".yml": true, ".json": true}
+
+// specBundleResolver - inlines the files referenced by a spec, with $ref or the RAML !include tag, from the bundle of files
+type specBundleResolver struct {
+	files     map[string][]byte
+	docs      map[string]interface{}
+	resolving map[string]bool
+	inlined   bool
+	// the recursive schemas, which can not be inlined, are added to the schemas of the spec
+	schemasRef   string
+	schemaNames  map[string]bool
+	recursive    map[string]string
+	recursiveIDs map[string]bool
+	schemas      map[string]interface{}
+}
+
+func newSpecBundleResolver(bundle map[string][]byte) *specBundleResolver {
+	r := &specBundleResolver{
+		files:        map[string][]byte{},
+		docs:         map[string]interface{}{},
+		resolving:    map[string]bool{},
+		schemaNames:  map[string]bool{},
+		recursive:    map[string]string{},
+		recursiveIDs: map[string]bool{},
+		schemas:      map[string]interface{}{},
+	}
+	for name, data := range bundle {
+		r.files[cleanBundlePath(name)] = data
+	}
+	return r
+}
+
+// resolveBundle - replaces the resource spec with a self-contained spec when it references files of the bundle
+func (s *SpecResourceParser) resolveBundle() error {
+	r := newSpecBundleResolver(s.bundle)
+
+	var spec []byte
+	var err error
+	if bytes.HasPrefix(s.resourceSpec, []byte(ramlHeaderPrefix)) {
+		spec, err = r.inlineRaml(s.resourceSpec)
+	} else {
+		spec, err = r.inlineRefs(s.resourceSpec)
+	}
+	if err != nil || !r.inlined {
+		return err
+	}
+
+	s.resourceSpec = spec
+	s.inlinedSpec = spec
+	s.specHash, _ = util.ComputeHash(spec)
+	return nil
+}
+
+// inlineRefs - inlines the external references of a json or yaml spec, a spec that is neither is returned as is
+func (r *specBundleResolver) inlineRefs(spec []byte) ([]byte, error) {
+	var doc interface{}
+	isJSON := true
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		isJSON = false
+		if err := yaml.Unmarshal(spec, &doc); err != nil {
+			return spec, nil
+		}
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return spec, nil
+	}
+	for name := range r.specSchemas(root, false) {
+		r.schemaNames[name] = true
+	}
+
+	doc, err := r.resolveRefs("", doc)
+	if err != nil || !r.inlined {
+		return spec, err
+	}
+	if len(r.schemas) > 0 {
+		schemas := r.specSchemas(root, true)
+		for name, schema := range r.schemas {
+			schemas[name] = schema
+		}
+	}
+	if isJSON {
+		return json.Marshal(doc)
+	}
+	return yaml.Marshal(doc)
+}
+
+// specSchemas - the schemas of the spec, the definitions of a swagger spec or the component schemas otherwise, the map
+// is added to the spec when create is set and the spec has no schemas
+func (r *specBundleResolver) specSchemas(root map[string]interface{}, create bool) map[string]interface{} {
+	parent, key := root, "definitions"
+	r.schemasRef = "#/definitions/"
+	if _, isOAS2 := root["swagger"]; !isOAS2 {
+		key = "schemas"
+		r.schemasRef = "#/components/schemas/"
+		components, ok := root["components"].(map[string]interface{})
+		if !ok {
+			components = map[string]interface{}{}
+			if create {
+				root["components"] = components
+			}
+		}
+		parent = components
+	}
+	schemas, ok := parent[key].(map[string]interface{})
+	if !ok {
+		schemas = map[string]interface{}{}
+		if create {
+			parent[key] = schemas
+		}
+	}
+	return schemas
+}
+
+// recursiveSchemaName - the name of the recursive schema of the reference id in the schemas of the spec, the last
+// segment of the fragment or the name of the file, unique within the schemas
+func (r *specBundleResolver) recursiveSchemaName(id string) string {
+	if name, found := r.recursive[id]; found {
+		return name
+	}
+	key, fragment, _ := strings.Cut(id, "#")
+	base := path.Base(fragment)
+	if fragment == "" || base == "/" {
+		base = strings.TrimSuffix(path.Base(key), path.Ext(key))
+	}
+	base = strings.Trim(invalidSchemaNameChars.ReplaceAllString(strings.NewReplacer("~1", "/", "~0", "~").Replace(base), "_"), "_")
+	if base == "" {
+		base = "Schema"
+	}
+	name := base
+	for i := 2; r.schemaNames[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	r.schemaNames[name] = true
+	r.recursive[id] = name
+	return name
+}
+
+// resolveRefs - replaces the references in the node, of the document at base, with the value they refer to.
+// The local references of the root document, base "", are kept.
+func (r *specBundleResolver) resolveRefs(base string, node interface{}) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			resolved, err := r.resolveRef(base, ref)
+			if err != nil {
+				return nil, err
+			}
+			if resolved != nil {
+				delete(n, "$ref")
+				siblings, err := r.resolveRefs(base, n)
+				if err != nil {
+					return nil, err
+				}
+				return mergeRefSiblings(resolved, siblings.(map[string]interface{})), nil
+			}
+		}
+		for key, value := range n {
+			resolved, err := r.resolveRefs(base, value)
+			if err != nil {
+				return nil, err
+			}
+			n[key] = resolved
+		}
+	case []interface{}:
+		for i, value := range n {
+			resolved, err := r.resolveRefs(base, value)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = resolved
+		}
+	}
+	return node, nil
+}
+
+// resolveRef - the value the reference refers to, or nil when the reference is kept
+func (r *specBundleResolver) resolveRef(base, ref string) (interface{}, error) {
+	location, fragment, _ := strings.Cut(ref, "#")
+	key := base
+	if location != "" {
+		var found bool
+		if key, found = r.fileKey(base, location); !found {
+			return nil, nil
+		}
+	} else if base == "" {
+		return nil, nil
+	}
+
+	id := key + "#" + fragment
+	if r.resolving[id] || r.recursiveIDs[id] {
+		// a recursive schema refers to itself, in the schemas of the spec
+		r.recursiveIDs[id] = true
+		return map[string]interface{}{"$ref": r.schemasRef + r.recursiveSchemaName(id)}, nil
+	}
+	r.resolving[id] = true
+	defer delete(r.resolving, id)
+
+	doc, err := r.load(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve the reference %s in %s: %s", ref, displayBundlePath(base), err)
+	}
+	value, found := resolveLocalRef(doc, "#"+fragment)
+	if !found {
+		return nil, fmt.Errorf("could not resolve the reference %s in %s: #%s was not found in %s", ref, displayBundlePath(base), fragment, key)
+	}
+	r.inlined = true
+	resolved, err := r.resolveRefs(key, copySpecValue(value))
+	if err != nil || !r.recursiveIDs[id] {
+		return resolved, err
+	}
+	name := r.recursiveSchemaName(id)
+	r.schemas[name] = resolved
+	return map[string]interface{}{"$ref": r.schemasRef + name}, nil
+}
+
+// fileKey - the bundle key of the location relative to the base file, remote locations are only resolved when in the bundle
+func (r *specBundleResolver) fileKey(base, location string) (string, bool) {
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" {
+		_, found := r.files[location]
+		return location, found
+	}
+	return cleanBundlePath(path.Join(path.Dir(base), location)), true
+}
+
+// load - the parsed json or yaml file of the bundle
+func (r *specBundleResolver) load(key string) (interface{}, error) {
+	if doc, found := r.docs[key]; found {
+		return doc, nil
+	}
+	data, found := r.files[key]
+	if !found {
+		return nil, fmt.Errorf("the file %s is not in the bundle", key)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("could not parse the file %s: %s", key, err)
+		}
+	}
+	r.docs[key] = doc
+	return doc, nil
+}
+
+// inlineRaml - replaces the !include tags of the RAML spec with the content of the included files
+func (r *specBundleResolver) inlineRaml(spec []byte) ([]byte, error) {
+	doc := yamlv3.Node{}
+	if err := yamlv3.Unmarshal(spec, &doc); err != nil {
+		// the spec is not valid yaml, leave the error to the RAML parser
+		return spec, nil
+	}
+	if err := r.resolveIncludes("", &doc); err != nil || !r.inlined {
+		return spec, err
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := yamlv3.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *specBundleResolver) resolveIncludes(base string, node *yamlv3.Node) error {
+	if node.Kind != yamlv3.ScalarNode || node.Tag != ramlIncludeTag {
+		for _, child := range node.Content {
+			if err := r.resolveIncludes(base, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	key, found := r.fileKey(base, node.Value)
+	if !found {
+		return nil
+	}
+	if r.resolving[key] {
+		return fmt.Errorf("circular include %s in %s", node.Value, displayBundlePath(base))
+	}
+	data, found := r.files[key]
+	if !found {
+		return fmt.Errorf("could not resolve the include %s in %s: the file %s is not in the bundle", node.Value, displayBundlePath(base), key)
+	}
+	r.inlined = true
+
+	if !ramlYAMLExtensions[path.Ext(key)] {
+		*node = yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: string(data), Style: yamlv3.LiteralStyle}
+		return nil
+	}
+
+	// drop the RAML fragment header, i.e. #%RAML 1.0 DataType, which is a comment to the yaml parser
+	if bytes.HasPrefix(data, []byte(ramlHeaderPrefix)) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	included := yamlv3.Node{}
+	if err := yamlv3.Unmarshal(data, &included); err != nil {
+		return fmt.Errorf("could not parse the include %s in %s: %s", node.Value, displayBundlePath(base), err)
+	}
+	if len(included.Content) == 0 {
+		*node = yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+		return nil
+	}
+
+	r.resolving[key] = true
+	defer delete(r.resolving, key)
+	if err := r.resolveIncludes(key, included.Content[0]); err != nil {
+		return err
+	}
+	*node = *included.Content[0]
+	return nil
+}
+
+// mergeRefSiblings - the keys set alongside a $ref override the keys of the value it refers to
+func mergeRefSiblings(resolved interface{}, siblings map[string]interface{}) interface{} {
+	resolvedMap, ok := resolved.(map[string]interface{})
+	if !ok || len(siblings) == 0 {
+		return resolved
+	}
+	for key, value := range siblings {
+		resolvedMap[key] = value
+	}
+	return resolvedMap
+}
+
+// copySpecValue - a deep copy of a json value, so each inlined reference can be changed independently
+func copySpecValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			c[key] = copySpecValue(val)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			c[i] = copySpecValue(val)
+		}
+		return c
+	}
+	return value
+}
+
+func cleanBundlePath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func displayBundlePath(key string) string {
+	if key == "" {
+		return "the spec"
+	}
+	return key
+}
+
+// readSpecArchive - reads the spec at specPath, and the files in its directory, from a zip, tar or gzipped tar archive.
+// The bundle is keyed by the path of the files relative to the spec.
+func readSpecArchive(archive []byte, specPath string) ([]byte, map[string][]byte, error) {
+	files, err := readArchiveFiles(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	specKey := cleanBundlePath(specPath)
+	spec, found := files[specKey]
+	if !found {
+		return nil, nil, fmt.Errorf("the spec %s is not in the archive", specPath)
+	}
+
+	dir := path.Dir(specKey)
+	bundle := map[string][]byte{}
+	for name, data := range files {
+		if dir == "." {
+			bundle[name] = data
+		} else if rel, ok := strings.CutPrefix(name, dir+"/"); ok {
+			bundle[rel] = data
+		}
+	}
+	return spec, bundle, nil
+}
+
+// archiveFiles - the files read from an archive, within the size and count limits of the decompressed files
+type archiveFiles struct {
+	files map[string][]byte
+	size  int64
+}
+
+func (a *archiveFiles) add(name string, r io.Reader) error {
+	if len(a.files) >= maxArchiveFiles {
+		return fmt.Errorf("the archive has more than %d files", maxArchiveFiles)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize-a.size+1))
+	if err != nil {
+		return err
+	}
+	a.size += int64(len(data))
+	if a.size > maxArchiveSize {
+		return fmt.Errorf("the decompressed files of the archive are larger than %d bytes", maxArchiveSize)
+	}
+	a.files[cleanBundlePath(name)] = data
+	return nil
+}
+
+func readArchiveFiles(archive []byte) (map[string][]byte, error) {
+	files := &archiveFiles{files: map[string][]byte{}}
+	if bytes.HasPrefix(archive, []byte("PK\x03\x04")) {
+		zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zipReader.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = files.add(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return files.files, nil
+	}
+
+	var reader io.Reader = bytes.NewReader(archive)
+	if bytes.HasPrefix(archive, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("the archive is not a zip, tar or gzipped tar file: %s", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := files.add(header.Name, tarReader); err != nil {
+			return nil, err
+		}
+	}
+	return files.files, nil
+}
@@ -0,0 +1,258 @@
+package apic
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/invopop/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTestBundle - the files of the directory, keyed by their path relative to the directory
+func readTestBundle(t *testing.T, dir string) map[string][]byte {
+	bundle := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		bundle[filepath.ToSlash(rel)], err = os.ReadFile(p)
+		return err
+	})
+	assert.Nil(t, err)
+	return bundle
+}
+
+func TestSpecBundleInlinesRefs(t *testing.T) {
+	bundle := readTestBundle(t, filepath.Join("testdata", "spec-bundle"))
+	spec := bundle["openapi.yaml"]
+
+	specParser := NewSpecResourceParser(spec, "")
+	originalHash := specParser.specHash
+	specParser.bundle = bundle
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, Oas3, specParser.GetSpecProcessor().GetResourceType())
+	assert.Equal(t, mimeApplicationYAML, specParser.getResourceContentType())
+	assert.NotNil(t, specParser.inlinedSpec)
+	assert.NotEqual(t, originalHash, specParser.specHash)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(specParser.inlinedSpec, &doc))
+
+	list, _ := resolveLocalRef(doc, "#/paths/~1pets/get/responses")
+	items, _ := resolveLocalRef(list, "#/200/content/application~1json/schema/items")
+	assert.Equal(t, "object", items.(map[string]interface{})["type"])
+	id, _ := resolveLocalRef(items, "#/properties/id")
+	assert.Equal(t, map[string]interface{}{"type": "integer", "format": "int64"}, id)
+	owner, _ := resolveLocalRef(items, "#/properties/owner/properties/name/type")
+	assert.Equal(t, "string", owner)
+
+	// the local references of the spec are kept
+	errorRef, _ := resolveLocalRef(list, "#/default/$ref")
+	assert.Equal(t, "#/components/responses/Error", errorRef)
+	errorSchema, _ := resolveLocalRef(doc, "#/components/responses/Error/content/application~1json/schema/properties/code/type")
+	assert.Equal(t, "integer", errorSchema)
+
+	get, _ := resolveLocalRef(doc, "#/paths/~1pets~1{petId}/get")
+	param, _ := resolveLocalRef(get, "#/parameters/0/name")
+	assert.Equal(t, "petId", param)
+	schema, _ := resolveLocalRef(get, "#/responses/200/content/application~1json/schema")
+	assert.Equal(t, "the pet with the id", schema.(map[string]interface{})["description"])
+	assert.Equal(t, []interface{}{"id"}, schema.(map[string]interface{})["required"])
+	assert.NotContains(t, schema, "$ref")
+
+	// a spec without external references is left as is
+	specParser = NewSpecResourceParser(bundle["schemas/owner.yaml"], "")
+	specParser.bundle = bundle
+	specParser.Parse()
+	assert.Nil(t, specParser.inlinedSpec)
+}
+
+func TestSpecBundleInlinesRamlIncludes(t *testing.T) {
+	bundle := readTestBundle(t, filepath.Join("testdata", "spec-bundle", "raml"))
+
+	specParser := NewSpecResourceParser(bundle["api.raml"], "")
+	specParser.bundle = bundle
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, Raml, specParser.GetSpecProcessor().GetResourceType())
+	assert.Equal(t, "v1", specParser.GetSpecProcessor().GetVersion())
+
+	spec := string(specParser.inlinedSpec)
+	assert.Contains(t, spec, "#%RAML 1.0\n")
+	assert.NotContains(t, spec, "!include")
+	assert.NotContains(t, spec, "DataType")
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(specParser.inlinedSpec, &doc))
+	owner, _ := resolveLocalRef(doc, "#/types/Pet/properties/owner/properties/name")
+	assert.Equal(t, "string", owner)
+	description, _ := resolveLocalRef(doc, "#/~1pets/get/description")
+	assert.Equal(t, "Lists the pets of the store\n", description)
+}
+
+func TestSpecBundleRecursiveRefs(t *testing.T) {
+	// a recursive schema is added to the component schemas, referring to itself
+	spec := `{"openapi": "3.0.1", "info": {"title": "trees", "version": "1.0.0"}, "paths": {"/tree": {"get": {"responses": {
+		"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "tree.json#/Node"}}}}}}}}}`
+	specParser := NewSpecResourceParser([]byte(spec), "")
+	specParser.bundle = map[string][]byte{
+		"tree.json": []byte(`{"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/Node"}}}}}`),
+	}
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, Oas3, specParser.GetSpecProcessor().GetResourceType())
+	doc := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(specParser.inlinedSpec, &doc))
+	ref, _ := resolveLocalRef(doc, "#/paths/~1tree/get/responses/200/content/application~1json/schema/$ref")
+	assert.Equal(t, "#/components/schemas/Node", ref)
+	ref, _ = resolveLocalRef(doc, "#/components/schemas/Node/properties/children/items/$ref")
+	assert.Equal(t, "#/components/schemas/Node", ref)
+
+	// the schemas of files referring to each other, the names of the definitions of the spec are not reused
+	spec = `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0.0"}, "paths": {},
+		"definitions": {"Pet": {"$ref": "pet.json"}, "pet": {"type": "string"}}}`
+	specParser = NewSpecResourceParser([]byte(spec), "")
+	specParser.bundle = map[string][]byte{
+		"pet.json":   []byte(`{"type": "object", "properties": {"owner": {"$ref": "./owner.json"}}}`),
+		"owner.json": []byte(`{"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "pet.json"}}}}`),
+	}
+	assert.Nil(t, specParser.Parse())
+	assert.Equal(t, Oas2, specParser.GetSpecProcessor().GetResourceType())
+	doc = map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal(specParser.inlinedSpec, &doc))
+	ref, _ = resolveLocalRef(doc, "#/definitions/Pet/$ref")
+	assert.Equal(t, "#/definitions/pet_2", ref)
+	ref, _ = resolveLocalRef(doc, "#/definitions/pet_2/properties/owner/properties/pets/items/$ref")
+	assert.Equal(t, "#/definitions/pet_2", ref)
+	kept, _ := resolveLocalRef(doc, "#/definitions/pet/type")
+	assert.Equal(t, "string", kept)
+}
+
+func TestSpecBundleErrors(t *testing.T) {
+	tests := map[string]struct {
+		spec   string
+		bundle map[string][]byte
+		err    string
+	}{
+		"file not in the bundle": {
+			spec:   `{"swagger": "2.0", "definitions": {"Pet": {"$ref": "pet.json"}}}`,
+			bundle: map[string][]byte{"other.json": []byte(`{}`)},
+			err:    "could not resolve the reference pet.json in the spec: the file pet.json is not in the bundle",
+		},
+		"fragment not found": {
+			spec:   `{"swagger": "2.0", "definitions": {"Pet": {"$ref": "defs/pet.json#/Pet"}}}`,
+			bundle: map[string][]byte{"defs/pet.json": []byte(`{"Dog": {}}`)},
+			err:    "could not resolve the reference defs/pet.json#/Pet in the spec: #/Pet was not found in defs/pet.json",
+		},
+		"raml include not in the bundle": {
+			spec:   "#%RAML 1.0\ntitle: Pets\ntypes:\n  Pet: !include types/pet.raml\n",
+			bundle: map[string][]byte{"other.raml": []byte("type: object")},
+			err:    "could not resolve the include types/pet.raml in the spec: the file types/pet.raml is not in the bundle",
+		},
+		"circular raml include": {
+			spec: "#%RAML 1.0\ntitle: Pets\ntypes:\n  Pet: !include types/pet.raml\n",
+			bundle: map[string][]byte{
+				"types/pet.raml":   []byte("type: object\nproperties:\n  owner: !include owner.raml\n"),
+				"types/owner.raml": []byte("type: object\nproperties:\n  pet: !include pet.raml\n"),
+			},
+			err: "circular include pet.raml in types/owner.raml",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			specParser := NewSpecResourceParser([]byte(tc.spec), "")
+			specParser.bundle = tc.bundle
+			err := specParser.Parse()
+			assert.NotNil(t, err)
+			if err != nil {
+				assert.Equal(t, tc.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestServiceBodyWithSpecArchive(t *testing.T) {
+	bundle := readTestBundle(t, filepath.Join("testdata", "spec-bundle"))
+	bundle["../README.md"] = []byte("not part of the bundle")
+
+	zipBuf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(zipBuf)
+	tarBuf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(tarBuf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, data := range bundle {
+		name = filepath.ToSlash(filepath.Join("api", name))
+		w, err := zipWriter.Create(name)
+		assert.Nil(t, err)
+		w.Write(data)
+		assert.Nil(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		tarWriter.Write(data)
+	}
+	assert.Nil(t, zipWriter.Close())
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+
+	// a small archive of a file larger than the limit once decompressed
+	largeBuf := &bytes.Buffer{}
+	largeGzipWriter := gzip.NewWriter(largeBuf)
+	largeTarWriter := tar.NewWriter(largeGzipWriter)
+	large := make([]byte, maxArchiveSize+1)
+	assert.Nil(t, largeTarWriter.WriteHeader(&tar.Header{Name: "openapi.yaml", Mode: 0600, Size: int64(len(large)), Typeflag: tar.TypeReg}))
+	largeTarWriter.Write(large)
+	assert.Nil(t, largeTarWriter.Close())
+	assert.Nil(t, largeGzipWriter.Close())
+
+	tests := map[string]struct {
+		archive  []byte
+		specPath string
+		err      bool
+	}{
+		"zip archive": {
+			archive:  zipBuf.Bytes(),
+			specPath: "api/openapi.yaml",
+		},
+		"gzipped tar archive": {
+			archive:  tarBuf.Bytes(),
+			specPath: "./api/openapi.yaml",
+		},
+		"spec not in the archive": {
+			archive:  zipBuf.Bytes(),
+			specPath: "openapi.yaml",
+			err:      true,
+		},
+		"decompressed archive over the size limit": {
+			archive:  largeBuf.Bytes(),
+			specPath: "openapi.yaml",
+			err:      true,
+		},
+		"not an archive": {
+			archive:  bundle["openapi.yaml"],
+			specPath: "openapi.yaml",
+			err:      true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sb, err := NewServiceBodyBuilder().
+				SetAPIName("pets").
+				SetVersion("1.0.0").
+				SetSpecArchive(tc.archive, tc.specPath).
+				Build()
+			if tc.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, Oas3, sb.ResourceType)
+			assert.Equal(t, "1.0.0", sb.GetSpecVersion())
+			assert.NotContains(t, string(sb.SpecDefinition), "./schemas/")
+			assert.Contains(t, string(sb.SpecDefinition), "#/components/responses/Error")
+			assert.Len(t, sb.specBundle, 9)
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+// API update severities, set on the service body when computed from the spec changes
+const (
+	// APIUpdateSeverityMajor - the spec has breaking changes
+	APIUpdateSeverityMajor = "MAJOR"
+	// APIUpdateSeverityMinor - the spec has only non-breaking changes, like added operations
+	APIUpdateSeverityMinor = "MINOR"
+	// APIUpdateSeverityPatch - the spec changed without changing its operations, e.g. descriptions
+	APIUpdateSeverityPatch = "PATCH"
+)
+
+const specChangelogDetailKey = "specChangelog"
+
+var (
+	oasMethods       = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	pathParamRegEx   = regexp.MustCompile(`\{[^}]*\}`)
+	diffResourceType = map[string]bool{Oas2: true, Oas3: true}
+)
+
+// SpecChange - a change between the previous and the new spec
+type SpecChange struct {
+	Breaking bool   `json:"breaking"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// SpecDiff - the changes between the previous and the new spec and the severity they add up to
+type SpecDiff struct {
+	Severity         string       `json:"severity"`
+	PreviousRevision string       `json:"previousRevision,omitempty"`
+	Changes          []SpecChange `json:"changes"`
+}
+
+// Changelog - the changes as human readable lines, breaking changes first
+func (d *SpecDiff) Changelog() []string {
+	lines := []string{}
+	for _, breaking := range []bool{true, false} {
+		for _, c := range d.Changes {
+			if c.Breaking != breaking {
+				continue
+			}
+			prefix := "non-breaking"
+			if c.Breaking {
+				prefix = "breaking"
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s: %s", prefix, c.Location, c.Message))
+		}
+	}
+	return lines
+}
+
+// toDetails - the diff as saved in the revision x-agent-details
+func (d *SpecDiff) toDetails() map[string]interface{} {
+	details := map[string]interface{}{
+		"severity":  d.Severity,
+		"changelog": d.Changelog(),
+	}
+	if d.PreviousRevision != "" {
+		details["previousRevision"] = d.PreviousRevision
+	}
+	return details
+}
+
+// DiffSpecs - compares two OAS2 or OAS3 specs and classifies the changes as breaking or non-breaking.
+// Removed operations, new required parameters, changed responses and stricter security are breaking changes. The response
+// schemas are compared by their properties: added properties, media types or required properties are non-breaking,
+// any other change to a response schema is breaking.
+// Returns nil when either of the specs is not an OAS spec.
+func DiffSpecs(previousSpec, newSpec []byte) (*SpecDiff, error) {
+	previousOps, previousSchemes, err := parseDiffSpec(previousSpec)
+	if previousOps == nil || err != nil {
+		return nil, err
+	}
+	newOps, newSchemes, err := parseDiffSpec(newSpec)
+	if newOps == nil || err != nil {
+		return nil, err
+	}
+
+	changes := diffChanges{}
+	for _, key := range util.OrderedKeys(previousOps) {
+		previousOp := previousOps[key]
+		newOp, found := newOps[key]
+		if !found {
+			changes.add(true, previousOp.location, "operation removed")
+			continue
+		}
+		diffOperationChanges(previousOp, newOp, &changes)
+	}
+	for _, key := range util.OrderedKeys(newOps) {
+		if _, found := previousOps[key]; !found {
+			changes.add(false, newOps[key].location, "operation added")
+		}
+	}
+
+	for _, name := range util.OrderedKeys(previousSchemes) {
+		newScheme, found := newSchemes[name]
+		switch {
+		case !found:
+			changes.add(true, "security scheme "+name, "security scheme removed")
+		case newScheme != previousSchemes[name]:
+			changes.add(true, "security scheme "+name, "security scheme changed")
+		}
+	}
+	for _, name := range util.OrderedKeys(newSchemes) {
+		if _, found := previousSchemes[name]; !found {
+			changes.add(false, "security scheme "+name, "security scheme added")
+		}
+	}
+
+	diff := &SpecDiff{Severity: APIUpdateSeverityPatch, Changes: changes}
+	for _, c := range changes {
+		diff.Severity = APIUpdateSeverityMinor
+		if c.Breaking {
+			diff.Severity = APIUpdateSeverityMajor
+			break
+		}
+	}
+	return diff, nil
+}
+
+type diffChanges []SpecChange
+
+func (c *diffChanges) add(breaking bool, location, format string, args ...interface{}) {
+	*c = append(*c, SpecChange{Breaking: breaking, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// diffOperation - the operation has the same method and path, with any path parameter name, in both specs
+type diffOperation struct {
+	location            string
+	parameters          map[string]bool // "in:name" to required
+	requestBodyRequired bool
+	responses           map[string]interface{} // status code to the resolved schemas, without their annotations
+	security            []string
+}
+
+func diffOperationChanges(previousOp, newOp *diffOperation, changes *diffChanges) {
+	location := newOp.location
+	for _, key := range util.OrderedKeys(previousOp.parameters) {
+		required, found := newOp.parameters[key]
+		switch {
+		case !found:
+			changes.add(true, location, "parameter %s removed", key)
+		case required && !previousOp.parameters[key]:
+			changes.add(true, location, "parameter %s is now required", key)
+		case !required && previousOp.parameters[key]:
+			changes.add(false, location, "parameter %s is now optional", key)
+		}
+	}
+	for _, key := range util.OrderedKeys(newOp.parameters) {
+		if _, found := previousOp.parameters[key]; found {
+			continue
+		}
+		if newOp.parameters[key] {
+			changes.add(true, location, "required parameter %s added", key)
+			continue
+		}
+		changes.add(false, location, "optional parameter %s added", key)
+	}
+
+	if newOp.requestBodyRequired && !previousOp.requestBodyRequired {
+		changes.add(true, location, "request body is now required")
+	}
+
+	for _, code := range util.OrderedKeys(previousOp.responses) {
+		schema, found := newOp.responses[code]
+		if !found {
+			changes.add(true, location, "response %s removed", code)
+			continue
+		}
+		if changed, breaking := diffResponseSchema(previousOp.responses[code], schema); changed {
+			changes.add(breaking, location, "response %s schema changed", code)
+		}
+	}
+	for _, code := range util.OrderedKeys(newOp.responses) {
+		if _, found := previousOp.responses[code]; !found {
+			changes.add(false, location, "response %s added", code)
+		}
+	}
+
+	previousSecurity, newSecurity := strings.Join(previousOp.security, ","), strings.Join(newOp.security, ",")
+	switch {
+	case previousSecurity == newSecurity:
+	case previousSecurity == "":
+		changes.add(true, location, "security requirement %s added", newSecurity)
+	case newSecurity == "":
+		changes.add(false, location, "security requirement %s removed", previousSecurity)
+	default:
+		changes.add(true, location, "security requirement changed from %s to %s", previousSecurity, newSecurity)
+	}
+}
+
+// parseDiffSpec - the operations and security scheme fingerprints of an OAS spec, nil when the spec is not an OAS spec
+func parseDiffSpec(spec []byte) (map[string]*diffOperation, map[string]string, error) {
+	specParser := NewSpecResourceParser(spec, "")
+	if err := specParser.Parse(); err != nil {
+		return nil, nil, err
+	}
+	processor := specParser.GetSpecProcessor()
+	if !diffResourceType[processor.GetResourceType()] {
+		return nil, nil, nil
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(processor.GetSpecBytes(), &doc); err != nil {
+		return nil, nil, err
+	}
+	isOAS2 := processor.GetResourceType() == Oas2
+
+	schemes := map[string]interface{}{}
+	if isOAS2 {
+		schemes, _ = doc["securityDefinitions"].(map[string]interface{})
+	} else if components, ok := doc["components"].(map[string]interface{}); ok {
+		schemes, _ = components["securitySchemes"].(map[string]interface{})
+	}
+	schemeFingerprints := map[string]string{}
+	for name, s := range schemes {
+		// a changed description does not change the scheme
+		scheme, _ := resolveRefs(doc, s, map[string]bool{}).(map[string]interface{})
+		delete(scheme, "description")
+		schemeFingerprints[name] = fingerprint(scheme)
+	}
+
+	operations := map[string]*diffOperation{}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, p := range paths {
+		pathItem, _ := resolveRefs(doc, p, map[string]bool{}).(map[string]interface{})
+		pathParams, _ := pathItem["parameters"].([]interface{})
+		for _, method := range oasMethods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := strings.ToUpper(method) + " " + pathParamRegEx.ReplaceAllString(path, "{}")
+			operations[key] = newDiffOperation(doc, strings.ToUpper(method)+" "+path, pathParams, op, isOAS2)
+		}
+	}
+	return operations, schemeFingerprints, nil
+}
+
+func newDiffOperation(doc map[string]interface{}, location string, pathParams []interface{}, op map[string]interface{}, isOAS2 bool) *diffOperation {
+	diffOp := &diffOperation{
+		location:   location,
+		parameters: map[string]bool{},
+		responses:  map[string]interface{}{},
+	}
+
+	opParams, _ := op["parameters"].([]interface{})
+	for _, p := range append(append([]interface{}{}, pathParams...), opParams...) {
+		param, _ := resolveRefs(doc, p, map[string]bool{}).(map[string]interface{})
+		in, _ := param["in"].(string)
+		name, _ := param["name"].(string)
+		required, _ := param["required"].(bool)
+		if isOAS2 && in == "body" {
+			diffOp.requestBodyRequired = required
+			continue
+		}
+		diffOp.parameters[in+":"+name] = required
+	}
+	if body, ok := resolveRefs(doc, op["requestBody"], map[string]bool{}).(map[string]interface{}); ok {
+		diffOp.requestBodyRequired, _ = body["required"].(bool)
+	}
+
+	responses, _ := op["responses"].(map[string]interface{})
+	for code, r := range responses {
+		response, _ := resolveRefs(doc, r, map[string]bool{}).(map[string]interface{})
+		if isOAS2 {
+			diffOp.responses[code] = withoutAnnotations(response["schema"])
+			continue
+		}
+		schemas := map[string]interface{}{}
+		content, _ := response["content"].(map[string]interface{})
+		for mediaType, c := range content {
+			mt, _ := c.(map[string]interface{})
+			schemas[mediaType] = mt["schema"]
+		}
+		// the schemas by media type, compared as the properties of an object
+		diffOp.responses[code] = map[string]interface{}{"properties": withoutAnnotations(schemas)}
+	}
+
+	// the operation security overrides the security of the document
+	security, found := op["security"].([]interface{})
+	if !found {
+		security, _ = doc["security"].([]interface{})
+	}
+	for _, s := range security {
+		requirement, _ := s.(map[string]interface{})
+		if len(requirement) == 0 {
+			continue
+		}
+		diffOp.security = append(diffOp.security, strings.Join(util.OrderedKeys(requirement), "+"))
+	}
+	sort.Strings(diffOp.security)
+	return diffOp
+}
+
+// resolveRefs - replaces the local references in the node with the values they point to, references in a cycle are kept
+func resolveRefs(doc interface{}, node interface{}, resolving map[string]bool) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok && strings.HasPrefix(ref, "#") && !resolving[ref] {
+			if value, found := resolveLocalRef(doc, ref); found {
+				resolving[ref] = true
+				defer delete(resolving, ref)
+				return resolveRefs(doc, value, resolving)
+			}
+		}
+		resolved := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			resolved[k] = resolveRefs(doc, v, resolving)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(n))
+		for i, v := range n {
+			resolved[i] = resolveRefs(doc, v, resolving)
+		}
+		return resolved
+	}
+	return node
+}
+
+// diffResponseSchema - compares the response schemas, a client reading the response is not broken by added properties
+// or by properties that are now required, any other change is breaking
+func diffResponseSchema(previous, schema interface{}) (changed, breaking bool) {
+	if fingerprint(previous) == fingerprint(schema) {
+		return false, false
+	}
+	previousMap, ok := previous.(map[string]interface{})
+	schemaMap, isMap := schema.(map[string]interface{})
+	if !ok || !isMap {
+		return true, true
+	}
+
+	for _, key := range util.OrderedKeys(previousMap) {
+		switch key {
+		case "properties":
+			previousProperties, _ := previousMap[key].(map[string]interface{})
+			properties, _ := schemaMap[key].(map[string]interface{})
+			for _, name := range util.OrderedKeys(previousProperties) {
+				property, found := properties[name]
+				if !found {
+					return true, true
+				}
+				if _, propertyBreaking := diffResponseSchema(previousProperties[name], property); propertyBreaking {
+					return true, true
+				}
+			}
+		case "required":
+			// a property that is no longer required may be missing from the response
+			required := map[string]bool{}
+			list, _ := schemaMap[key].([]interface{})
+			for _, name := range list {
+				required[fmt.Sprint(name)] = true
+			}
+			previousList, _ := previousMap[key].([]interface{})
+			for _, name := range previousList {
+				if !required[fmt.Sprint(name)] {
+					return true, true
+				}
+			}
+		default:
+			if fingerprint(previousMap[key]) != fingerprint(schemaMap[key]) {
+				return true, true
+			}
+		}
+	}
+	for key := range schemaMap {
+		if _, found := previousMap[key]; !found && key != "properties" && key != "required" {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// schemaAnnotations - the schema keywords documenting the data, a change to them does not change the response
+var schemaAnnotations = map[string]bool{"description": true, "example": true, "examples": true, "title": true}
+
+// withoutAnnotations - the schema without its annotations, the properties named like an annotation are kept
+func withoutAnnotations(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			if schemaAnnotations[k] {
+				continue
+			}
+			properties, ok := v.(map[string]interface{})
+			if ok && (k == "properties" || k == "patternProperties") {
+				strippedProperties := make(map[string]interface{}, len(properties))
+				for name, property := range properties {
+					strippedProperties[name] = withoutAnnotations(property)
+				}
+				stripped[k] = strippedProperties
+				continue
+			}
+			stripped[k] = withoutAnnotations(v)
+		}
+		return stripped
+	case []interface{}:
+		stripped := make([]interface{}, len(s))
+		for i, v := range s {
+			stripped[i] = withoutAnnotations(v)
+		}
+		return stripped
+	}
+	return schema
+}
+
+// fingerprint - the json encoding of the value, with sorted keys, to compare values
+func fingerprint(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,236 @@
+package apic
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const diffBaseOpenAPI = `{
+	"openapi": "3.0.1",
+	"info": {"title": "pets", "version": "1.0.0"},
+	"servers": [{"url": "https://pets.example.com"}],
+	"security": [{"key": []}],
+	"paths": {
+		"/pets/{petId}": {
+			"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+			"get": {
+				"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}],
+				"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+			},
+			"delete": {"responses": {"204": {"description": "deleted"}}}
+		}
+	},
+	"components": {
+		"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}},
+		"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header"}}
+	}
+}`
+
+func TestDiffSpecs(t *testing.T) {
+	tests := map[string]struct {
+		newSpec           string
+		expectedSeverity  string
+		expectedChangelog []string
+	}{
+		"description only": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "1.0.1", "description": "the pets api"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}],
+				"paths": {
+					"/pets/{id}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"summary": "get a pet",
+							"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}],
+							"responses": {"200": {"description": "the pet", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+						},
+						"delete": {"responses": {"204": {"description": "deleted"}}}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}},
+					"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header", "description": "the api key"}}
+				}
+			}`,
+			expectedSeverity:  APIUpdateSeverityPatch,
+			expectedChangelog: []string{},
+		},
+		"response schema description only": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "1.0.1"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}],
+				"paths": {
+					"/pets/{petId}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}],
+							"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+						},
+						"delete": {"responses": {"204": {"description": "deleted"}}}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {
+						"type": "object", "title": "Pet", "description": "a pet", "example": {"name": "Rex"},
+						"properties": {"name": {"type": "string", "description": "the name of the pet"}}
+					}},
+					"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header"}}
+				}
+			}`,
+			expectedSeverity:  APIUpdateSeverityPatch,
+			expectedChangelog: []string{},
+		},
+		"non-breaking additions": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "1.1.0"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}],
+				"paths": {
+					"/pets": {"get": {"responses": {"200": {"description": "ok"}}}},
+					"/pets/{petId}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}, {"name": "lang", "in": "header", "schema": {"type": "string"}}],
+							"responses": {
+								"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+								"404": {"description": "not found"}
+							}
+						},
+						"delete": {"responses": {"204": {"description": "deleted"}}}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}},
+					"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header"}}
+				}
+			}`,
+			expectedSeverity: APIUpdateSeverityMinor,
+			expectedChangelog: []string{
+				"non-breaking: GET /pets/{petId}: optional parameter header:lang added",
+				"non-breaking: GET /pets/{petId}: response 404 added",
+				"non-breaking: GET /pets: operation added",
+			},
+		},
+		"response property added": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "1.1.0"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}],
+				"paths": {
+					"/pets/{petId}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}],
+							"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+						},
+						"delete": {"responses": {"204": {"description": "deleted"}}}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}, "tag": {"type": "string"}}}},
+					"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header"}}
+				}
+			}`,
+			expectedSeverity:  APIUpdateSeverityMinor,
+			expectedChangelog: []string{"non-breaking: GET /pets/{petId}: response 200 schema changed"},
+		},
+		"response property removed": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "2.0.0"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}],
+				"paths": {
+					"/pets/{petId}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"parameters": [{"name": "fields", "in": "query", "schema": {"type": "string"}}],
+							"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+						},
+						"delete": {"responses": {"204": {"description": "deleted"}}}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {"type": "object", "properties": {}}},
+					"securitySchemes": {"key": {"type": "apiKey", "name": "KeyId", "in": "header"}}
+				}
+			}`,
+			expectedSeverity:  APIUpdateSeverityMajor,
+			expectedChangelog: []string{"breaking: GET /pets/{petId}: response 200 schema changed"},
+		},
+		"breaking changes": {
+			newSpec: `{
+				"openapi": "3.0.1",
+				"info": {"title": "pets", "version": "2.0.0"},
+				"servers": [{"url": "https://pets.example.com"}],
+				"security": [{"key": []}, {"oauth": ["read"]}],
+				"paths": {
+					"/pets/{petId}": {
+						"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"get": {
+							"parameters": [{"name": "fields", "in": "query", "required": true, "schema": {"type": "string"}}],
+							"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+						}
+					}
+				},
+				"components": {
+					"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "integer"}}}},
+					"securitySchemes": {
+						"key": {"type": "apiKey", "name": "KeyId", "in": "query"},
+						"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://auth.example.com/token", "scopes": {"read": "read"}}}}
+					}
+				}
+			}`,
+			expectedSeverity: APIUpdateSeverityMajor,
+			expectedChangelog: []string{
+				"breaking: DELETE /pets/{petId}: operation removed",
+				"breaking: GET /pets/{petId}: parameter query:fields is now required",
+				"breaking: GET /pets/{petId}: response 200 schema changed",
+				"breaking: GET /pets/{petId}: security requirement changed from key to key,oauth",
+				"breaking: security scheme key: security scheme changed",
+				"non-breaking: security scheme oauth: security scheme added",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			diff, err := DiffSpecs([]byte(diffBaseOpenAPI), []byte(tc.newSpec))
+			assert.Nil(t, err)
+			assert.NotNil(t, diff)
+			assert.Equal(t, tc.expectedSeverity, diff.Severity)
+			assert.Equal(t, tc.expectedChangelog, diff.Changelog())
+		})
+	}
+}
+
+func TestDiffSpecsOAS2(t *testing.T) {
+	previousSpec, err := os.ReadFile("./testdata/petstore-swagger2.json")
+	assert.Nil(t, err)
+
+	diff, err := DiffSpecs(previousSpec, previousSpec)
+	assert.Nil(t, err)
+	assert.Equal(t, APIUpdateSeverityPatch, diff.Severity)
+	assert.Empty(t, diff.Changes)
+
+	newSpec := `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0.0"}, "host": "pets.example.com", "paths": {}}`
+	diff, err = DiffSpecs(previousSpec, []byte(newSpec))
+	assert.Nil(t, err)
+	assert.Equal(t, APIUpdateSeverityMajor, diff.Severity)
+	assert.Contains(t, diff.Changelog(), "breaking: GET /pet/{petId}: operation removed")
+
+	// specs that are not OAS specs are not compared
+	protoSpec, err := os.ReadFile("./testdata/petstore.proto")
+	assert.Nil(t, err)
+	diff, err = DiffSpecs(previousSpec, protoSpec)
+	assert.Nil(t, err)
+	assert.Nil(t, diff)
+}
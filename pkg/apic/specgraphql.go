@@ -1,29 +1,91 @@
 package apic
 
+import (
+	"github.com/Axway/agent-sdk/pkg/util/graphql"
+)
+
+const (
+	graphQLQueriesDetailKey       = "graphqlQueries"
+	graphQLMutationsDetailKey     = "graphqlMutations"
+	graphQLSubscriptionsDetailKey = "graphqlSubscriptions"
+)
+
+// GraphQLOperation - a root operation field of a GraphQL schema
+type GraphQLOperation struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ReturnType  string `json:"returnType"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
 type graphQLProcessor struct {
-	spec []byte
+	schema *graphql.Schema
+	spec   []byte
 }
 
-func newGraphQLSpecProcessor(resourceSpec []byte) SpecProcessor {
-	return &graphQLProcessor{spec: resourceSpec}
+func newGraphQLSpecProcessor(schema *graphql.Schema, resourceSpec []byte) *graphQLProcessor {
+	return &graphQLProcessor{schema: schema, spec: resourceSpec}
 }
 
 func (p *graphQLProcessor) GetResourceType() string {
 	return GraphQL
 }
 
+// GetVersion - GraphQL schemas are versionless
 func (p *graphQLProcessor) GetVersion() string {
 	return ""
 }
 
+// GetDescription - the schema description, falling back to the description of the query root type
 func (p *graphQLProcessor) GetDescription() string {
+	if p.schema.Description != "" {
+		return p.schema.Description
+	}
+	if t, ok := p.schema.Types[p.schema.QueryType]; ok {
+		return t.Description
+	}
 	return ""
 }
 
+// GetEndpoints - the schema does not define where the service is hosted
 func (p *graphQLProcessor) GetEndpoints() ([]EndpointDefinition, error) {
 	return []EndpointDefinition{}, nil
 }
 
+// GetOperations - the query, mutation and subscription root operations of the schema
+func (p *graphQLProcessor) GetOperations() []GraphQLOperation {
+	operations := []GraphQLOperation{}
+	for _, opType := range []string{graphql.OperationQuery, graphql.OperationMutation, graphql.OperationSubscription} {
+		for _, f := range p.schema.RootOperations(opType) {
+			operations = append(operations, GraphQLOperation{
+				Type:        opType,
+				Name:        f.Name,
+				Description: f.Description,
+				ReturnType:  f.Type.String(),
+				Deprecated:  f.IsDeprecated,
+			})
+		}
+	}
+	return operations
+}
+
+// getRevisionDetails - the root operation names to add to the revision x-agent-details
+func (p *graphQLProcessor) getRevisionDetails() map[string]interface{} {
+	keys := map[string]string{
+		graphql.OperationQuery:        graphQLQueriesDetailKey,
+		graphql.OperationMutation:     graphQLMutationsDetailKey,
+		graphql.OperationSubscription: graphQLSubscriptionsDetailKey,
+	}
+	details := map[string]interface{}{}
+	for _, op := range p.GetOperations() {
+		names, _ := details[keys[op.Type]].([]interface{})
+		details[keys[op.Type]] = append(names, op.Name)
+	}
+	return details
+}
+
+// GetSpecBytes -
 func (p *graphQLProcessor) GetSpecBytes() []byte {
 	return p.spec
 }
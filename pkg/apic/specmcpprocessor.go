@@ -1,29 +1,244 @@
 package apic
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MCP transport types found in server manifests
+const (
+	MCPTransportStreamableHTTP = "streamable-http"
+	MCPTransportSSE            = "sse"
+)
+
+const (
+	mcpToolsDetailKey     = "mcpTools"
+	mcpResourcesDetailKey = "mcpResources"
+	mcpPromptsDetailKey   = "mcpPrompts"
+	mcpTransportDetailKey = "transport"
+	mcpSchemaIdentifier   = "modelcontextprotocol"
+)
+
+// MCPImplementation - the name and version of an MCP server implementation
+type MCPImplementation struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// MCPRemote - a remote transport an MCP server is reachable on
+type MCPRemote struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// MCPTool - a tool exposed by an MCP server
+type MCPTool struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema,omitempty"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  map[string]interface{} `json:"annotations,omitempty"`
+}
+
+// MCPResource - a resource, or resource template, exposed by an MCP server
+type MCPResource struct {
+	URI         string `json:"uri,omitempty"`
+	URITemplate string `json:"uriTemplate,omitempty"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// MCPPromptArgument - an argument accepted by an MCP prompt
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPPrompt - a prompt exposed by an MCP server
+type MCPPrompt struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// mcpManifest - the MCP server manifest, supports the registry server.json format
+// along with the tools, resources and prompts the server advertises
+type mcpManifest struct {
+	Schema            string             `json:"$schema,omitempty"`
+	Name              string             `json:"name"`
+	Title             string             `json:"title,omitempty"`
+	Description       string             `json:"description,omitempty"`
+	Version           string             `json:"version,omitempty"`
+	URL               string             `json:"url,omitempty"`
+	ServerInfo        *MCPImplementation `json:"serverInfo,omitempty"`
+	Instructions      string             `json:"instructions,omitempty"`
+	Remotes           []MCPRemote        `json:"remotes,omitempty"`
+	Tools             []MCPTool          `json:"tools,omitempty"`
+	Resources         []MCPResource      `json:"resources,omitempty"`
+	ResourceTemplates []MCPResource      `json:"resourceTemplates,omitempty"`
+	Prompts           []MCPPrompt        `json:"prompts,omitempty"`
+}
+
 type mcpProcessor struct {
-	spec []byte
+	manifest *mcpManifest
+	spec     []byte
 }
 
-func newMCPSpecProcessor(resourceSpec []byte) SpecProcessor {
-	return &mcpProcessor{spec: resourceSpec}
+func newMCPSpecProcessor(manifest *mcpManifest, resourceSpec []byte) *mcpProcessor {
+	return &mcpProcessor{manifest: manifest, spec: resourceSpec}
+}
+
+// parseMCPManifest - unmarshal the MCP server manifest
+func parseMCPManifest(spec []byte) (*mcpManifest, error) {
+	manifest := &mcpManifest{}
+	if err := json.Unmarshal(spec, manifest); err != nil {
+		return nil, fmt.Errorf("invalid mcp server manifest: %s", err)
+	}
+	if manifest.Name == "" && manifest.ServerInfo == nil {
+		return nil, fmt.Errorf("invalid mcp server manifest: 'name' or 'serverInfo' key not found")
+	}
+	return manifest, nil
+}
+
+// isMCPManifest - returns true when the parsed yaml or json document looks like an MCP server manifest
+func isMCPManifest(specDef map[string]interface{}) bool {
+	if schema, ok := specDef["$schema"].(string); ok && strings.Contains(schema, mcpSchemaIdentifier) {
+		return true
+	}
+	if _, ok := specDef["serverInfo"].(map[string]interface{}); ok {
+		return true
+	}
+	if remotes, ok := specDef["remotes"].([]interface{}); ok {
+		for _, r := range remotes {
+			remote, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if t, _ := remote["type"].(string); t == MCPTransportStreamableHTTP || t == MCPTransportSSE {
+				return true
+			}
+		}
+	}
+	// a list of tools that each carry an input schema
+	if tools, ok := specDef["tools"].([]interface{}); ok && len(tools) > 0 {
+		for _, t := range tools {
+			tool, ok := t.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if _, found := tool["inputSchema"]; !found {
+				return false
+			}
+		}
+		return true
+	}
+	return false
 }
 
 func (p *mcpProcessor) GetResourceType() string {
 	return Mcp
 }
 
+// GetVersion - the server implementation version, falling back to the manifest version
 func (p *mcpProcessor) GetVersion() string {
-	return ""
+	if p.manifest.ServerInfo != nil && p.manifest.ServerInfo.Version != "" {
+		return p.manifest.ServerInfo.Version
+	}
+	return p.manifest.Version
 }
 
+// GetDescription - the manifest description, falling back to the server instructions
 func (p *mcpProcessor) GetDescription() string {
-	return ""
+	if p.manifest.Description != "" {
+		return p.manifest.Description
+	}
+	return p.manifest.Instructions
 }
 
+// GetTitle -
+func (p *mcpProcessor) GetTitle() string {
+	if p.manifest.ServerInfo != nil && p.manifest.ServerInfo.Title != "" {
+		return p.manifest.ServerInfo.Title
+	}
+	if p.manifest.Title != "" {
+		return p.manifest.Title
+	}
+	if p.manifest.ServerInfo != nil && p.manifest.ServerInfo.Name != "" {
+		return p.manifest.ServerInfo.Name
+	}
+	return p.manifest.Name
+}
+
+// GetEndpoints - an endpoint for each of the remote transports of the server
 func (p *mcpProcessor) GetEndpoints() ([]EndpointDefinition, error) {
-	return []EndpointDefinition{}, nil
+	remotes := p.manifest.Remotes
+	if len(remotes) == 0 && p.manifest.URL != "" {
+		remotes = []MCPRemote{{Type: MCPTransportStreamableHTTP, URL: p.manifest.URL}}
+	}
+
+	urls := []endpointURL{}
+	for _, remote := range remotes {
+		if remote.URL == "" {
+			continue
+		}
+		transport := remote.Type
+		if transport == "" {
+			transport = MCPTransportStreamableHTTP
+		}
+		urls = append(urls, endpointURL{url: remote.URL, details: map[string]interface{}{mcpTransportDetailKey: transport}})
+	}
+	return endpointsFromURLs(urls, "https", 0)
+}
+
+// GetTools -
+func (p *mcpProcessor) GetTools() []MCPTool {
+	return p.manifest.Tools
 }
 
+// GetResources - the resources and resource templates of the server
+func (p *mcpProcessor) GetResources() []MCPResource {
+	return append(append([]MCPResource{}, p.manifest.Resources...), p.manifest.ResourceTemplates...)
+}
+
+// GetPrompts -
+func (p *mcpProcessor) GetPrompts() []MCPPrompt {
+	return p.manifest.Prompts
+}
+
+// getRevisionDetails - the tool, resource and prompt metadata to add to the revision x-agent-details
+func (p *mcpProcessor) getRevisionDetails() map[string]interface{} {
+	details := map[string]interface{}{}
+	if tools := p.GetTools(); len(tools) > 0 {
+		details[mcpToolsDetailKey] = toDetailList(tools)
+	}
+	if resources := p.GetResources(); len(resources) > 0 {
+		details[mcpResourcesDetailKey] = toDetailList(resources)
+	}
+	if prompts := p.GetPrompts(); len(prompts) > 0 {
+		details[mcpPromptsDetailKey] = toDetailList(prompts)
+	}
+	return details
+}
+
+// GetSpecBytes -
 func (p *mcpProcessor) GetSpecBytes() []byte {
 	return p.spec
 }
+
+// toDetailList - converts the list of structs to a generic list to be saved in x-agent-details
+func toDetailList[T any](items []T) []interface{} {
+	list := []interface{}{}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return list
+	}
+	json.Unmarshal(data, &list)
+	return list
+}
@@ -226,6 +226,106 @@ func (p *oas2SpecProcessor) GetSpecBytes() []byte {
 	return s
 }
 
+// ValidateSpec - validates the required fields, security definitions, operation ids and local references of the spec,
+// the security definitions that are not used are reported as warnings
+func (p *oas2SpecProcessor) ValidateSpec() []SpecValidationIssue {
+	issues := specIssues{}
+	if p.spec.Info.Title == "" {
+		issues.addError("#/info/title", "value of title must be a non-empty string")
+	}
+	if p.spec.Info.Version == "" {
+		issues.addError("#/info/version", "value of version must be a non-empty string")
+	}
+
+	for _, name := range util.OrderedKeys(p.spec.SecurityDefinitions) {
+		p.validateSecurityDefinition("#/securityDefinitions/"+escapeJSONPointer(name), p.spec.SecurityDefinitions[name], &issues)
+	}
+	used := map[string]bool{}
+	validateRequirements := func(location string, requirements openapi2.SecurityRequirements) {
+		for _, requirement := range requirements {
+			for _, name := range util.OrderedKeys(requirement) {
+				used[name] = true
+				if _, found := p.spec.SecurityDefinitions[name]; !found {
+					issues.addError(location, "security definition %s is not defined", name)
+				}
+			}
+		}
+	}
+	validateRequirements("#/security", p.spec.Security)
+
+	operationIDs := map[string]string{}
+	for _, path := range util.OrderedKeys(p.spec.Paths) {
+		pathLocation := "#/paths/" + escapeJSONPointer(path)
+		if !strings.HasPrefix(path, "/") {
+			issues.addError(pathLocation, "path %s must begin with a slash", path)
+		}
+		if p.spec.Paths[path] == nil {
+			continue
+		}
+		operations := p.spec.Paths[path].Operations()
+		for _, method := range util.OrderedKeys(operations) {
+			op := operations[method]
+			location := pathLocation + "/" + strings.ToLower(method)
+			if op.Security != nil {
+				validateRequirements(location+"/security", *op.Security)
+			}
+			if len(op.Responses) == 0 {
+				issues.addError(location+"/responses", "the operation must define at least one response")
+			}
+			if op.OperationID == "" {
+				continue
+			}
+			if other, found := operationIDs[op.OperationID]; found {
+				issues.addError(location+"/operationId", "operation id %s is also used by %s", op.OperationID, other)
+				continue
+			}
+			operationIDs[op.OperationID] = location
+		}
+	}
+	for _, name := range util.OrderedKeys(p.spec.SecurityDefinitions) {
+		if !used[name] {
+			issues.addWarning("#/securityDefinitions/"+escapeJSONPointer(name), "security definition %s is not used by any security requirement", name)
+		}
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(p.GetSpecBytes(), &doc); err == nil {
+		validateLocalRefs(doc, &issues)
+	}
+	return issues
+}
+
+func (p *oas2SpecProcessor) validateSecurityDefinition(location string, scheme *openapi2.SecurityScheme, issues *specIssues) {
+	if scheme == nil || scheme.Ref != "" {
+		return
+	}
+	switch scheme.Type {
+	case oasSecurityBasic:
+	case oasSecurityAPIKey:
+		if scheme.Name == "" {
+			issues.addError(location, "an apiKey security definition must have a name")
+		}
+		if scheme.In != "query" && scheme.In != "header" {
+			issues.addError(location, "the location of an apiKey security definition must be query or header, found %q", scheme.In)
+		}
+	case oasSecurityOauth:
+		requiresAuthURL := scheme.Flow == "implicit" || scheme.Flow == "accessCode"
+		requiresTokenURL := scheme.Flow == "password" || scheme.Flow == "application" || scheme.Flow == "accessCode"
+		if !requiresAuthURL && !requiresTokenURL {
+			issues.addError(location, "the flow of an oauth2 security definition must be implicit, password, application or accessCode, found %q", scheme.Flow)
+			return
+		}
+		if requiresAuthURL && scheme.AuthorizationURL == "" {
+			issues.addError(location, "the %s flow requires an authorizationUrl", scheme.Flow)
+		}
+		if requiresTokenURL && scheme.TokenURL == "" {
+			issues.addError(location, "the %s flow requires a tokenUrl", scheme.Flow)
+		}
+	default:
+		issues.addError(location, "the type of a security definition must be basic, apiKey or oauth2, found %q", scheme.Type)
+	}
+}
+
 func createEndpointDefinition(scheme, host string, port int, basePath string) EndpointDefinition {
 	path := "/"
 	if basePath != "" {
@@ -1,6 +1,7 @@
 package apic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -12,19 +13,21 @@ import (
 	"github.com/Axway/agent-sdk/pkg/util"
 	coreerrors "github.com/Axway/agent-sdk/pkg/util/errors"
 	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/Axway/agent-sdk/pkg/util/oas"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // oas3SpecProcessor parses and validates an OAS3 spec, and exposes methods to modify the content of the spec.
 type oas3SpecProcessor struct {
 	spec         *openapi3.T
+	rawSpec      []byte
 	scopes       map[string]string
 	authPolicies []string
 	apiKeyInfo   []APIKeyInfo
 }
 
-func newOas3Processor(oas3Obj *openapi3.T) *oas3SpecProcessor {
-	return &oas3SpecProcessor{spec: oas3Obj}
+func newOas3Processor(oas3Obj *openapi3.T, rawSpec []byte) *oas3SpecProcessor {
+	return &oas3SpecProcessor{spec: oas3Obj, rawSpec: rawSpec}
 }
 
 func (p *oas3SpecProcessor) GetResourceType() string {
@@ -265,6 +268,9 @@ func (p *oas3SpecProcessor) StripExtensions() {
 		}
 		p.spec.Paths.Extensions = map[string]any{}
 	}
+	for _, webhook := range p.spec.Webhooks {
+		cleanPathExtensions(webhook)
+	}
 	if p.spec.Components != nil {
 		p.spec.Components.Extensions = map[string]any{}
 	}
@@ -300,9 +306,62 @@ func (p *oas3SpecProcessor) stripTags(tags []string) {
 
 func (p *oas3SpecProcessor) GetSpecBytes() []byte {
 	s, _ := json.Marshal(p.spec)
+	if len(p.rawSpec) > 0 && oas.IsOAS31(p.spec) {
+		if restored, err := oas.RestoreRefSiblings(p.rawSpec, s); err == nil {
+			s = restored
+		}
+	}
 	return s
 }
 
+// ValidateSpec - validates the structure of the spec and that all security requirements refer to a defined scheme,
+// the security schemes that are not used are reported as warnings
+func (p *oas3SpecProcessor) ValidateSpec() []SpecValidationIssue {
+	issues := specIssues{}
+	err := p.spec.Validate(context.Background(), openapi3.EnableMultiError(), openapi3.DisableExamplesValidation())
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		for _, e := range multiErr {
+			issues.addError("", "%s", e.Error())
+		}
+	} else if err != nil {
+		issues.addError("", "%s", err.Error())
+	}
+
+	schemes := openapi3.SecuritySchemes{}
+	if p.spec.Components != nil && p.spec.Components.SecuritySchemes != nil {
+		schemes = p.spec.Components.SecuritySchemes
+	}
+	used := map[string]bool{}
+	validateRequirements := func(location string, requirements openapi3.SecurityRequirements) {
+		for _, requirement := range requirements {
+			for _, name := range util.OrderedKeys(requirement) {
+				used[name] = true
+				if _, found := schemes[name]; !found {
+					issues.addError(location, "security scheme %s is not defined", name)
+				}
+			}
+		}
+	}
+	validateRequirements("#/security", p.spec.Security)
+	if p.spec.Paths != nil {
+		paths := p.spec.Paths.Map()
+		for _, path := range util.OrderedKeys(paths) {
+			operations := paths[path].Operations()
+			for _, method := range util.OrderedKeys(operations) {
+				if op := operations[method]; op.Security != nil {
+					validateRequirements(fmt.Sprintf("#/paths/%s/%s/security", escapeJSONPointer(path), strings.ToLower(method)), *op.Security)
+				}
+			}
+		}
+	}
+	for _, name := range util.OrderedKeys(schemes) {
+		if !used[name] {
+			issues.addWarning("#/components/securitySchemes/"+escapeJSONPointer(name), "security scheme %s is not used by any security requirement", name)
+		}
+	}
+	return issues
+}
+
 func (p *oas3SpecProcessor) GetSecurityBuilder() SecurityBuilder {
 	return newSpecSecurityBuilder(oas3)
 }
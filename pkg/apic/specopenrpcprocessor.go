@@ -0,0 +1,145 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	openRPCMethodsDetailKey = "openrpcMethods"
+	openRPCServerDetailKey  = "server"
+)
+
+// OpenRPCContentDescriptor - a method parameter or result
+type OpenRPCContentDescriptor struct {
+	Name        string                 `json:"name"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+}
+
+// OpenRPCMethod - a JSON-RPC method of the service
+type OpenRPCMethod struct {
+	Name           string                     `json:"name"`
+	Summary        string                     `json:"summary,omitempty"`
+	Description    string                     `json:"description,omitempty"`
+	Params         []OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor  `json:"result,omitempty"`
+	ParamStructure string                     `json:"paramStructure,omitempty"`
+	Deprecated     bool                       `json:"deprecated,omitempty"`
+}
+
+type openRPCServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+type openRPCServer struct {
+	Name        string                           `json:"name,omitempty"`
+	URL         string                           `json:"url"`
+	Summary     string                           `json:"summary,omitempty"`
+	Description string                           `json:"description,omitempty"`
+	Variables   map[string]openRPCServerVariable `json:"variables,omitempty"`
+}
+
+type openRPCInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// openRPCDocument - the OpenRPC document describing a JSON-RPC service
+type openRPCDocument struct {
+	OpenRPC string          `json:"openrpc"`
+	Info    openRPCInfo     `json:"info"`
+	Servers []openRPCServer `json:"servers,omitempty"`
+	Methods []OpenRPCMethod `json:"methods"`
+}
+
+type openRPCProcessor struct {
+	doc  *openRPCDocument
+	spec []byte
+}
+
+func newOpenRPCProcessor(doc *openRPCDocument, resourceSpec []byte) *openRPCProcessor {
+	return &openRPCProcessor{doc: doc, spec: resourceSpec}
+}
+
+// parseOpenRPCDocument - unmarshal the OpenRPC document
+func parseOpenRPCDocument(spec []byte) (*openRPCDocument, error) {
+	doc := &openRPCDocument{}
+	if err := json.Unmarshal(spec, doc); err != nil {
+		return nil, fmt.Errorf("invalid openrpc specification: %s", err)
+	}
+	if !strings.HasPrefix(doc.OpenRPC, "1.") {
+		return nil, fmt.Errorf("invalid openrpc specification: 'openrpc' key is invalid")
+	}
+	if doc.Info.Title == "" {
+		return nil, fmt.Errorf("invalid openrpc specification: 'info.title' key not found")
+	}
+	if doc.Methods == nil {
+		return nil, fmt.Errorf("invalid openrpc specification: 'methods' key not found")
+	}
+	return doc, nil
+}
+
+func (p *openRPCProcessor) GetResourceType() string {
+	return OpenRPC
+}
+
+// GetVersion -
+func (p *openRPCProcessor) GetVersion() string {
+	return p.doc.Info.Version
+}
+
+// GetDescription -
+func (p *openRPCProcessor) GetDescription() string {
+	return p.doc.Info.Description
+}
+
+// GetTitle -
+func (p *openRPCProcessor) GetTitle() string {
+	return p.doc.Info.Title
+}
+
+// GetEndpoints - an endpoint for each server, server variables are replaced by their default values
+func (p *openRPCProcessor) GetEndpoints() ([]EndpointDefinition, error) {
+	urls := []endpointURL{}
+	for _, server := range p.doc.Servers {
+		serverURL := server.URL
+		for name, variable := range server.Variables {
+			// openrpc uses ${name} for variables, {name} is also accepted
+			serverURL = strings.ReplaceAll(serverURL, "${"+name+"}", variable.Default)
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+		}
+		u := endpointURL{url: serverURL}
+		if server.Name != "" {
+			u.details = map[string]interface{}{openRPCServerDetailKey: server.Name}
+		}
+		urls = append(urls, u)
+	}
+	return endpointsFromURLs(urls, "https", 0)
+}
+
+// GetMethods -
+func (p *openRPCProcessor) GetMethods() []OpenRPCMethod {
+	return p.doc.Methods
+}
+
+// getRevisionDetails - the method names to add to the revision x-agent-details
+func (p *openRPCProcessor) getRevisionDetails() map[string]interface{} {
+	names := make([]interface{}, 0, len(p.doc.Methods))
+	for _, m := range p.doc.Methods {
+		names = append(names, m.Name)
+	}
+	return map[string]interface{}{openRPCMethodsDetailKey: names}
+}
+
+// GetSpecBytes -
+func (p *openRPCProcessor) GetSpecBytes() []byte {
+	return p.spec
+}
@@ -9,6 +9,7 @@ import (
 
 	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
 	"github.com/Axway/agent-sdk/pkg/util"
+	"github.com/Axway/agent-sdk/pkg/util/graphql"
 	"github.com/Axway/agent-sdk/pkg/util/oas"
 
 	"github.com/Axway/agent-sdk/pkg/util/wsdl"
@@ -43,12 +44,17 @@ type AsyncSpecProcessor interface {
 	GetSpecBytes() []byte
 }
 
-// OasSpecProcessor -
-type OasSpecProcessor interface {
+// SpecAuthProcessor - a spec processor that provides the auth info used for access and credential request definitions
+type SpecAuthProcessor interface {
 	ParseAuthInfo()
 	GetAPIKeyInfo() []APIKeyInfo
 	GetOAuthScopes() map[string]string
 	GetAuthPolicies() []string
+}
+
+// OasSpecProcessor -
+type OasSpecProcessor interface {
+	SpecAuthProcessor
 	StripSpecAuth()
 	StripExtensions()
 	GetTitle() string
@@ -61,6 +67,11 @@ type OasSpecProcessor interface {
 	stripTags([]string)
 }
 
+// revisionDetailsProcessor - a spec processor that provides additional metadata to save in the revision x-agent-details
+type revisionDetailsProcessor interface {
+	getRevisionDetails() map[string]interface{}
+}
+
 // SpecResourceParser -
 type SpecResourceParser struct {
 	resourceSpecType    string
@@ -69,6 +80,8 @@ type SpecResourceParser struct {
 	specProcessor       SpecProcessor
 	specHash            uint64
 	tagsToStrip         []string
+	bundle              map[string][]byte
+	inlinedSpec         []byte
 }
 
 type newSpecParserFunc func(resourceSpec []byte, resourceSpecType string) SpecResourceParser
@@ -102,6 +115,12 @@ func newSpecResourceParser(opts ...specParserOpt) newSpecParserFunc {
 
 // Parse -
 func (s *SpecResourceParser) Parse() error {
+	if len(s.bundle) > 0 {
+		if err := s.resolveBundle(); err != nil {
+			return err
+		}
+	}
+
 	if s.resourceSpecType == "" {
 		err := s.discoverSpecTypeAndCreateProcessor()
 		if err != nil {
@@ -126,23 +145,13 @@ func (s *SpecResourceParser) getResourceContentType() string {
 
 func (s *SpecResourceParser) discoverSpecTypeAndCreateProcessor() error {
 	errs := []error{}
-	var err error
-	s.specProcessor, err = s.discoverYAMLAndJSONSpec()
-	if err == nil {
-		return nil
-	}
-	errs = append(errs, err)
-
-	if s.specProcessor == nil {
-		s.specProcessor, err = s.parseWSDLSpec()
-		if err == nil {
-			return nil
+	for _, step := range specRegistry.discoverySteps() {
+		if step.detector != nil && !step.detector(s.resourceSpec) {
+			continue
 		}
-		errs = append(errs, err)
-	}
-	if s.specProcessor == nil {
-		s.specProcessor, err = s.parseProtobufSpec()
+		specProcessor, err := step.create(s)
 		if err == nil {
+			s.specProcessor = specProcessor
 			return nil
 		}
 		errs = append(errs, err)
@@ -180,6 +189,12 @@ func (s *SpecResourceParser) createProcessorWithResourceType() error {
 		s.specProcessor, err = s.parseA2aSpec()
 	case Raml:
 		s.specProcessor, err = s.parseRamlSpec()
+	case OpenRPC:
+		s.specProcessor, err = s.parseOpenRPCSpec()
+	default:
+		if step, found := specRegistry.getRegistered(s.resourceSpecType); found {
+			s.specProcessor, err = step.create(s)
+		}
 	}
 	return err
 }
@@ -203,7 +218,7 @@ func (s *SpecResourceParser) discoverYAMLAndJSONSpec() (SpecProcessor, error) {
 
 	specType, ok := specDef["openapi"]
 	if ok {
-		openapi := specType.(string)
+		openapi, _ := specType.(string)
 		if strings.HasPrefix(openapi, "3.") {
 			s.resourceContentType = contentType
 			return s.parseOAS3Spec()
@@ -236,6 +251,22 @@ func (s *SpecResourceParser) discoverYAMLAndJSONSpec() (SpecProcessor, error) {
 		return newAsyncAPIProcessor(specDef, s.resourceSpec), nil
 	}
 
+	if _, ok = specDef["openrpc"]; ok {
+		return s.parseOpenRPCSpec()
+	}
+
+	if graphql.IsIntrospection(s.resourceSpec) {
+		return s.parseGraphQLSpec()
+	}
+
+	if isA2AAgentCard(specDef) {
+		return s.parseA2aSpec()
+	}
+
+	if isMCPManifest(specDef) {
+		return s.parseMcpSpec()
+	}
+
 	ramlVersion := ""
 	if len(s.resourceSpec) > 10 {
 		ramlVersion = string(s.resourceSpec[2:10])
@@ -253,11 +284,26 @@ func (s *SpecResourceParser) parseWSDLSpec() (SpecProcessor, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(s.bundle) > 0 {
+		if err := wsdl.ResolveImports(def, s.bundle); err != nil {
+			return nil, err
+		}
+	}
 	return newWsdlProcessor(def, s.resourceSpec), nil
 }
 
 func (s *SpecResourceParser) parseGraphQLSpec() (SpecProcessor, error) {
-	return newGraphQLSpecProcessor(s.resourceSpec), nil
+	schema, err := graphql.Parse(s.resourceSpec)
+	if err != nil {
+		return nil, err
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	if graphql.IsIntrospection(s.resourceSpec) {
+		s.resourceContentType = mimeApplicationJSON
+	}
+	return newGraphQLSpecProcessor(schema, s.resourceSpec), nil
 }
 
 func (s *SpecResourceParser) parseOAS2Spec() (SpecProcessor, error) {
@@ -273,7 +319,7 @@ func (s *SpecResourceParser) parseOAS3Spec() (SpecProcessor, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newOas3Processor(oas3Obj), nil
+	return newOas3Processor(oas3Obj, s.resourceSpec), nil
 }
 
 func (s *SpecResourceParser) parseAsyncAPISpec() (SpecProcessor, error) {
@@ -301,24 +347,68 @@ func (s *SpecResourceParser) parseProtobufSpec() (SpecProcessor, error) {
 	parser := proto.NewParser(reader)
 	definition, err := parser.Parse()
 	if err != nil {
+		// check for a binary FileDescriptorSet before returning the .proto parse error
+		if descriptorSet, setErr := parseFileDescriptorSet(s.resourceSpec); setErr == nil {
+			return newProtobufProcessor(descriptorSet, s.resourceSpec), nil
+		}
 		return nil, err
 	}
 
 	if len(definition.Elements) > 0 {
-		return newProtobufProcessor(definition, s.resourceSpec), nil
+		return newProtobufProcessor(newProtobufDefinition(definition), s.resourceSpec), nil
 	}
 	return nil, errors.New("invalid protobuf specification")
 
 }
 
-func (s *SpecResourceParser) parseMcpSpec() (SpecProcessor, error) {
+// specAsJSON - returns the resource spec as json, converting from yaml when needed, and sets the content type
+func (s *SpecResourceParser) specAsJSON() ([]byte, error) {
 	s.resourceContentType = mimeApplicationJSON
-	return newMCPSpecProcessor(s.resourceSpec), nil
+	if json.Valid(s.resourceSpec) {
+		return s.resourceSpec, nil
+	}
+	specBytes, err := yaml.YAMLToJSON(s.resourceSpec)
+	if err != nil {
+		return nil, err
+	}
+	s.resourceContentType = mimeApplicationYAML
+	return specBytes, nil
+}
+
+func (s *SpecResourceParser) parseMcpSpec() (SpecProcessor, error) {
+	specBytes, err := s.specAsJSON()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := parseMCPManifest(specBytes)
+	if err != nil {
+		return nil, err
+	}
+	return newMCPSpecProcessor(manifest, s.resourceSpec), nil
+}
+
+func (s *SpecResourceParser) parseOpenRPCSpec() (SpecProcessor, error) {
+	specBytes, err := s.specAsJSON()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseOpenRPCDocument(specBytes)
+	if err != nil {
+		return nil, err
+	}
+	return newOpenRPCProcessor(doc, s.resourceSpec), nil
 }
 
 func (s *SpecResourceParser) parseA2aSpec() (SpecProcessor, error) {
-	s.resourceContentType = mimeApplicationJSON
-	return newA2ASpecProcessor(s.resourceSpec), nil
+	specBytes, err := s.specAsJSON()
+	if err != nil {
+		return nil, err
+	}
+	card, err := parseA2AAgentCard(specBytes)
+	if err != nil {
+		return nil, err
+	}
+	return newA2ASpecProcessor(card, s.resourceSpec), nil
 }
 
 func (s *SpecResourceParser) parseRamlSpec() (SpecProcessor, error) {
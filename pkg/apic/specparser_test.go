@@ -1,14 +1,19 @@
 package apic
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"testing"
 
+	"github.com/Axway/agent-sdk/pkg/util/oas"
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"gopkg.in/yaml.v3"
 )
 
@@ -109,17 +114,38 @@ func TestSpecDiscovery(t *testing.T) {
 			parseErr:     false,
 			expectedType: Wsdl,
 		},
+		{
+			name:         "No input type WSDL 2.0 Spec",
+			inputFile:    "./testdata/wsdl20-pets.xml",
+			expectedType: Wsdl,
+		},
 		{
 			name:         "No input type Protobuf Spec",
 			inputFile:    "./testdata/petstore.proto",
 			parseErr:     false,
 			expectedType: Protobuf,
 		},
+		{
+			name:         "No input type versioned Protobuf Spec",
+			inputFile:    "./testdata/greeter-v2.proto",
+			expectedType: Protobuf,
+		},
 		{
 			name:         "No input type AsyncAPI Spec YAML",
 			inputFile:    "./testdata/asyncapi-sample.yaml",
 			expectedType: AsyncAPI,
 		},
+		{
+			name:         "No input type AsyncAPI 3.0 Spec YAML",
+			inputFile:    "./testdata/asyncapi-v3-sample.yaml",
+			expectedType: AsyncAPI,
+		},
+		{
+			name:         "AsyncAPI 3.0 Spec YAML",
+			inputFile:    "./testdata/asyncapi-v3-sample.yaml",
+			inputType:    AsyncAPI,
+			expectedType: AsyncAPI,
+		},
 		{
 			name:         "No input type Raml 1.0 spec",
 			inputFile:    "./testdata/raml_10.raml",
@@ -130,6 +156,79 @@ func TestSpecDiscovery(t *testing.T) {
 			inputFile:    "./testdata/raml_08.raml",
 			expectedType: Raml,
 		},
+		{
+			name:         "No input type OpenRPC Spec",
+			inputFile:    "./testdata/openrpc-petstore.json",
+			expectedType: OpenRPC,
+		},
+		{
+			name:         "OpenRPC input type with OpenRPC Spec",
+			inputFile:    "./testdata/openrpc-petstore.json",
+			inputType:    OpenRPC,
+			expectedType: OpenRPC,
+		},
+		{
+			name:      "OpenRPC input type with OAS3 Spec",
+			inputFile: "./testdata/petstore-openapi3.json",
+			inputType: OpenRPC,
+			parseErr:  true,
+		},
+		{
+			name:         "No input type MCP server manifest",
+			inputFile:    "./testdata/mcp-server.json",
+			expectedType: Mcp,
+		},
+		{
+			name:         "MCP input type with MCP server manifest",
+			inputFile:    "./testdata/mcp-server.json",
+			inputType:    Mcp,
+			expectedType: Mcp,
+		},
+		{
+			name:      "MCP input type with Protobuf Spec",
+			inputFile: "./testdata/petstore.proto",
+			parseErr:  true,
+			inputType: Mcp,
+		},
+		{
+			name:         "No input type A2A agent card",
+			inputFile:    "./testdata/a2a-agent-card.json",
+			expectedType: A2a,
+		},
+		{
+			name:      "A2A input type with WSDL Spec",
+			inputFile: "./testdata/weather.xml",
+			parseErr:  true,
+			inputType: A2a,
+		},
+		{
+			name:         "No input type GraphQL SDL",
+			inputFile:    "./testdata/graphql-schema.graphql",
+			expectedType: GraphQL,
+		},
+		{
+			name:         "No input type GraphQL introspection",
+			inputFile:    "./testdata/graphql-introspection.json",
+			expectedType: GraphQL,
+		},
+		{
+			name:         "GraphQL input type with GraphQL SDL",
+			inputFile:    "./testdata/graphql-schema.graphql",
+			inputType:    GraphQL,
+			expectedType: GraphQL,
+		},
+		{
+			name:      "GraphQL input type with OAS3 Spec",
+			inputFile: "./testdata/petstore-openapi3.json",
+			parseErr:  true,
+			inputType: GraphQL,
+		},
+		{
+			name:      "GraphQL input type with Protobuf Spec",
+			inputFile: "./testdata/petstore.proto",
+			parseErr:  true,
+			inputType: GraphQL,
+		},
 		{
 			name:         "No input type Unstructured",
 			inputFile:    "./testdata/multiplication.thrift",
@@ -150,6 +249,25 @@ func TestSpecDiscovery(t *testing.T) {
 			inputType:       Oas3,
 			stripExtensions: true,
 		},
+		{
+			name:         "No input type OAS3.1 Spec YAML",
+			inputFile:    "./testdata/petstore-openapi31.yaml",
+			expectedType: Oas3,
+		},
+		{
+			name:            "OAS3.1 Spec with strip extensions",
+			inputFile:       "./testdata/petstore-openapi31.yaml",
+			expectedType:    Oas3,
+			inputType:       Oas3,
+			stripExtensions: true,
+		},
+		{
+			name:         "OAS3.1 Spec with strip auth",
+			inputFile:    "./testdata/petstore-openapi31.yaml",
+			expectedType: Oas3,
+			inputType:    Oas3,
+			stripAuth:    true,
+		},
 		{
 			name:            "OAS2 Spec with strip extensions",
 			inputFile:       "./testdata/petstore-swagger2.json",
@@ -190,16 +308,28 @@ func TestSpecDiscovery(t *testing.T) {
 				ValidateOAS2Processors(t, specParser, tc.inputFile, tc.stripExtensions, tc.stripAuth)
 			case Wsdl:
 				_, ok = specProcessor.(*wsdlProcessor)
-				ValidateWsdlProcessors(t, specParser)
+				ValidateWsdlProcessors(t, specParser, tc.inputFile)
 			case Protobuf:
 				_, ok = specProcessor.(*protobufProcessor)
-				ValidateProtobufProcessors(t, specParser)
+				ValidateProtobufProcessors(t, specParser, tc.inputFile)
 			case AsyncAPI:
 				_, ok = specProcessor.(*asyncAPIProcessor)
-				ValidateAsyncAPIProcessors(t, specParser)
+				ValidateAsyncAPIProcessors(t, specParser, tc.inputFile)
 			case Raml:
 				_, ok = specProcessor.(*ramlProcessor)
 				ValidateRamlProcessors(t, specParser, tc.inputFile)
+			case OpenRPC:
+				_, ok = specProcessor.(*openRPCProcessor)
+				ValidateOpenRPCProcessors(t, specParser)
+			case Mcp:
+				_, ok = specProcessor.(*mcpProcessor)
+				ValidateMcpProcessors(t, specParser)
+			case A2a:
+				_, ok = specProcessor.(*a2aProcessor)
+				ValidateA2AProcessors(t, specParser)
+			case GraphQL:
+				_, ok = specProcessor.(*graphQLProcessor)
+				ValidateGraphQLProcessors(t, specParser, tc.inputFile)
 			case Unstructured:
 				_, ok = specProcessor.(*unstructuredProcessor)
 			}
@@ -346,6 +476,12 @@ func ValidateOAS3Processors(t *testing.T, specParser SpecResourceParser, stripAu
 	if processor.spec.Paths != nil {
 		assert.Empty(t, processor.spec.Paths.Extensions)
 	}
+	for _, webhook := range processor.spec.Webhooks {
+		assert.Empty(t, webhook.Extensions)
+		if webhook.Post != nil {
+			assert.Empty(t, webhook.Post.Extensions)
+		}
+	}
 	if processor.spec.Components != nil {
 		assert.Empty(t, processor.spec.Components.Extensions)
 	}
@@ -441,26 +577,156 @@ func ValidateOAS2Processors(t *testing.T, specParser SpecResourceParser, inputFi
 	}
 }
 
-func ValidateWsdlProcessors(t *testing.T, specParser SpecResourceParser) {
+func ValidateWsdlProcessors(t *testing.T, specParser SpecResourceParser, inputFile string) {
 	specProcessor := specParser.GetSpecProcessor()
 	endPoints, err := specProcessor.GetEndpoints()
 
 	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with wsdl")
+	if inputFile == "./testdata/wsdl20-pets.xml" {
+		assert.Len(t, endPoints, 2)
+		assert.Equal(t, int32(443), endPoints[0].Port)
+		assert.Equal(t, "/soap", endPoints[0].BasePath)
+		assert.Equal(t, int32(8080), endPoints[1].Port)
+		assert.Equal(t, "http", endPoints[1].Protocol)
+		assert.Equal(t, "v2", specProcessor.GetVersion())
+		assert.Equal(t, "Manage the pets of the pet store", specProcessor.GetDescription())
+
+		ports := specProcessor.(*wsdlProcessor).GetServices()[0].Ports
+		assert.Equal(t, "1.2", ports[0].SOAPVersion)
+		assert.Equal(t, "1.1", ports[1].SOAPVersion)
+		return
+	}
 	assert.Len(t, endPoints, 2, "The returned end points array did not have exactly 2 endpoints")
 	assert.Equal(t, "beano.com", endPoints[0].Host, "The returned end point had an unexpected value for it's host")
 	assert.Equal(t, int32(8065), endPoints[0].Port, "The returned end point had an unexpected value for it's port")
 	assert.Equal(t, "https", endPoints[0].Protocol, "The returned end point had an unexpected value for it's protocol")
 }
 
-func ValidateProtobufProcessors(t *testing.T, specParser SpecResourceParser) {
+func ValidateProtobufProcessors(t *testing.T, specParser SpecResourceParser, inputFile string) {
 	specProcessor := specParser.GetSpecProcessor()
 	endPoints, err := specProcessor.GetEndpoints()
+	processor := specProcessor.(*protobufProcessor)
 
 	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with protobuf")
-	assert.Len(t, endPoints, 0, "The returned end points array is not empty")
+	if inputFile == "./testdata/petstore.proto" {
+		assert.Len(t, endPoints, 0, "The returned end points array is not empty")
+		assert.Equal(t, "", specProcessor.GetVersion())
+		assert.Len(t, processor.GetServices(), 1)
+		assert.Equal(t, "swaggerpetstore.SwaggerPetstoreService", processor.GetServices()[0].Name)
+		assert.Len(t, processor.GetServices()[0].Methods, 5)
+		assert.Equal(t, "POST", processor.GetServices()[0].Methods[0].HTTPMethod)
+		assert.Equal(t, "/api/pets", processor.GetServices()[0].Methods[0].HTTPPath)
+		return
+	}
+
+	assert.Len(t, endPoints, 2)
+	assert.Equal(t, EndpointDefinition{Host: "greeter.example.com", Port: 443, Protocol: "grpc", BasePath: "/",
+		Details: map[string]interface{}{"service": "example.greeter.v2.Greeter"}}, endPoints[0])
+	assert.Equal(t, int32(8443), endPoints[1].Port)
+	assert.Equal(t, "v2", specProcessor.GetVersion())
+	assert.Equal(t, "Greeting services for the example company", specProcessor.GetDescription())
+
+	services := processor.GetServices()
+	assert.Len(t, services, 2)
+	assert.Equal(t, "Greeter sends greetings", services[0].Description)
+	modes := []string{}
+	for _, m := range services[0].Methods {
+		modes = append(modes, m.StreamingMode)
+	}
+	assert.Equal(t, []string{GRPCUnary, GRPCServerStreaming, GRPCClientStreaming, GRPCBidiStreaming}, modes)
+	assert.Equal(t, "Sends a greeting", services[0].Methods[0].Description)
+	assert.Equal(t, "GET", services[0].Methods[0].HTTPMethod)
+	assert.Len(t, processor.getRevisionDetails()[grpcServicesDetailKey], 2)
 }
 
-func ValidateAsyncAPIProcessors(t *testing.T, specParser SpecResourceParser) {
+func TestProtobufVersionFromPackage(t *testing.T) {
+	tests := map[string]string{
+		"foo.v2":          "v2",
+		"foo.bar.v1beta1": "v1beta1",
+		"v3":              "v3",
+		"foo.v2alpha":     "v2alpha",
+		"foo.bar":         "",
+		"foo.v2.bar":      "",
+		"foo.version1":    "",
+		"":                "",
+	}
+	for pkg, expected := range tests {
+		p := newProtobufProcessor(&protobufDefinition{packageName: pkg}, nil)
+		assert.Equal(t, expected, p.GetVersion(), pkg)
+	}
+}
+
+func TestProtobufFileDescriptorSet(t *testing.T) {
+	serviceOpts := &descriptorpb.ServiceOptions{}
+	serviceOpts.ProtoReflect().SetUnknown(protowire.AppendString(protowire.AppendTag(nil, protoDefaultHostFieldID, protowire.BytesType), "orders.example.com:9090"))
+	httpRule := protowire.AppendString(protowire.AppendTag(nil, 4, protowire.BytesType), "/v1/orders")
+	methodOpts := &descriptorpb.MethodOptions{}
+	methodOpts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, protoHTTPFieldID, protowire.BytesType), httpRule))
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    protobuf.String("google/protobuf/empty.proto"),
+				Package: protobuf.String("google.protobuf"),
+			},
+			{
+				Name:    protobuf.String("orders.proto"),
+				Package: protobuf.String("shop.orders.v1"),
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{
+						Name:    protobuf.String("Orders"),
+						Options: serviceOpts,
+						Method: []*descriptorpb.MethodDescriptorProto{
+							{
+								Name:       protobuf.String("CreateOrder"),
+								InputType:  protobuf.String(".shop.orders.v1.Order"),
+								OutputType: protobuf.String(".shop.orders.v1.Order"),
+								Options:    methodOpts,
+							},
+							{
+								Name:            protobuf.String("WatchOrders"),
+								InputType:       protobuf.String(".google.protobuf.Empty"),
+								OutputType:      protobuf.String(".shop.orders.v1.Order"),
+								ServerStreaming: protobuf.Bool(true),
+							},
+						},
+					},
+				},
+				SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+					Location: []*descriptorpb.SourceCodeInfo_Location{
+						{Path: []int32{6, 0}, LeadingComments: protobuf.String(" Order management\n")},
+						{Path: []int32{6, 0, 2, 1}, LeadingComments: protobuf.String(" Streams new orders\n")},
+					},
+				},
+			},
+		},
+	}
+	spec, err := protobuf.Marshal(set)
+	assert.Nil(t, err)
+
+	parser := NewSpecResourceParser(spec, "")
+	assert.Nil(t, parser.Parse())
+	processor, ok := parser.GetSpecProcessor().(*protobufProcessor)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", processor.GetVersion())
+	assert.Equal(t, "Order management", processor.GetDescription())
+
+	endpoints, err := processor.GetEndpoints()
+	assert.Nil(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "orders.example.com", endpoints[0].Host)
+	assert.Equal(t, int32(9090), endpoints[0].Port)
+
+	services := processor.GetServices()
+	assert.Len(t, services, 1)
+	assert.Equal(t, "shop.orders.v1.Orders", services[0].Name)
+	assert.Equal(t, ProtobufMethod{Name: "CreateOrder", InputType: "shop.orders.v1.Order", OutputType: "shop.orders.v1.Order",
+		StreamingMode: GRPCUnary, HTTPMethod: "POST", HTTPPath: "/v1/orders"}, services[0].Methods[0])
+	assert.Equal(t, GRPCServerStreaming, services[0].Methods[1].StreamingMode)
+	assert.Equal(t, "Streams new orders", services[0].Methods[1].Description)
+}
+
+func ValidateAsyncAPIProcessors(t *testing.T, specParser SpecResourceParser, inputFile string) {
 	specProcessor := specParser.GetSpecProcessor()
 	endPoints, err := specProcessor.GetEndpoints()
 
@@ -468,7 +734,13 @@ func ValidateAsyncAPIProcessors(t *testing.T, specParser SpecResourceParser) {
 	assert.Equal(t, "api.company.com", endPoints[0].Host)
 	assert.Equal(t, int32(5676), endPoints[0].Port)
 	assert.Equal(t, "mqtt", endPoints[0].Protocol)
-	assert.Equal(t, "", endPoints[0].BasePath)
+	if inputFile == "./testdata/asyncapi-v3-sample.yaml" {
+		assert.Equal(t, "/accounts", endPoints[0].BasePath)
+		assert.Equal(t, "2.1.0", specProcessor.GetVersion())
+	} else {
+		assert.Equal(t, "", endPoints[0].BasePath)
+		assert.Equal(t, "1.0.0", specProcessor.GetVersion())
+	}
 	assert.Equal(t, map[string]interface{}{
 		"solace": map[string]interface{}{
 			"msgVpn":  "apim-test",
@@ -502,6 +774,135 @@ func ValidateRamlProcessors(t *testing.T, specParser SpecResourceParser, inputFi
 	}
 }
 
+func ValidateOpenRPCProcessors(t *testing.T, specParser SpecResourceParser) {
+	specProcessor := specParser.GetSpecProcessor()
+	endPoints, err := specProcessor.GetEndpoints()
+
+	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with openrpc")
+	assert.Len(t, endPoints, 2)
+	assert.Equal(t, EndpointDefinition{Host: "api.petstore.example.com", Port: 443, Protocol: "https", BasePath: "/rpc",
+		Details: map[string]interface{}{"server": "production"}}, endPoints[0])
+	assert.Equal(t, EndpointDefinition{Host: "ws.petstore.example.com", Port: 8546, Protocol: "wss", BasePath: "/",
+		Details: map[string]interface{}{"server": "websocket"}}, endPoints[1])
+	assert.Equal(t, "1.0.3", specProcessor.GetVersion())
+	assert.Equal(t, "A sample JSON-RPC pet store", specProcessor.GetDescription())
+	assert.Equal(t, mimeApplicationJSON, specParser.getResourceContentType())
+
+	processor := specProcessor.(*openRPCProcessor)
+	assert.Equal(t, "Petstore", processor.GetTitle())
+	methods := processor.GetMethods()
+	assert.Len(t, methods, 2)
+	assert.Equal(t, "list_pets", methods[0].Name)
+	assert.Equal(t, "limit", methods[0].Params[0].Name)
+	assert.Equal(t, "pets", methods[0].Result.Name)
+	assert.True(t, methods[1].Deprecated)
+	assert.Equal(t, "by-name", methods[1].ParamStructure)
+	assert.Equal(t, map[string]interface{}{openRPCMethodsDetailKey: []interface{}{"list_pets", "get_pet"}}, processor.getRevisionDetails())
+}
+
+func ValidateMcpProcessors(t *testing.T, specParser SpecResourceParser) {
+	specProcessor := specParser.GetSpecProcessor()
+	endPoints, err := specProcessor.GetEndpoints()
+
+	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with mcp")
+	assert.Len(t, endPoints, 2)
+	assert.Equal(t, "mcp.example.com", endPoints[0].Host)
+	assert.Equal(t, int32(443), endPoints[0].Port)
+	assert.Equal(t, "https", endPoints[0].Protocol)
+	assert.Equal(t, "/weather/mcp", endPoints[0].BasePath)
+	assert.Equal(t, MCPTransportStreamableHTTP, endPoints[0].Details["transport"])
+	assert.Equal(t, int32(8080), endPoints[1].Port)
+	assert.Equal(t, MCPTransportSSE, endPoints[1].Details["transport"])
+	assert.Equal(t, "1.2.0", specProcessor.GetVersion())
+	assert.Equal(t, "Weather forecasts and alerts", specProcessor.GetDescription())
+	assert.Equal(t, mimeApplicationJSON, specParser.getResourceContentType())
+
+	processor := specProcessor.(*mcpProcessor)
+	assert.Equal(t, "Weather Server", processor.GetTitle())
+	assert.Len(t, processor.GetTools(), 1)
+	assert.Equal(t, "object", processor.GetTools()[0].InputSchema["type"])
+	assert.Len(t, processor.GetResources(), 2)
+	assert.Len(t, processor.GetPrompts(), 1)
+
+	details := processor.getRevisionDetails()
+	assert.Len(t, details[mcpToolsDetailKey], 1)
+	assert.Len(t, details[mcpResourcesDetailKey], 2)
+	assert.Len(t, details[mcpPromptsDetailKey], 1)
+}
+
+func ValidateA2AProcessors(t *testing.T, specParser SpecResourceParser) {
+	specProcessor := specParser.GetSpecProcessor()
+	endPoints, err := specProcessor.GetEndpoints()
+
+	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with a2a")
+	assert.Len(t, endPoints, 3)
+	assert.Equal(t, "agents.example.com", endPoints[0].Host)
+	assert.Equal(t, int32(443), endPoints[0].Port)
+	assert.Equal(t, "/a2a/v1", endPoints[0].BasePath)
+	assert.Equal(t, A2ATransportJSONRPC, endPoints[0].Details["transport"])
+	assert.Equal(t, int32(8443), endPoints[1].Port)
+	assert.Equal(t, A2ATransportHTTPJSON, endPoints[1].Details["transport"])
+	assert.Equal(t, "grpc.agents.example.com", endPoints[2].Host)
+	assert.Equal(t, int32(50051), endPoints[2].Port)
+	assert.Equal(t, A2ATransportGRPC, endPoints[2].Details["transport"])
+	assert.Equal(t, "2.1.0", specProcessor.GetVersion())
+	assert.Equal(t, "Plans trips and books travel", specProcessor.GetDescription())
+
+	processor := specProcessor.(*a2aProcessor)
+	assert.Equal(t, "Travel Agent", processor.GetTitle())
+	assert.Len(t, processor.GetSkills(), 2)
+
+	processor.ParseAuthInfo()
+	assert.Equal(t, []string{Apikey, Oauth}, processor.GetAuthPolicies())
+	assert.Equal(t, []APIKeyInfo{{Name: "X-API-Key", Location: "header"}}, processor.GetAPIKeyInfo())
+	assert.Equal(t, map[string]string{"trips:read": "read trips", "trips:write": ""}, processor.GetOAuthScopes())
+
+	details := processor.getRevisionDetails()
+	assert.Len(t, details[a2aSkillsDetailKey], 2)
+	assert.Equal(t, true, details[a2aCapabilitiesDetailKey].(map[string]interface{})["streaming"])
+}
+
+func ValidateGraphQLProcessors(t *testing.T, specParser SpecResourceParser, inputFile string) {
+	specProcessor := specParser.GetSpecProcessor()
+	endPoints, err := specProcessor.GetEndpoints()
+
+	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with graphql")
+	assert.Len(t, endPoints, 0)
+	assert.Equal(t, "", specProcessor.GetVersion())
+
+	processor := specProcessor.(*graphQLProcessor)
+	operations := processor.GetOperations()
+	details := processor.getRevisionDetails()
+	if inputFile == "./testdata/graphql-introspection.json" {
+		assert.Equal(t, "Weather service", specProcessor.GetDescription())
+		assert.Equal(t, mimeApplicationJSON, specParser.getResourceContentType())
+		assert.Equal(t, []GraphQLOperation{{Type: "query", Name: "forecast", Description: "Forecast for a city", ReturnType: "[Forecast!]"}}, operations)
+		return
+	}
+
+	assert.Equal(t, "The bookstore catalog and ordering API", specProcessor.GetDescription())
+	assert.Len(t, operations, 6)
+	assert.Equal(t, GraphQLOperation{Type: "query", Name: "book", Description: "Find a book by id", ReturnType: "Book"}, operations[0])
+	assert.Equal(t, []interface{}{"book", "books", "search", "authors"}, details[graphQLQueriesDetailKey])
+	assert.Equal(t, []interface{}{"placeOrder"}, details[graphQLMutationsDetailKey])
+	assert.Equal(t, []interface{}{"orderPlaced"}, details[graphQLSubscriptionsDetailKey])
+}
+
+func TestGraphQLSpecValidation(t *testing.T) {
+	tests := map[string]string{
+		"syntax error":        "type Query { books: [Book }",
+		"undefined type":      "type Query { books: [Book] }",
+		"missing query type":  "type Book { id: ID }",
+		"introspection error": `{"data":{"__schema":{"queryType":{"name":"Query"},"types":[]}}}`,
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := NewSpecResourceParser([]byte(spec), GraphQL)
+			assert.NotNil(t, parser.Parse())
+		})
+	}
+}
+
 func isInList[T comparable](actual T, validValues []T) bool {
 	for i := range validValues {
 		if validValues[i] == actual {
@@ -522,4 +923,162 @@ func TestA2ASpecParser(t *testing.T) {
 	p := parser.GetSpecProcessor()
 	assert.Equal(t, A2a, p.GetResourceType())
 	assert.Equal(t, agentCard, p.GetSpecBytes())
+	assert.Equal(t, "1.0.0", p.GetVersion())
+
+	endpoints, err := p.GetEndpoints()
+	assert.Nil(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "/mcp", endpoints[0].BasePath)
+
+	// the same card is discovered without a resource type
+	parser = NewSpecResourceParser(agentCard, "")
+	assert.Nil(t, parser.Parse())
+	assert.Equal(t, A2a, parser.GetSpecProcessor().GetResourceType())
+
+	// an agent card requires a name
+	parser = NewSpecResourceParser([]byte(`{"url":"https://example.com"}`), A2a)
+	assert.NotNil(t, parser.Parse())
+}
+
+func TestMCPSpecParser(t *testing.T) {
+	tests := map[string]struct {
+		spec              string
+		specType          string
+		parseErr          bool
+		expectedType      string
+		expectedEndpoints int
+		expectedVersion   string
+	}{
+		"yaml manifest with remotes is discovered": {
+			spec:              "name: io.example/yaml\nversion: 2.0.1\nremotes:\n  - type: sse\n    url: https://mcp.example.com/sse\n",
+			expectedType:      Mcp,
+			expectedEndpoints: 1,
+			expectedVersion:   "2.0.1",
+		},
+		"tools list with input schemas is discovered": {
+			spec:              `{"name":"tools-only","tools":[{"name":"echo","inputSchema":{"type":"object"}}]}`,
+			expectedType:      Mcp,
+			expectedEndpoints: 0,
+		},
+		"single url used as streamable http endpoint": {
+			spec:              `{"name":"single","url":"https://example.com/mcp"}`,
+			specType:          Mcp,
+			expectedType:      Mcp,
+			expectedEndpoints: 1,
+		},
+		"manifest without a name fails": {
+			spec:     `{"tools":[]}`,
+			specType: Mcp,
+			parseErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := NewSpecResourceParser([]byte(tc.spec), tc.specType)
+			err := parser.Parse()
+			if tc.parseErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+
+			p := parser.GetSpecProcessor()
+			assert.Equal(t, tc.expectedType, p.GetResourceType())
+			endpoints, err := p.GetEndpoints()
+			assert.Nil(t, err)
+			assert.Len(t, endpoints, tc.expectedEndpoints)
+			assert.Equal(t, tc.expectedVersion, p.GetVersion())
+		})
+	}
+}
+
+func TestOAS31SpecProcessor(t *testing.T) {
+	specParser, err := createSpecParser("./testdata/petstore-openapi31.yaml", "")
+	assert.Nil(t, err)
+	processor, ok := specParser.GetSpecProcessor().(*oas3SpecProcessor)
+	assert.True(t, ok)
+	assert.Equal(t, "1.0.5", processor.GetVersion())
+	assert.Equal(t, "Swagger Petstore - OpenAPI 3.1", processor.GetTitle())
+
+	processor.ParseAuthInfo()
+	assert.Equal(t, []string{Apikey, Oauth}, processor.GetAuthPolicies())
+	assert.Equal(t, []APIKeyInfo{{Location: "header", Name: "api_key"}}, processor.GetAPIKeyInfo())
+	assert.Len(t, processor.GetOAuthScopes(), 2)
+
+	processor.AddSecuritySchemes(processor.GetSecurityBuilder().HTTPBasic().Build())
+	oas.SetOAS3Servers([]string{"https://gateway.example.com/pets"}, processor.spec)
+
+	specDef := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(processor.GetSpecBytes(), &specDef))
+	assert.Equal(t, "3.1.0", specDef["openapi"])
+	assert.Equal(t, "https://spec.openapis.org/oas/3.1/dialect/base", specDef["jsonSchemaDialect"])
+	assert.Contains(t, specDef["webhooks"], "newPet")
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://gateway.example.com/pets"}}, specDef["servers"])
+
+	components := specDef["components"].(map[string]interface{})
+	assert.Contains(t, components["securitySchemes"], "basicAuth")
+	assert.Contains(t, components["securitySchemes"], "mtls")
+	pet := components["schemas"].(map[string]interface{})["Pet"].(map[string]interface{})
+	assert.Contains(t, pet, "$defs")
+	properties := pet["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"string", "null"}, properties["nickname"].(map[string]interface{})["type"])
+	assert.Equal(t, "pet", properties["kind"].(map[string]interface{})["const"])
+	assert.Equal(t, "pet status in the store", properties["status"].(map[string]interface{})["description"])
+
+	// re-parsing the updated spec must succeed
+	_, err = oas.ParseOAS3(processor.GetSpecBytes())
+	assert.Nil(t, err)
+}
+
+func TestOpenRPCSpecParser(t *testing.T) {
+	tests := map[string]struct {
+		spec        string
+		parseErr    bool
+		contentType string
+	}{
+		"yaml document": {
+			spec: `openrpc: 1.3.2
+info:
+  title: node
+  version: 0.1.0
+methods:
+  - name: eth_blockNumber
+    params: []
+    result:
+      name: blockNumber
+      schema:
+        type: string
+`,
+			contentType: mimeApplicationYAML,
+		},
+		"invalid openrpc version": {
+			spec:     `{"openrpc": "2.0.0", "info": {"title": "node", "version": "1"}, "methods": []}`,
+			parseErr: true,
+		},
+		"no title": {
+			spec:     `{"openrpc": "1.2.6", "info": {"version": "1"}, "methods": []}`,
+			parseErr: true,
+		},
+		"no methods": {
+			spec:     `{"openrpc": "1.2.6", "info": {"title": "node", "version": "1"}}`,
+			parseErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			specParser := NewSpecResourceParser([]byte(tc.spec), "")
+			err := specParser.Parse()
+			if tc.parseErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, OpenRPC, specParser.GetSpecProcessor().GetResourceType())
+			assert.Equal(t, tc.contentType, specParser.getResourceContentType())
+			endpoints, err := specParser.GetSpecProcessor().GetEndpoints()
+			assert.Nil(t, err)
+			assert.Empty(t, endpoints)
+		})
+	}
 }
@@ -1,35 +1,338 @@
 package apic
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/emicklei/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// gRPC method streaming modes
+const (
+	GRPCUnary           = "unary"
+	GRPCClientStreaming = "client_streaming"
+	GRPCServerStreaming = "server_streaming"
+	GRPCBidiStreaming   = "bidi_streaming"
+)
+
+const (
+	grpcProtocol            = "grpc"
+	grpcDefaultPort         = 443
+	grpcServicesDetailKey   = "grpcServices"
+	grpcServiceDetailKey    = "service"
+	protoDefaultHostOption  = "(google.api.default_host)"
+	protoHTTPOption         = "(google.api.http)"
+	protoDefaultHostFieldID = 1049     // google.api.default_host extension of ServiceOptions
+	protoHTTPFieldID        = 72295728 // google.api.http extension of MethodOptions
+	protoPackagePath        = 2        // FileDescriptorProto package field
+	protoServicePath        = 6        // FileDescriptorProto service field
+	protoMethodPath         = 2        // ServiceDescriptorProto method field
 )
 
+var (
+	protoPackageVersionRe = regexp.MustCompile(`(?:^|\.)(v\d+(?:(?:alpha|beta)\d*)?)$`)
+	httpRuleFields        = map[protowire.Number]string{2: "GET", 3: "PUT", 4: "POST", 5: "DELETE", 6: "PATCH"}
+)
+
+// ProtobufMethod - an rpc method of a gRPC service
+type ProtobufMethod struct {
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	InputType       string `json:"inputType"`
+	OutputType      string `json:"outputType"`
+	ClientStreaming bool   `json:"clientStreaming,omitempty"`
+	ServerStreaming bool   `json:"serverStreaming,omitempty"`
+	StreamingMode   string `json:"streamingMode"`
+	HTTPMethod      string `json:"httpMethod,omitempty"`
+	HTTPPath        string `json:"httpPath,omitempty"`
+}
+
+// ProtobufService - a gRPC service and its methods
+type ProtobufService struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	DefaultHost string           `json:"defaultHost,omitempty"`
+	Methods     []ProtobufMethod `json:"methods"`
+}
+
+// protobufDefinition - the services of a .proto file or a FileDescriptorSet
+type protobufDefinition struct {
+	packageName string
+	description string
+	services    []ProtobufService
+}
+
 type protobufProcessor struct {
-	protobufDef *proto.Proto
+	protobufDef *protobufDefinition
 	spec        []byte
 }
 
-func newProtobufProcessor(protobufDef *proto.Proto, spec []byte) *protobufProcessor {
+func newProtobufProcessor(protobufDef *protobufDefinition, spec []byte) *protobufProcessor {
 	return &protobufProcessor{protobufDef: protobufDef, spec: spec}
 }
 
+// newProtobufDefinition - reads the package and services from a parsed .proto file
+func newProtobufDefinition(def *proto.Proto) *protobufDefinition {
+	pd := &protobufDefinition{}
+	for _, e := range def.Elements {
+		if p, ok := e.(*proto.Package); ok {
+			pd.packageName = p.Name
+			pd.description = protoComment(p.Comment)
+		}
+	}
+	for _, e := range def.Elements {
+		if s, ok := e.(*proto.Service); ok {
+			pd.services = append(pd.services, newProtobufService(pd.packageName, s))
+		}
+	}
+	return pd
+}
+
+func newProtobufService(packageName string, s *proto.Service) ProtobufService {
+	service := ProtobufService{
+		Name:        qualifiedProtoName(packageName, s.Name),
+		Description: protoComment(s.Comment),
+		Methods:     []ProtobufMethod{},
+	}
+	for _, e := range s.Elements {
+		switch element := e.(type) {
+		case *proto.Option:
+			if element.Name == protoDefaultHostOption {
+				service.DefaultHost = element.Constant.Source
+			}
+		case *proto.RPC:
+			service.Methods = append(service.Methods, newProtobufMethod(element))
+		}
+	}
+	return service
+}
+
+func newProtobufMethod(rpc *proto.RPC) ProtobufMethod {
+	method := ProtobufMethod{
+		Name:            rpc.Name,
+		Description:     protoComment(rpc.Comment),
+		InputType:       rpc.RequestType,
+		OutputType:      rpc.ReturnsType,
+		ClientStreaming: rpc.StreamsRequest,
+		ServerStreaming: rpc.StreamsReturns,
+		StreamingMode:   streamingMode(rpc.StreamsRequest, rpc.StreamsReturns),
+	}
+	for _, e := range rpc.Elements {
+		option, ok := e.(*proto.Option)
+		if !ok || option.Name != protoHTTPOption {
+			continue
+		}
+		for _, c := range option.AggregatedConstants {
+			verb := strings.ToUpper(c.Name)
+			if c.Literal != nil && (verb == "GET" || verb == "PUT" || verb == "POST" || verb == "DELETE" || verb == "PATCH") {
+				method.HTTPMethod, method.HTTPPath = verb, c.Literal.Source
+			}
+		}
+	}
+	return method
+}
+
+// parseFileDescriptorSet - reads the package and services from a binary FileDescriptorSet
+func parseFileDescriptorSet(spec []byte) (*protobufDefinition, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := protobuf.Unmarshal(spec, set); err != nil {
+		return nil, err
+	}
+	if len(set.GetFile()) == 0 {
+		return nil, errors.New("invalid protobuf file descriptor set")
+	}
+	for _, f := range set.GetFile() {
+		if !strings.HasSuffix(f.GetName(), ".proto") {
+			return nil, errors.New("invalid protobuf file descriptor set")
+		}
+	}
+
+	pd := &protobufDefinition{}
+	for _, f := range set.GetFile() {
+		comments := descriptorComments(f)
+		if pd.packageName == "" && len(f.GetService()) > 0 {
+			pd.packageName = f.GetPackage()
+			pd.description = comments[fmt.Sprint([]int32{protoPackagePath})]
+		}
+		for i, s := range f.GetService() {
+			servicePath := []int32{protoServicePath, int32(i)}
+			service := ProtobufService{
+				Name:        qualifiedProtoName(f.GetPackage(), s.GetName()),
+				Description: comments[fmt.Sprint(servicePath)],
+				DefaultHost: unknownStringField(s.GetOptions(), protoDefaultHostFieldID),
+				Methods:     []ProtobufMethod{},
+			}
+			for j, m := range s.GetMethod() {
+				method := ProtobufMethod{
+					Name:            m.GetName(),
+					Description:     comments[fmt.Sprint(append(servicePath, protoMethodPath, int32(j)))],
+					InputType:       strings.TrimPrefix(m.GetInputType(), "."),
+					OutputType:      strings.TrimPrefix(m.GetOutputType(), "."),
+					ClientStreaming: m.GetClientStreaming(),
+					ServerStreaming: m.GetServerStreaming(),
+					StreamingMode:   streamingMode(m.GetClientStreaming(), m.GetServerStreaming()),
+				}
+				method.HTTPMethod, method.HTTPPath = descriptorHTTPRule(m.GetOptions())
+				service.Methods = append(service.Methods, method)
+			}
+			pd.services = append(pd.services, service)
+		}
+	}
+	return pd, nil
+}
+
+// descriptorComments - the leading comments of the file, keyed by the element path
+func descriptorComments(f *descriptorpb.FileDescriptorProto) map[string]string {
+	comments := map[string]string{}
+	for _, loc := range f.GetSourceCodeInfo().GetLocation() {
+		if loc.GetLeadingComments() != "" {
+			comments[fmt.Sprint(loc.GetPath())] = strings.TrimSpace(loc.GetLeadingComments())
+		}
+	}
+	return comments
+}
+
+// unknownStringField - reads a string extension field from the options without its go type registered
+func unknownStringField(options protobuf.Message, field protowire.Number) string {
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return ""
+	}
+	value := ""
+	walkProtoFields(options.ProtoReflect().GetUnknown(), func(num protowire.Number, data []byte) {
+		if num == field {
+			value = string(data)
+		}
+	})
+	return value
+}
+
+// descriptorHTTPRule - reads the verb and path of the google.api.http method option
+func descriptorHTTPRule(options *descriptorpb.MethodOptions) (string, string) {
+	if options == nil {
+		return "", ""
+	}
+	verb, path := "", ""
+	walkProtoFields(options.ProtoReflect().GetUnknown(), func(num protowire.Number, rule []byte) {
+		if num != protoHTTPFieldID {
+			return
+		}
+		walkProtoFields(rule, func(num protowire.Number, data []byte) {
+			if v, ok := httpRuleFields[num]; ok {
+				verb, path = v, string(data)
+			}
+		})
+	})
+	return verb, path
+}
+
+// walkProtoFields - calls the handler for each length delimited field in the wire data
+func walkProtoFields(b []byte, handler func(protowire.Number, []byte)) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+		if typ == protowire.BytesType {
+			data, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				return
+			}
+			handler(num, data)
+			b = b[m:]
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return
+		}
+		b = b[m:]
+	}
+}
+
+func streamingMode(clientStreaming, serverStreaming bool) string {
+	switch {
+	case clientStreaming && serverStreaming:
+		return GRPCBidiStreaming
+	case clientStreaming:
+		return GRPCClientStreaming
+	case serverStreaming:
+		return GRPCServerStreaming
+	}
+	return GRPCUnary
+}
+
+func qualifiedProtoName(packageName, name string) string {
+	if packageName == "" {
+		return name
+	}
+	return packageName + "." + name
+}
+
+func protoComment(c *proto.Comment) string {
+	if c == nil {
+		return ""
+	}
+	lines := make([]string, 0, len(c.Lines))
+	for _, l := range c.Lines {
+		lines = append(lines, strings.TrimSpace(l))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
 func (p *protobufProcessor) GetResourceType() string {
 	return Protobuf
 }
 
-// GetVersion -
+// GetVersion - the version suffix of the package name, i.e. v2 for foo.bar.v2
 func (p *protobufProcessor) GetVersion() string {
+	if match := protoPackageVersionRe.FindStringSubmatch(p.protobufDef.packageName); len(match) > 1 {
+		return match[1]
+	}
 	return ""
 }
 
-// GetDescription -
+// GetDescription - the package comment, falling back to the comment of the first service
 func (p *protobufProcessor) GetDescription() string {
+	if p.protobufDef.description != "" {
+		return p.protobufDef.description
+	}
+	for _, s := range p.protobufDef.services {
+		if s.Description != "" {
+			return s.Description
+		}
+	}
 	return ""
 }
 
-// GetEndpoints -
+// GetEndpoints - a grpc endpoint for each of the service default hosts
 func (p *protobufProcessor) GetEndpoints() ([]EndpointDefinition, error) {
-	return []EndpointDefinition{}, nil
+	urls := []endpointURL{}
+	for _, s := range p.protobufDef.services {
+		if s.DefaultHost == "" {
+			continue
+		}
+		urls = append(urls, endpointURL{url: s.DefaultHost, details: map[string]interface{}{grpcServiceDetailKey: s.Name}})
+	}
+	return endpointsFromURLs(urls, grpcProtocol, grpcDefaultPort)
+}
+
+// GetServices -
+func (p *protobufProcessor) GetServices() []ProtobufService {
+	return p.protobufDef.services
+}
+
+// getRevisionDetails - the services and methods to add to the revision x-agent-details
+func (p *protobufProcessor) getRevisionDetails() map[string]interface{} {
+	if len(p.protobufDef.services) == 0 {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{grpcServicesDetailKey: toDetailList(p.protobufDef.services)}
 }
 
 // GetSpecBytes -
@@ -0,0 +1,126 @@
+package apic
+
+import (
+	"sort"
+	"sync"
+)
+
+// Priorities of the built in spec discovery steps, a registered spec processor is detected before
+// the built in steps with a lower priority and after the built in steps with the same or a higher priority
+const (
+	SpecPriorityYAMLAndJSON = 400
+	SpecPriorityWsdl        = 300
+	SpecPriorityProtobuf    = 200
+	SpecPriorityGraphQL     = 100
+)
+
+var builtInResourceTypes = map[string]bool{
+	Wsdl:         true,
+	SwaggerV2:    true,
+	Oas2:         true,
+	Oas3:         true,
+	Protobuf:     true,
+	AsyncAPI:     true,
+	Unstructured: true,
+	GraphQL:      true,
+	Mcp:          true,
+	A2a:          true,
+	Raml:         true,
+	OpenRPC:      true,
+}
+
+// SpecDetector - returns true when the spec content is of the registered resource type
+type SpecDetector func(resourceSpec []byte) bool
+
+// SpecProcessorFactory - creates the spec processor for the content of the registered resource type
+type SpecProcessorFactory func(resourceSpec []byte) (SpecProcessor, error)
+
+type specDiscoveryStep struct {
+	resourceType string
+	priority     int
+	detector     SpecDetector
+	create       func(s *SpecResourceParser) (SpecProcessor, error)
+}
+
+type specProcessorRegistry struct {
+	lock       sync.RWMutex
+	registered []specDiscoveryStep
+}
+
+var specRegistry = &specProcessorRegistry{}
+
+// RegisterSpecProcessor - registers a spec processor for a custom resource type. The factory is used when the
+// resource type is set on the service body, the detector, when not nil, is used for discovering the resource type
+// of the spec and is called in priority order along with the built in discovery steps.
+func RegisterSpecProcessor(resourceType string, priority int, detector SpecDetector, factory SpecProcessorFactory) error {
+	if resourceType == "" {
+		return ErrSpecProcessorRegistration.FormatError(resourceType, "a resource type is required")
+	}
+	if factory == nil {
+		return ErrSpecProcessorRegistration.FormatError(resourceType, "a spec processor factory is required")
+	}
+	if builtInResourceTypes[resourceType] {
+		return ErrSpecProcessorRegistration.FormatError(resourceType, "the resource type is built in")
+	}
+
+	specRegistry.lock.Lock()
+	defer specRegistry.lock.Unlock()
+	for _, r := range specRegistry.registered {
+		if r.resourceType == resourceType {
+			return ErrSpecProcessorRegistration.FormatError(resourceType, "the resource type is already registered")
+		}
+	}
+	specRegistry.registered = append(specRegistry.registered, specDiscoveryStep{
+		resourceType: resourceType,
+		priority:     priority,
+		detector:     detector,
+		create: func(s *SpecResourceParser) (SpecProcessor, error) {
+			return factory(s.resourceSpec)
+		},
+	})
+	return nil
+}
+
+// UnregisterSpecProcessor - removes the spec processor registered for the resource type
+func UnregisterSpecProcessor(resourceType string) {
+	specRegistry.lock.Lock()
+	defer specRegistry.lock.Unlock()
+	for i, r := range specRegistry.registered {
+		if r.resourceType == resourceType {
+			specRegistry.registered = append(specRegistry.registered[:i], specRegistry.registered[i+1:]...)
+			return
+		}
+	}
+}
+
+// getRegistered - returns the discovery step registered for the resource type
+func (r *specProcessorRegistry) getRegistered(resourceType string) (specDiscoveryStep, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, step := range r.registered {
+		if step.resourceType == resourceType {
+			return step, true
+		}
+	}
+	return specDiscoveryStep{}, false
+}
+
+// discoverySteps - returns the built in and registered discovery steps in priority order
+func (r *specProcessorRegistry) discoverySteps() []specDiscoveryStep {
+	steps := []specDiscoveryStep{
+		{priority: SpecPriorityYAMLAndJSON, create: (*SpecResourceParser).discoverYAMLAndJSONSpec},
+		{resourceType: Wsdl, priority: SpecPriorityWsdl, create: (*SpecResourceParser).parseWSDLSpec},
+		{resourceType: Protobuf, priority: SpecPriorityProtobuf, create: (*SpecResourceParser).parseProtobufSpec},
+		{resourceType: GraphQL, priority: SpecPriorityGraphQL, create: (*SpecResourceParser).parseGraphQLSpec},
+	}
+
+	r.lock.RLock()
+	steps = append(steps, r.registered...)
+	r.lock.RUnlock()
+
+	// stable sort keeps the built in steps ahead of registered steps with the same priority
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].priority > steps[j].priority
+	})
+	return steps
+}
@@ -0,0 +1,169 @@
+package apic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const smithyType = "smithy"
+
+type smithyProcessor struct {
+	spec []byte
+}
+
+func (p *smithyProcessor) GetVersion() string {
+	return "1.0"
+}
+
+func (p *smithyProcessor) GetEndpoints() ([]EndpointDefinition, error) {
+	return []EndpointDefinition{{Host: "weather.example.com", Port: 443, Protocol: "https", BasePath: "/"}}, nil
+}
+
+func (p *smithyProcessor) GetDescription() string {
+	return "smithy model"
+}
+
+func (p *smithyProcessor) GetSpecBytes() []byte {
+	return p.spec
+}
+
+func (p *smithyProcessor) GetResourceType() string {
+	return smithyType
+}
+
+func newSmithyProcessor(spec []byte) (SpecProcessor, error) {
+	return &smithyProcessor{spec: spec}, nil
+}
+
+func isSmithy(spec []byte) bool {
+	return bytes.HasPrefix(spec, []byte("$version:"))
+}
+
+const smithyModel = `$version: "2"
+namespace example.weather
+
+service Weather {
+    version: "2006-03-01"
+}
+`
+
+func TestRegisterSpecProcessor(t *testing.T) {
+	tests := map[string]struct {
+		resourceType string
+		factory      SpecProcessorFactory
+		expectErr    bool
+	}{
+		"no resource type": {
+			factory:   newSmithyProcessor,
+			expectErr: true,
+		},
+		"no factory": {
+			resourceType: smithyType,
+			expectErr:    true,
+		},
+		"built in resource type": {
+			resourceType: Oas3,
+			factory:      newSmithyProcessor,
+			expectErr:    true,
+		},
+		"registered": {
+			resourceType: smithyType,
+			factory:      newSmithyProcessor,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer UnregisterSpecProcessor(tc.resourceType)
+			err := RegisterSpecProcessor(tc.resourceType, SpecPriorityGraphQL, isSmithy, tc.factory)
+			if tc.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			// registering the same type twice fails
+			assert.NotNil(t, RegisterSpecProcessor(tc.resourceType, SpecPriorityGraphQL, isSmithy, tc.factory))
+		})
+	}
+}
+
+func TestRegisteredSpecProcessorDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		priority     int
+		detector     SpecDetector
+		spec         []byte
+		resourceType string
+		expectedType string
+		parseErr     bool
+	}{
+		"detected after the built in steps": {
+			priority:     0,
+			detector:     isSmithy,
+			spec:         []byte(smithyModel),
+			expectedType: smithyType,
+		},
+		"not detected": {
+			priority: 0,
+			detector: func([]byte) bool { return false },
+			spec:     []byte(smithyModel),
+			parseErr: true,
+		},
+		"nil detector is only used with the resource type": {
+			priority:     0,
+			spec:         []byte(smithyModel),
+			resourceType: smithyType,
+			expectedType: smithyType,
+		},
+		"built in step with the same priority is detected first": {
+			priority:     SpecPriorityYAMLAndJSON,
+			detector:     func([]byte) bool { return true },
+			spec:         []byte(`{"openapi": "3.0.1", "info": {"title": "petstore"}, "paths": {}}`),
+			expectedType: Oas3,
+		},
+		"higher priority is detected before the built in steps": {
+			priority:     SpecPriorityYAMLAndJSON + 1,
+			detector:     func([]byte) bool { return true },
+			spec:         []byte(`{"openapi": "3.0.1", "info": {"title": "petstore"}, "paths": {}}`),
+			expectedType: smithyType,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Nil(t, RegisterSpecProcessor(smithyType, tc.priority, tc.detector, newSmithyProcessor))
+			defer UnregisterSpecProcessor(smithyType)
+
+			specParser := NewSpecResourceParser(tc.spec, tc.resourceType)
+			err := specParser.Parse()
+			if tc.parseErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			processor := specParser.GetSpecProcessor()
+			assert.Equal(t, tc.expectedType, processor.GetResourceType())
+			if tc.expectedType != smithyType {
+				return
+			}
+			endpoints, err := processor.GetEndpoints()
+			assert.Nil(t, err)
+			assert.Equal(t, "weather.example.com", endpoints[0].Host)
+		})
+	}
+}
+
+func TestServiceBodyWithRegisteredSpecProcessor(t *testing.T) {
+	assert.Nil(t, RegisterSpecProcessor(smithyType, 0, isSmithy, newSmithyProcessor))
+	defer UnregisterSpecProcessor(smithyType)
+
+	sb, err := NewServiceBodyBuilder().
+		SetID("weather").
+		SetAPIName("weather").
+		SetVersion("1.0").
+		SetAPISpec([]byte(smithyModel)).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, smithyType, sb.ResourceType)
+	assert.Equal(t, "1.0", sb.GetSpecVersion())
+	assert.Len(t, sb.Endpoints, 1)
+}
@@ -0,0 +1,165 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/util"
+)
+
+// SpecValidationMode - how the report of the spec validation is handled when building the service body
+type SpecValidationMode string
+
+const (
+	// SpecValidationOff - the spec is not validated
+	SpecValidationOff SpecValidationMode = ""
+	// SpecValidationBlock - the service body is not built, or published, when the spec has errors
+	SpecValidationBlock SpecValidationMode = "block"
+	// SpecValidationWarn - the problems found in the spec are logged
+	SpecValidationWarn SpecValidationMode = "warn"
+	// SpecValidationAttach - the report is added to the revision x-agent-details
+	SpecValidationAttach SpecValidationMode = "attach"
+)
+
+// Severities of the spec validation issues
+const (
+	SpecIssueError   = "error"
+	SpecIssueWarning = "warning"
+)
+
+const specValidationDetailKey = "specValidation"
+
+// SpecValidationIssue - a problem found in the spec
+type SpecValidationIssue struct {
+	Severity string `json:"severity"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SpecValidationReport - the result of validating the structure of the spec
+type SpecValidationReport struct {
+	ResourceType string                `json:"resourceType"`
+	Valid        bool                  `json:"valid"`
+	Issues       []SpecValidationIssue `json:"issues"`
+}
+
+// SpecValidator - a spec processor that validates the structure of its spec
+type SpecValidator interface {
+	ValidateSpec() []SpecValidationIssue
+}
+
+func newSpecValidationReport(resourceType string, issues []SpecValidationIssue) *SpecValidationReport {
+	if issues == nil {
+		issues = []SpecValidationIssue{}
+	}
+	report := &SpecValidationReport{ResourceType: resourceType, Issues: issues}
+	report.Valid = !report.HasErrors()
+	return report
+}
+
+// HasErrors - returns true when any of the issues is an error
+func (r *SpecValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SpecIssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// summary - the error messages of the report on one line
+func (r *SpecValidationReport) summary() string {
+	messages := []string{}
+	for _, issue := range r.Issues {
+		if issue.Severity != SpecIssueError {
+			continue
+		}
+		if issue.Location != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", issue.Location, issue.Message))
+			continue
+		}
+		messages = append(messages, issue.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// toDetails - the report as the generic map saved in x-agent-details
+func (r *SpecValidationReport) toDetails() map[string]interface{} {
+	details := map[string]interface{}{}
+	data, _ := json.Marshal(r)
+	json.Unmarshal(data, &details)
+	return details
+}
+
+// specIssues - collects the issues found by a spec validator
+type specIssues []SpecValidationIssue
+
+func (i *specIssues) addError(location, format string, args ...interface{}) {
+	*i = append(*i, SpecValidationIssue{Severity: SpecIssueError, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (i *specIssues) addWarning(location, format string, args ...interface{}) {
+	*i = append(*i, SpecValidationIssue{Severity: SpecIssueWarning, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateLocalRefs - reports the local $ref values of the document that do not resolve to a value in the document
+func validateLocalRefs(doc interface{}, issues *specIssues) {
+	var walk func(node interface{}, location string)
+	walk = func(node interface{}, location string) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := n["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+				if _, found := resolveLocalRef(doc, ref); !found {
+					issues.addError(location, "reference %s could not be resolved", ref)
+				}
+			}
+			for _, key := range util.OrderedKeys(n) {
+				walk(n[key], location+"/"+escapeJSONPointer(key))
+			}
+		case []interface{}:
+			for i, v := range n {
+				walk(v, location+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(doc, "#")
+}
+
+// resolveLocalRef - returns the value the local json pointer reference points to
+func resolveLocalRef(doc interface{}, ref string) (interface{}, bool) {
+	pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, false
+	}
+	node := doc
+	if pointer == "" {
+		return node, true
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[token]
+			if !ok {
+				return nil, false
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
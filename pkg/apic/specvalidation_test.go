@@ -0,0 +1,257 @@
+package apic
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const invalidSwagger = `{
+	"swagger": "2.0",
+	"info": {"title": "pets"},
+	"host": "pets.example.com",
+	"securityDefinitions": {
+		"key": {"type": "apiKey", "in": "cookie"},
+		"oauth": {"type": "oauth2", "flow": "implicit"}
+	},
+	"security": [{"missing": []}],
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pets"}}}},
+			"post": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}
+		}
+	}
+}`
+
+const invalidOpenAPI = `{
+	"openapi": "3.0.1",
+	"info": {"title": "pets"},
+	"servers": [{"url": "https://pets.example.com"}],
+	"paths": {
+		"/pets": {
+			"get": {"security": [{"missing": []}], "responses": {"200": {"description": "ok"}}}
+		}
+	}
+}`
+
+const invalidAsyncAPI = `{
+	"asyncapi": "2.6.0",
+	"info": {"title": "events", "version": "1.0.0"},
+	"servers": {"prod": {"url": "broker.example.com", "security": [{"missing": []}]}},
+	"channels": {
+		"pets": {
+			"publish": {"operationId": "petEvent", "message": {"$ref": "#/components/messages/Pet"}},
+			"subscribe": {"operationId": "petEvent", "message": {"payload": {"type": "string"}}}
+		}
+	}
+}`
+
+const invalidAsyncAPIV3 = `{
+	"asyncapi": "3.0.0",
+	"info": {"title": "events", "version": "1.0.0"},
+	"servers": {"prod": {"protocol": "kafka"}},
+	"channels": {"pets": {"address": "pets"}},
+	"operations": {"onPet": {"action": "publish", "channel": {"$ref": "#/channels/pets"}}}
+}`
+
+const invalidWsdl = `<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/" xmlns:tns="http://example.com/pets" targetNamespace="http://example.com/pets">
+	<wsdl:message name="GetPetIn"/>
+	<wsdl:portType name="PetSoap">
+		<wsdl:operation name="GetPet">
+			<wsdl:input message="tns:GetPetIn"/>
+			<wsdl:output message="tns:GetPetOut"/>
+		</wsdl:operation>
+	</wsdl:portType>
+	<wsdl:binding name="PetSoap" type="tns:PetSoap">
+		<soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+		<wsdl:operation name="GetPet"/>
+		<wsdl:operation name="DeletePet"/>
+	</wsdl:binding>
+	<wsdl:service name="Pets">
+		<wsdl:port name="PetSoap" binding="tns:PetSoap">
+			<soap:address location="/pets"/>
+		</wsdl:port>
+	</wsdl:service>
+</wsdl:definitions>`
+
+func TestValidateSpec(t *testing.T) {
+	tests := map[string]struct {
+		inputFile    string
+		spec         string
+		resourceType string
+		expected     []SpecValidationIssue
+	}{
+		"valid swagger": {
+			inputFile: "./testdata/petstore-swagger2.json",
+		},
+		"valid openapi": {
+			inputFile: "./testdata/petstore-openapi3.json",
+		},
+		"valid asyncapi": {
+			inputFile: "./testdata/asyncapi-sample.yaml",
+		},
+		"valid asyncapi 3": {
+			inputFile: "./testdata/asyncapi-v3-sample.yaml",
+		},
+		"valid wsdl": {
+			inputFile: "./testdata/weather.xml",
+		},
+		"invalid swagger": {
+			spec:         invalidSwagger,
+			resourceType: Oas2,
+			expected: []SpecValidationIssue{
+				{Severity: SpecIssueError, Location: "#/info/version", Message: "value of version must be a non-empty string"},
+				{Severity: SpecIssueError, Location: "#/securityDefinitions/key", Message: "an apiKey security definition must have a name"},
+				{Severity: SpecIssueError, Location: "#/securityDefinitions/key", Message: `the location of an apiKey security definition must be query or header, found "cookie"`},
+				{Severity: SpecIssueError, Location: "#/securityDefinitions/oauth", Message: "the implicit flow requires an authorizationUrl"},
+				{Severity: SpecIssueError, Location: "#/security", Message: "security definition missing is not defined"},
+				{Severity: SpecIssueError, Location: "#/paths/~1pets/post/operationId", Message: "operation id listPets is also used by #/paths/~1pets/get"},
+				{Severity: SpecIssueWarning, Location: "#/securityDefinitions/key", Message: "security definition key is not used by any security requirement"},
+				{Severity: SpecIssueWarning, Location: "#/securityDefinitions/oauth", Message: "security definition oauth is not used by any security requirement"},
+				{Severity: SpecIssueError, Location: "#/paths/~1pets/get/responses/200/schema", Message: "reference #/definitions/Pets could not be resolved"},
+			},
+		},
+		"invalid openapi": {
+			spec:         invalidOpenAPI,
+			resourceType: Oas3,
+			expected: []SpecValidationIssue{
+				{Severity: SpecIssueError, Message: "invalid info: value of version must be a non-empty string"},
+				{Severity: SpecIssueError, Location: "#/paths/~1pets/get/security", Message: "security scheme missing is not defined"},
+			},
+		},
+		"invalid asyncapi": {
+			spec:         invalidAsyncAPI,
+			resourceType: AsyncAPI,
+			expected: []SpecValidationIssue{
+				{Severity: SpecIssueError, Location: "#/servers/prod/protocol", Message: "the server protocol must be provided"},
+				{Severity: SpecIssueError, Location: "#/servers/prod/security", Message: "security scheme missing is not defined"},
+				{Severity: SpecIssueError, Location: "#/channels/pets/subscribe/operationId", Message: "operation id petEvent is also used by #/channels/pets/publish"},
+				{Severity: SpecIssueError, Location: "#/channels/pets/publish/message", Message: "reference #/components/messages/Pet could not be resolved"},
+			},
+		},
+		"invalid asyncapi 3": {
+			spec:         invalidAsyncAPIV3,
+			resourceType: AsyncAPI,
+			expected: []SpecValidationIssue{
+				{Severity: SpecIssueError, Location: "#/servers/prod/host", Message: "the server host must be provided"},
+				{Severity: SpecIssueError, Location: "#/operations/onPet/action", Message: `the operation action must be send or receive, found "publish"`},
+			},
+		},
+		"invalid wsdl": {
+			spec:         invalidWsdl,
+			resourceType: Wsdl,
+			expected: []SpecValidationIssue{
+				{Severity: SpecIssueError, Location: "service[Pets]/port[PetSoap]", Message: "the port address location /pets is not a valid url"},
+				{Severity: SpecIssueError, Location: "portType[PetSoap]/operation[GetPet]", Message: "message tns:GetPetOut is not defined"},
+				{Severity: SpecIssueError, Location: "binding[PetSoap]/operation[DeletePet]", Message: "operation DeletePet is not defined in port type PetSoap"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := []byte(tc.spec)
+			if tc.inputFile != "" {
+				var err error
+				spec, err = os.ReadFile(tc.inputFile)
+				assert.Nil(t, err)
+			}
+			specParser := NewSpecResourceParser(spec, tc.resourceType)
+			assert.Nil(t, specParser.Parse())
+
+			validator, ok := specParser.GetSpecProcessor().(SpecValidator)
+			assert.True(t, ok)
+			issues := validator.ValidateSpec()
+			if tc.expected == nil {
+				assert.Empty(t, issues)
+				return
+			}
+			assert.Equal(t, tc.expected, issues)
+		})
+	}
+}
+
+func TestServiceBodySpecValidationModes(t *testing.T) {
+	tests := map[string]struct {
+		mode        SpecValidationMode
+		spec        string
+		buildErr    bool
+		noReport    bool
+		attached    bool
+		reportValid bool
+	}{
+		"validation off": {
+			mode:     SpecValidationOff,
+			spec:     invalidSwagger,
+			noReport: true,
+		},
+		"warn does not block": {
+			mode: SpecValidationWarn,
+			spec: invalidSwagger,
+		},
+		"block on errors": {
+			mode:     SpecValidationBlock,
+			spec:     invalidSwagger,
+			buildErr: true,
+		},
+		"block with a valid spec": {
+			mode:        SpecValidationBlock,
+			spec:        `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0.0"}, "host": "pets.example.com", "paths": {}}`,
+			reportValid: true,
+		},
+		"warnings only": {
+			mode:        SpecValidationBlock,
+			spec:        `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0.0"}, "host": "pets.example.com", "securityDefinitions": {"basic": {"type": "basic"}}, "paths": {}}`,
+			reportValid: true,
+		},
+		"attach the report": {
+			mode:     SpecValidationAttach,
+			spec:     invalidSwagger,
+			attached: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			agentDetails := map[string]interface{}{"team": "pets"}
+			sb, err := NewServiceBodyBuilder().
+				SetAPIName("pets").
+				SetRevisionAgentDetails(agentDetails).
+				SetResourceType(Oas2).
+				SetAPISpec([]byte(tc.spec)).
+				SetSpecValidationMode(tc.mode).
+				Build()
+			if tc.buildErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), "#/info/version: value of version must be a non-empty string")
+				assert.False(t, sb.GetSpecValidationReport().Valid)
+
+				// a service body that failed validation is not published
+				client, _ := GetTestServiceClient()
+				_, err = client.PublishService(&sb)
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+
+			report := sb.GetSpecValidationReport()
+			if tc.noReport {
+				assert.Nil(t, report)
+				return
+			}
+			assert.Equal(t, Oas2, report.ResourceType)
+			assert.Equal(t, tc.reportValid, report.Valid)
+
+			details, found := sb.RevisionAgentDetails[specValidationDetailKey].(map[string]interface{})
+			assert.Equal(t, tc.attached, found)
+			if tc.attached {
+				assert.Equal(t, "pets", sb.RevisionAgentDetails["team"])
+				assert.NotContains(t, agentDetails, specValidationDetailKey)
+				assert.Equal(t, false, details["valid"])
+				assert.Len(t, details["issues"], len(report.Issues))
+			}
+		})
+	}
+}
@@ -1,14 +1,38 @@
 package apic
 
 import (
-	"net"
+	"fmt"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/Axway/agent-sdk/pkg/util/log"
 	"github.com/Axway/agent-sdk/pkg/util/wsdl"
 )
 
+const wsdlServicesDetailKey = "wsdlServices"
+
+var (
+	wsdlDefaultPorts       = map[string]int{"http": 80, "https": 443}
+	wsdlNamespaceVersionRe = regexp.MustCompile(`^v?\d+(?:\.\d+)*$`)
+)
+
+// WsdlService - a service of the WSDL and its ports
+type WsdlService struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Ports       []WsdlPort `json:"ports"`
+}
+
+// WsdlPort - a port, or WSDL 2.0 endpoint, of a WSDL service
+type WsdlPort struct {
+	Name        string `json:"name"`
+	Binding     string `json:"binding"`
+	Address     string `json:"address"`
+	SOAPVersion string `json:"soapVersion,omitempty"`
+}
+
 type wsdlProcessor struct {
 	wsdlDef *wsdl.Definitions
 	spec    []byte
@@ -22,54 +46,89 @@ func (p *wsdlProcessor) GetResourceType() string {
 	return Wsdl
 }
 
-// GetVersion -
+// GetVersion - the last segment of the target namespace when it is a version, i.e. 2.1 for http://example.com/pets/2.1
 func (p *wsdlProcessor) GetVersion() string {
+	namespace := strings.TrimRight(p.wsdlDef.TargetNamespace, "/")
+	segment := namespace[strings.LastIndexAny(namespace, "/:")+1:]
+	if wsdlNamespaceVersionRe.MatchString(segment) {
+		return segment
+	}
 	return ""
 }
 
-// GetDescription -
+// GetDescription - the documentation of the WSDL, falling back to the documentation of the first service
 func (p *wsdlProcessor) GetDescription() string {
+	if doc := strings.TrimSpace(p.wsdlDef.Doc); doc != "" {
+		return doc
+	}
+	for _, s := range p.wsdlDef.Services {
+		if doc := strings.TrimSpace(s.Doc); doc != "" {
+			return doc
+		}
+	}
 	return ""
 }
 
-// GetEndpoints -
+// GetServices - the services of the WSDL, with the SOAP version of the binding of each port
+func (p *wsdlProcessor) GetServices() []WsdlService {
+	services := []WsdlService{}
+	for _, s := range p.wsdlDef.Services {
+		service := WsdlService{Name: s.Name, Description: strings.TrimSpace(s.Doc), Ports: []WsdlPort{}}
+		for _, port := range s.Ports {
+			wsdlPort := WsdlPort{Name: port.Name, Binding: port.Binding, Address: port.Address.Location}
+			if binding := p.wsdlDef.GetBinding(port.Binding); binding != nil {
+				wsdlPort.SOAPVersion = binding.SOAPVersion
+			}
+			service.Ports = append(service.Ports, wsdlPort)
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+// GetEndpoints - an endpoint for each distinct port address of all services
 func (p *wsdlProcessor) GetEndpoints() ([]EndpointDefinition, error) {
 	endPoints := []EndpointDefinition{}
-	ports := p.wsdlDef.Service.Ports
-	for _, val := range ports {
-		loc := val.Address.Location
-		fixed, err := url.Parse(loc)
-		if err != nil {
-			log.Errorf("Error parsing service location in WSDL to get endpoints: %v", err.Error())
-			return nil, err
-		}
-		protocol := fixed.Scheme
-		host := fixed.Hostname()
-		portStr := fixed.Port()
-		if portStr == "" {
-			p, err := net.LookupPort("tcp", protocol)
+	for _, service := range p.GetServices() {
+		for _, port := range service.Ports {
+			fixed, err := url.Parse(port.Address)
+			if err != nil {
+				log.Errorf("Error parsing service location in WSDL to get endpoints: %v", err.Error())
+				return nil, err
+			}
+			protocol := fixed.Scheme
+			portNumber, err := p.getPort(fixed)
 			if err != nil {
 				log.Errorf("Error finding port for endpoint: %v", err.Error())
 				return nil, err
 			}
-			portStr = strconv.Itoa(p)
-		}
-		port, _ := strconv.Atoi(portStr)
 
-		endPoint := EndpointDefinition{
-			Host:     host,
-			Port:     int32(port),
-			Protocol: protocol,
-			BasePath: fixed.Path,
-		}
-		if !p.contains(endPoints, endPoint) {
-			endPoints = append(endPoints, endPoint)
+			endPoint := EndpointDefinition{
+				Host:     fixed.Hostname(),
+				Port:     int32(portNumber),
+				Protocol: protocol,
+				BasePath: fixed.Path,
+			}
+			if !p.contains(endPoints, endPoint) {
+				endPoints = append(endPoints, endPoint)
+			}
 		}
 	}
 
 	return endPoints, nil
 }
 
+// getPort - the port of the address, or the default port of its scheme
+func (p *wsdlProcessor) getPort(address *url.URL) (int, error) {
+	if portStr := address.Port(); portStr != "" {
+		return strconv.Atoi(portStr)
+	}
+	if port, found := wsdlDefaultPorts[address.Scheme]; found {
+		return port, nil
+	}
+	return 0, fmt.Errorf("no default port for the %s scheme", address.Scheme)
+}
+
 func (p *wsdlProcessor) contains(endpts []EndpointDefinition, endpt EndpointDefinition) bool {
 	for _, pt := range endpts {
 		if pt.Host == endpt.Host && pt.Port == endpt.Port &&
@@ -80,7 +139,78 @@ func (p *wsdlProcessor) contains(endpts []EndpointDefinition, endpt EndpointDefi
 	return false
 }
 
+// getRevisionDetails - the services and ports to add to the revision x-agent-details
+func (p *wsdlProcessor) getRevisionDetails() map[string]interface{} {
+	services := []interface{}{}
+	for _, s := range p.GetServices() {
+		services = append(services, s)
+	}
+	if len(services) == 0 {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{wsdlServicesDetailKey: services}
+}
+
 // GetSpecBytes -
 func (p *wsdlProcessor) GetSpecBytes() []byte {
 	return p.spec
 }
+
+// ValidateSpec - validates the service ports, and that the bindings, port types and messages of the wsdl refer to each other
+func (p *wsdlProcessor) ValidateSpec() []SpecValidationIssue {
+	issues := specIssues{}
+	def := p.wsdlDef
+	if len(def.Services) == 0 {
+		issues.addError("service", "the wsdl must define at least one service")
+	}
+	for _, service := range def.Services {
+		serviceLocation := "service[" + service.Name + "]"
+		if len(service.Ports) == 0 {
+			issues.addError(serviceLocation, "the service must define at least one port")
+		}
+		for _, port := range service.Ports {
+			location := serviceLocation + "/port[" + port.Name + "]"
+			if port.Address.Location == "" {
+				issues.addError(location, "the port must have an address location")
+			} else if u, err := url.Parse(port.Address.Location); err != nil || u.Host == "" {
+				issues.addError(location, "the port address location %s is not a valid url", port.Address.Location)
+			}
+			if def.GetBinding(port.Binding) == nil {
+				issues.addError(location, "binding %s is not defined", port.Binding)
+			}
+		}
+	}
+
+	// WSDL 2.0 operations refer to schema elements rather than messages
+	if def.Version != wsdl.WSDL20 {
+		for _, pt := range def.PortType {
+			for _, op := range pt.Operations {
+				location := "portType[" + pt.Name + "]/operation[" + op.Name + "]"
+				for _, io := range []*wsdl.IO{op.Input, op.Output} {
+					if io != nil && def.GetMessage(io.Message) == nil {
+						issues.addError(location, "message %s is not defined", io.Message)
+					}
+				}
+			}
+		}
+	}
+
+	for _, binding := range def.Bindings {
+		bindingLocation := "binding[" + binding.Name + "]"
+		portType := def.GetPortType(binding.Type)
+		if portType == nil {
+			issues.addError(bindingLocation, "port type %s is not defined", binding.Type)
+			continue
+		}
+		operations := map[string]bool{}
+		for _, op := range portType.Operations {
+			operations[op.Name] = true
+		}
+		for _, op := range binding.Operations {
+			if !operations[op.Name] {
+				issues.addError(bindingLocation+"/operation["+op.Name+"]", "operation %s is not defined in port type %s", op.Name, portType.Name)
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,265 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+	"github.com/Axway/agent-sdk/pkg/config"
+)
+
+const (
+	defaultMergeRetries = 3
+	defaultFieldManager = "agent"
+)
+
+// MergeOption - an option for merging the keys of a sub resource
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	manager string
+	retries int
+}
+
+// WithFieldManager - the name the keys merged are owned by, the agent type name by default
+func WithFieldManager(manager string) MergeOption {
+	return func(o *mergeOptions) {
+		o.manager = manager
+	}
+}
+
+// WithMergeRetries - the number of times the merge is retried when the resource was changed while merging
+func WithMergeRetries(retries int) MergeOption {
+	return func(o *mergeOptions) {
+		o.retries = retries
+	}
+}
+
+func newMergeOptions(opts []MergeOption) *mergeOptions {
+	o := &mergeOptions{manager: config.AgentTypeName, retries: defaultMergeRetries}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.manager == "" {
+		o.manager = defaultFieldManager
+	}
+	return o
+}
+
+// WithMergeAgentDetails - merges the x-agent-details of the resource with MergeSubResource, instead of replacing them,
+// so the keys set by others are kept
+func WithMergeAgentDetails(opts ...MergeOption) UpdateOption {
+	return func(o *updateOptions) {
+		o.mergeAgentDetails = newMergeOptions(opts)
+	}
+}
+
+// keyOwnership - the top level keys of each sub resource set by each field manager, kept in the managedKeys of the
+// x-agent-details of the resource
+type keyOwnership map[string]map[string][]string
+
+func getKeyOwnership(agentDetails map[string]interface{}) keyOwnership {
+	ownership := keyOwnership{}
+	if managedKeys, ok := agentDetails[defs.AttrManagedKeys]; ok {
+		if data, err := json.Marshal(managedKeys); err == nil {
+			json.Unmarshal(data, &ownership)
+		}
+	}
+	if ownership == nil {
+		return keyOwnership{}
+	}
+	return ownership
+}
+
+// MergeSubResource - merges the values into the sub resource of the resource on Amplify Central, with JSON merge patch
+// semantics: objects are merged, a nil value removes the key and other values replace the existing value. The keys set
+// by the field manager in a previous merge that are not in values are removed, the keys set by others are kept. The
+// sub resource is updated with If-Match, and the merge is retried with the latest resource on a conflict.
+func (c *ServiceClient) MergeSubResource(ri apiv1.Interface, subResourceName string, values map[string]interface{}, opts ...MergeOption) (*apiv1.ResourceInstance, error) {
+	inst, err := ri.AsInstance()
+	if err != nil {
+		return nil, err
+	}
+	if inst.GetSelfLink() == "" {
+		return nil, fmt.Errorf("could not merge the sub resource %s, could not get self link", subResourceName)
+	}
+	return c.mergeSubResource(inst.GetSelfLink(), nil, subResourceName, values, newMergeOptions(opts))
+}
+
+// mergeSubResource - merges the values into the sub resource of the current resource, read from the self link when
+// current is nil or the merge is retried
+func (c *ServiceClient) mergeSubResource(selfLink string, current *apiv1.ResourceInstance, subResourceName string, values map[string]interface{}, o *mergeOptions) (*apiv1.ResourceInstance, error) {
+	logger := c.logger.WithField("selfLink", selfLink).WithField("subResourceName", subResourceName)
+	for attempt := 0; ; attempt++ {
+		if current == nil {
+			var err error
+			if current, err = c.GetResource(selfLink); err != nil {
+				return nil, err
+			}
+		}
+
+		subResources, changed := mergedSubResources(current, subResourceName, o.manager, values)
+		if !changed {
+			logger.Trace("no changes to the keys of the sub resource")
+			return current, nil
+		}
+
+		updated, err := c.putSubResources(current, subResources)
+		if err == nil || !IsConflictError(err) || attempt >= o.retries {
+			return updated, err
+		}
+		logger.WithField("attempt", attempt+1).Debug("the resource was changed while merging, retrying the merge")
+		current = nil
+	}
+}
+
+// putSubResources - updates the sub resources of the resource, each with If-Match on the version returned by the previous
+func (c *ServiceClient) putSubResources(ri *apiv1.ResourceInstance, subResources map[string]interface{}) (*apiv1.ResourceInstance, error) {
+	names := make([]string, 0, len(subResources))
+	for name := range subResources {
+		names = append(names, name)
+	}
+	// x-agent-details, recording the keys owned, is updated last
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == defs.XAgentDetails) != (names[j] == defs.XAgentDetails) {
+			return names[j] == defs.XAgentDetails
+		}
+		return names[i] < names[j]
+	})
+
+	updated := ri
+	for _, name := range names {
+		bts, err := json.Marshal(map[string]interface{}{name: subResources[name]})
+		if err != nil {
+			return nil, err
+		}
+		url := c.createAPIServerURL(fmt.Sprintf("%s/%s", ri.GetSelfLink(), name))
+		resourceVersion := updated.Metadata.ResourceVersion
+		bts, err = c.ExecuteAPIWithHeader(coreapi.PUT, url, nil, bts, ifMatchHeaders(resourceVersion))
+		if resourceVersion != "" && isConflict(err) {
+			return nil, &ConflictError{Kind: ri.Kind, Name: ri.Name, ResourceVersion: resourceVersion}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		updated = &apiv1.ResourceInstance{}
+		if err := json.Unmarshal(bts, updated); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// mergedSubResources - the sub resource and the x-agent-details of the resource after merging the values of the
+// manager, and whether they changed
+func mergedSubResources(ri *apiv1.ResourceInstance, subResourceName, manager string, values map[string]interface{}) (map[string]interface{}, bool) {
+	existingDetails, ok := toJSONValue(ri.SubResources[defs.XAgentDetails]).(map[string]interface{})
+	if !ok {
+		existingDetails = map[string]interface{}{}
+	}
+	existing, ok := toJSONValue(ri.SubResources[subResourceName]).(map[string]interface{})
+	if !ok {
+		existing = map[string]interface{}{}
+	}
+	ownership := getKeyOwnership(existingDetails)
+
+	owned := []string{}
+	if managers, ok := ownership[subResourceName]; ok {
+		owned = managers[manager]
+	}
+	patch, _ := toJSONValue(values).(map[string]interface{})
+	if subResourceName == defs.XAgentDetails {
+		// the keys owned are not merged as a value
+		delete(patch, defs.AttrManagedKeys)
+		owned = withoutKey(owned, defs.AttrManagedKeys)
+	}
+	merged := mergeOwnedKeys(existing, patch, owned)
+
+	keys := []string{}
+	for key, value := range patch {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if ownership[subResourceName] == nil {
+		ownership[subResourceName] = map[string][]string{}
+	}
+	if len(keys) > 0 {
+		ownership[subResourceName][manager] = keys
+	} else {
+		delete(ownership[subResourceName], manager)
+	}
+	if len(ownership[subResourceName]) == 0 {
+		delete(ownership, subResourceName)
+	}
+
+	agentDetails := copyMap(existingDetails)
+	if subResourceName == defs.XAgentDetails {
+		agentDetails = merged
+	}
+	agentDetails[defs.AttrManagedKeys] = toJSONValue(ownership)
+	if len(ownership) == 0 {
+		delete(agentDetails, defs.AttrManagedKeys)
+	}
+
+	subResources := map[string]interface{}{defs.XAgentDetails: agentDetails}
+	changed := !reflect.DeepEqual(existingDetails, toJSONValue(agentDetails))
+	if subResourceName != defs.XAgentDetails {
+		subResources[subResourceName] = merged
+		changed = changed || !reflect.DeepEqual(existing, toJSONValue(merged))
+	}
+	return subResources, changed
+}
+
+// mergeOwnedKeys - the existing object with the keys owned that are not in the patch removed and the patch merged in
+func mergeOwnedKeys(existing, patch map[string]interface{}, owned []string) map[string]interface{} {
+	merged := copyMap(existing)
+	for _, key := range owned {
+		if _, found := patch[key]; !found {
+			delete(merged, key)
+		}
+	}
+	return mergePatch(merged, patch)
+}
+
+// mergePatch - the target with the patch applied as a JSON merge patch, RFC 7386
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	merged := copyMap(target)
+	for key, value := range patch {
+		patchObject, isObject := value.(map[string]interface{})
+		switch {
+		case value == nil:
+			delete(merged, key)
+		case isObject:
+			targetObject, _ := merged[key].(map[string]interface{})
+			merged[key] = mergePatch(targetObject, patchObject)
+		default:
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+	return c
+}
+
+func withoutKey(keys []string, key string) []string {
+	without := []string{}
+	for _, k := range keys {
+		if k != key {
+			without = append(without, k)
+		}
+	}
+	return without
+}
@@ -0,0 +1,274 @@
+package apic
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
+	defs "github.com/Axway/agent-sdk/pkg/apic/definitions"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		target   map[string]interface{}
+		patch    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"adds and replaces values": {
+			target:   map[string]interface{}{"a": "b", "c": "d"},
+			patch:    map[string]interface{}{"a": "z", "e": "f"},
+			expected: map[string]interface{}{"a": "z", "c": "d", "e": "f"},
+		},
+		"removes null values": {
+			target:   map[string]interface{}{"a": "b", "c": "d"},
+			patch:    map[string]interface{}{"a": nil},
+			expected: map[string]interface{}{"c": "d"},
+		},
+		"merges objects": {
+			target:   map[string]interface{}{"a": map[string]interface{}{"b": "c", "d": "e"}},
+			patch:    map[string]interface{}{"a": map[string]interface{}{"b": nil, "f": "g"}},
+			expected: map[string]interface{}{"a": map[string]interface{}{"d": "e", "f": "g"}},
+		},
+		"replaces values that are not objects": {
+			target:   map[string]interface{}{"a": []interface{}{"b"}},
+			patch:    map[string]interface{}{"a": map[string]interface{}{"c": "d"}},
+			expected: map[string]interface{}{"a": map[string]interface{}{"c": "d"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, mergePatch(tc.target, tc.patch))
+		})
+	}
+}
+
+func newMergeService(t *testing.T, resourceVersion string, subResources map[string]interface{}) *apiv1.ResourceInstance {
+	ri := newVersionedService(t, "Petstore", resourceVersion)
+	ri.SubResources = subResources
+	return ri
+}
+
+func TestMergedSubResources(t *testing.T) {
+	tests := map[string]struct {
+		subResources    map[string]interface{}
+		subResourceName string
+		values          map[string]interface{}
+		expected        map[string]interface{}
+		changed         bool
+	}{
+		"keeps the keys of others": {
+			subResources: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{"team": "payments"},
+			},
+			subResourceName: defs.XAgentDetails,
+			values:          map[string]interface{}{"externalAPIID": "123"},
+			expected: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team":          "payments",
+					"externalAPIID": "123",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{"agent": []interface{}{"externalAPIID"}},
+					},
+				},
+			},
+			changed: true,
+		},
+		"removes the keys owned no longer set": {
+			subResources: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team":          "payments",
+					"externalAPIID": "123",
+					"stage":         "prod",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{"agent": []interface{}{"externalAPIID", "stage"}},
+					},
+				},
+			},
+			subResourceName: defs.XAgentDetails,
+			values:          map[string]interface{}{"externalAPIID": "456"},
+			expected: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team":          "payments",
+					"externalAPIID": "456",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{"agent": []interface{}{"externalAPIID"}},
+					},
+				},
+			},
+			changed: true,
+		},
+		"keeps the ownership of other managers": {
+			subResources: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team": "payments",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{"catalog-sync": []interface{}{"team"}},
+					},
+				},
+			},
+			subResourceName: defs.XAgentDetails,
+			values:          map[string]interface{}{"team": "orders"},
+			expected: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team": "orders",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{
+							"agent":        []interface{}{"team"},
+							"catalog-sync": []interface{}{"team"},
+						},
+					},
+				},
+			},
+			changed: true,
+		},
+		"merges other sub resources": {
+			subResources: map[string]interface{}{
+				"source": map[string]interface{}{"type": "external", "owner": "team"},
+			},
+			subResourceName: "source",
+			values:          map[string]interface{}{"type": "discovered", "owner": nil},
+			expected: map[string]interface{}{
+				"source": map[string]interface{}{"type": "discovered"},
+				defs.XAgentDetails: map[string]interface{}{
+					defs.AttrManagedKeys: map[string]interface{}{
+						"source": map[string]interface{}{"agent": []interface{}{"type"}},
+					},
+				},
+			},
+			changed: true,
+		},
+		"no changes to the keys owned": {
+			subResources: map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{
+					"team":          "payments",
+					"externalAPIID": "123",
+					defs.AttrManagedKeys: map[string]interface{}{
+						defs.XAgentDetails: map[string]interface{}{"agent": []interface{}{"externalAPIID"}},
+					},
+				},
+			},
+			subResourceName: defs.XAgentDetails,
+			values:          map[string]interface{}{"externalAPIID": "123"},
+			changed:         false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ri := newMergeService(t, "1", tc.subResources)
+			subResources, changed := mergedSubResources(ri, tc.subResourceName, "agent", tc.values)
+			assert.Equal(t, tc.changed, changed)
+			if tc.changed {
+				assert.Equal(t, tc.expected, toJSONValue(subResources))
+			}
+		})
+	}
+}
+
+func TestMergeSubResource(t *testing.T) {
+	existing := map[string]interface{}{
+		defs.XAgentDetails: map[string]interface{}{"team": "payments"},
+	}
+	conflict := api.MockResponse{RespCode: http.StatusPreconditionFailed, RespData: "{}"}
+
+	t.Run("merges the latest resource again after a conflict", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{
+			resourceResponse(t, newMergeService(t, "1", existing)),
+			conflict,
+			resourceResponse(t, newMergeService(t, "2", map[string]interface{}{
+				defs.XAgentDetails: map[string]interface{}{"team": "orders"},
+			})),
+			resourceResponse(t, newMergeService(t, "3", nil)),
+		})
+
+		ri, err := client.MergeSubResource(newMergeService(t, "", nil), defs.XAgentDetails, map[string]interface{}{"externalAPIID": "123"})
+		assert.Nil(t, err)
+		assert.Equal(t, "3", ri.Metadata.ResourceVersion)
+		assert.Len(t, httpClient.Requests, 4)
+		put := httpClient.Requests[3]
+		assert.Equal(t, http.MethodPut, put.Method)
+		assert.Contains(t, put.URL, "/x-agent-details")
+		assert.Equal(t, `"2"`, put.Headers[HdrIfMatch])
+
+		sent := map[string]map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(put.Body, &sent))
+		assert.Equal(t, "orders", sent[defs.XAgentDetails]["team"])
+		assert.Equal(t, "123", sent[defs.XAgentDetails]["externalAPIID"])
+		assert.NotNil(t, sent[defs.XAgentDetails][defs.AttrManagedKeys])
+	})
+
+	t.Run("returns the conflict when the retries are exhausted", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{
+			resourceResponse(t, newMergeService(t, "1", existing)),
+			conflict,
+		})
+
+		_, err := client.MergeSubResource(newMergeService(t, "", nil), defs.XAgentDetails, map[string]interface{}{"externalAPIID": "123"}, WithMergeRetries(0))
+		assert.True(t, IsConflictError(err))
+		assert.Len(t, httpClient.Requests, 2)
+	})
+
+	t.Run("updates the sub resource before the x-agent-details", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{
+			resourceResponse(t, newMergeService(t, "1", existing)),
+			resourceResponse(t, newMergeService(t, "2", nil)),
+			resourceResponse(t, newMergeService(t, "3", nil)),
+		})
+
+		_, err := client.MergeSubResource(newMergeService(t, "", nil), "source", map[string]interface{}{"type": "discovered"}, WithFieldManager("discovery"))
+		assert.Nil(t, err)
+		assert.Len(t, httpClient.Requests, 3)
+		assert.Contains(t, httpClient.Requests[1].URL, "/source")
+		assert.Equal(t, `"1"`, httpClient.Requests[1].Headers[HdrIfMatch])
+		assert.Contains(t, httpClient.Requests[2].URL, "/x-agent-details")
+		assert.Equal(t, `"2"`, httpClient.Requests[2].Headers[HdrIfMatch])
+		assert.Contains(t, string(httpClient.Requests[2].Body), `"discovery":["type"]`)
+	})
+
+	t.Run("does not update when nothing changed", func(t *testing.T) {
+		client, httpClient := GetTestServiceClient()
+		httpClient.SetResponses([]api.MockResponse{resourceResponse(t, newMergeService(t, "1", existing))})
+
+		_, err := client.MergeSubResource(newMergeService(t, "", nil), defs.XAgentDetails, map[string]interface{}{})
+		assert.Nil(t, err)
+		assert.Len(t, httpClient.Requests, 1)
+	})
+}
+
+func TestCreateOrUpdateResourceWithMergeAgentDetails(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	subResources := map[string]interface{}{
+		defs.XAgentDetails: map[string]interface{}{"team": "payments"},
+	}
+	existing := newMergeService(t, "1", subResources)
+	httpClient.SetResponses([]api.MockResponse{
+		resourceResponse(t, newMergeService(t, "2", nil)),
+		resourceResponse(t, newMergeService(t, "2", subResources)),
+		resourceResponse(t, newMergeService(t, "3", nil)),
+	})
+
+	svc := management.NewAPIService("petstore", "v7envandcat")
+	svc.Title = "Petstore"
+	svc.SetSubResource(defs.XAgentDetails, map[string]interface{}{"externalAPIID": "123"})
+	_, err := client.CreateOrUpdateResource(svc, WithExistingResourceInstance(existing), WithMergeAgentDetails())
+	assert.Nil(t, err)
+	assert.Len(t, httpClient.Requests, 3)
+	assert.Equal(t, http.MethodGet, httpClient.Requests[1].Method)
+	put := httpClient.Requests[2]
+	assert.Equal(t, http.MethodPut, put.Method)
+	assert.Contains(t, put.URL, "/x-agent-details")
+	assert.Equal(t, `"2"`, put.Headers[HdrIfMatch])
+
+	sent := map[string]map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(put.Body, &sent))
+	assert.Equal(t, "payments", sent[defs.XAgentDetails]["team"])
+	assert.Equal(t, "123", sent[defs.XAgentDetails]["externalAPIID"])
+	assert.Contains(t, string(put.Body), `"agent":["externalAPIID","specHash"]`)
+}
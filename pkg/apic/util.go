@@ -2,6 +2,8 @@ package apic
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 
 	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
@@ -118,3 +120,40 @@ func addCustomFieldsToNewRequestSchema(clientLogger log.FieldLogger, data, exist
 
 	return data
 }
+
+// endpointURL - the url of an endpoint of a specification and the details added to its endpoint
+type endpointURL struct {
+	url     string
+	details map[string]interface{}
+}
+
+// endpointsFromURLs - an endpoint for each of the urls, the urls without a scheme use the default protocol and those
+// without a port the default port, or the port of the protocol when 0. The urls of the same endpoint are listed once.
+func endpointsFromURLs(urls []endpointURL, defaultProtocol string, defaultPort int) ([]EndpointDefinition, error) {
+	endpoints := []EndpointDefinition{}
+	seen := map[string]bool{}
+	for _, u := range urls {
+		rawURL := u.url
+		if !strings.Contains(rawURL, "://") {
+			rawURL = defaultProtocol + "://" + rawURL
+		}
+		parsedURL, err := url.Parse(rawURL)
+		if err != nil || parsedURL.Hostname() == "" {
+			return nil, fmt.Errorf("could not parse the endpoint url: %s", u.url)
+		}
+		port := defaultPort
+		if parsedURL.Port() != "" {
+			port, _ = strconv.Atoi(parsedURL.Port())
+		}
+
+		endpoint := createEndpointDefinition(parsedURL.Scheme, parsedURL.Hostname(), port, parsedURL.Path)
+		key := fmt.Sprintf("%s://%s:%d%s", endpoint.Protocol, endpoint.Host, endpoint.Port, endpoint.BasePath)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		endpoint.Details = u.details
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
@@ -75,3 +75,28 @@ func TestGetSubscriptionNameFromAccessReq(t *testing.T) {
 	subscriptionName = GetSubscriptionNameFromAccessRequest(ar)
 	assert.Equal(t, "", subscriptionName)
 }
+
+func TestEndpointsFromURLs(t *testing.T) {
+	details := map[string]interface{}{"transport": "GRPC"}
+	endpoints, err := endpointsFromURLs([]endpointURL{
+		{url: "https://api.example.com/v1"},
+		{url: "http://api.example.com:8080"},
+		{url: "grpc.example.com:50051", details: details},
+		// the same endpoint as the first url
+		{url: "api.example.com:443/v1"},
+	}, "https", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{
+		{Host: "api.example.com", Port: 443, Protocol: "https", BasePath: "/v1"},
+		{Host: "api.example.com", Port: 8080, Protocol: "http", BasePath: "/"},
+		{Host: "grpc.example.com", Port: 50051, Protocol: "https", BasePath: "/", Details: details},
+	}, endpoints)
+
+	// the default port is used when the url has none
+	endpoints, err = endpointsFromURLs([]endpointURL{{url: "pubsub.googleapis.com"}}, "grpc", 443)
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{{Host: "pubsub.googleapis.com", Port: 443, Protocol: "grpc", BasePath: "/"}}, endpoints)
+
+	_, err = endpointsFromURLs([]endpointURL{{url: "https://"}}, "https", 0)
+	assert.NotNil(t, err)
+}
@@ -17,9 +17,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Axway/agent-sdk/pkg/apic"
 	"github.com/Axway/agent-sdk/pkg/apic/definitions"
 	"github.com/Axway/agent-sdk/pkg/cmd/properties"
 	"github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/traceability/redaction"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -1227,3 +1229,47 @@ func TestNewCmd(t *testing.T) {
 
 	assert.Contains(t, "Error central.organizationID not set in config", errBuf.String())
 }
+
+func TestGenImportCmd(t *testing.T) {
+	rootCmd := NewRootCmd("Test", "TestRootCmd", nil, nil, corecfg.DiscoveryAgent)
+	rootCmd.AddCommand(GenImportCmd(rootCmd))
+
+	importCmd, _, err := rootCmd.RootCmd().Find([]string{"import"})
+	assert.Nil(t, err)
+	assert.Equal(t, "import", importCmd.Name())
+	assert.NotNil(t, importCmd.Flags().Lookup(pruneFlag))
+	assert.NotNil(t, importCmd.Args(importCmd, []string{}))
+
+	out := new(bytes.Buffer)
+	printChanges(out, []apic.PlanChange{
+		{Action: apic.PlanCreate, Kind: "APIService", Name: "petstore", Scope: "env"},
+		{Action: apic.PlanUpdate, Kind: "APIServiceInstance", Name: "petstore-prod", Scope: "env", Diff: []string{`title: "" -> "Petstore"`}},
+	})
+	assert.Equal(t, "create    APIService env/petstore\n"+
+		"update    APIServiceInstance env/petstore-prod\n"+
+		"    title: \"\" -> \"Petstore\"\n", out.String())
+}
+
+func TestGenRedactionCmd(t *testing.T) {
+	rootCmd := NewRootCmd("redaction_agent", "TestRootCmd", nil, nil, corecfg.TraceabilityAgent)
+	rootCmd.AddCommand(GenRedactionCmd(rootCmd))
+
+	redactionCmd, _, err := rootCmd.RootCmd().Find([]string{"redaction"})
+	assert.Nil(t, err)
+	assert.Equal(t, "redaction", redactionCmd.Name())
+	assert.NotNil(t, redactionCmd.Args(redactionCmd, []string{}))
+
+	out := new(bytes.Buffer)
+	rootCmd.RootCmd().SetOut(out)
+	rootCmd.RootCmd().SetArgs([]string{"redaction", "--pathConfig", "./testdata", "./testdata/redaction_sample.har"})
+	assert.Nil(t, rootCmd.Execute())
+
+	results := []redaction.DryRunResult{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &results))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "/users/{*}", results[0].Redacted.URI)
+	assert.Equal(t, map[string]string{"Authorization": "{*}"}, results[0].Redacted.RequestHeaders)
+	assert.Equal(t, `{"password":"{*}","user":"joe"}`, results[0].Redacted.RequestPayload)
+	assert.Contains(t, results[0].Matches, redaction.Match{Field: "requestHeader.Cookie", Action: redaction.MatchRemoved})
+	assert.Contains(t, results[0].Matches, redaction.Match{Field: "requestPayload:$.password", Action: redaction.MatchSanitized, Rule: "sanitize jsonPath=$.password"})
+}
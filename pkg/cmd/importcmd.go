@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Axway/agent-sdk/pkg/agent/cache"
+	"github.com/Axway/agent-sdk/pkg/apic"
+	"github.com/Axway/agent-sdk/pkg/apic/auth"
+	"github.com/Axway/agent-sdk/pkg/config"
+)
+
+const pruneFlag = "prune"
+
+// GenImportCmd - generates the import command, applying a directory of API server resource files to Amplify Central
+// with the central config of the agent. Add it to the agent with AgentRootCmd.AddCommand.
+func GenImportCmd(rootCmd AgentRootCmd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import directory",
+		Short: "Create or update the API server resources of the yaml and json files in the directory",
+		Long: "Create or update the API server resources of the yaml and json files in the directory, in dependency order.\n" +
+			"The resources that did not change are not updated. Set central.dryRun to preview the changes.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, ok := rootCmd.(*agentRootCommand)
+			if !ok {
+				return fmt.Errorf("the import command requires an agent root command")
+			}
+			if err := c.initialize(cmd, args); err != nil {
+				return err
+			}
+
+			centralCfg, err := config.ParseCentralConfig(c.GetProperties(), c.GetAgentType())
+			if err != nil {
+				return err
+			}
+			if err := config.ValidateConfig(centralCfg); err != nil {
+				return err
+			}
+
+			resources, err := apic.ReadResourceFiles(args[0])
+			if err != nil {
+				return err
+			}
+
+			opts := []apic.ApplyOption{}
+			if prune, _ := cmd.Flags().GetBool(pruneFlag); prune {
+				opts = append(opts, apic.WithPrune(config.AgentTypeName))
+			}
+
+			client := apic.New(centralCfg, auth.NewPlatformTokenGetterWithCentralConfig(centralCfg), cache.NewAgentCacheManager(centralCfg, false))
+			changes, err := client.ApplyResources(resources, opts...)
+			printChanges(cmd.OutOrStdout(), changes)
+			return err
+		},
+	}
+
+	cmd.Flags().Bool(pruneFlag, false, "Delete the resources created by the agent that are not in the directory")
+	return cmd
+}
+
+// printChanges - writes the action of each change, and the fields of the updated resources that changed
+func printChanges(out io.Writer, changes []apic.PlanChange) {
+	for _, change := range changes {
+		name := change.Name
+		if change.Scope != "" {
+			name = change.Scope + "/" + name
+		}
+		fmt.Fprintf(out, "%-9s %s %s\n", change.Action, change.Kind, name)
+		for _, diff := range change.Diff {
+			fmt.Fprintf(out, "    %s\n", strings.TrimSpace(diff))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Axway/agent-sdk/pkg/traceability/redaction"
+)
+
+// GenRedactionCmd - generates the redaction command, running the sample transactions of HAR or captured event files
+// through the redaction config of the agent. Add it to the agent with AgentRootCmd.AddCommand.
+func GenRedactionCmd(rootCmd AgentRootCmd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redaction file...",
+		Short: "Test the redaction config of the agent with the sample transactions of the files",
+		Long: "Redact the sample transactions of HAR files, or of transaction events captured before they were redacted, " +
+			"with the output.traceability.redaction config of the agent.\n" +
+			"The redacted transactions are written as JSON with the rule that matched each of their fields.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, ok := rootCmd.(*agentRootCommand)
+			if !ok {
+				return fmt.Errorf("the redaction command requires an agent root command")
+			}
+			if err := c.initialize(cmd, args); err != nil {
+				return err
+			}
+
+			redactionCfg := redaction.DefaultConfig()
+			if file := viper.ConfigFileUsed(); file != "" {
+				var err error
+				if redactionCfg, err = redaction.LoadConfigFile(file); err != nil {
+					return err
+				}
+			}
+
+			transactions := []redaction.Transaction{}
+			for _, file := range args {
+				read, err := redaction.ReadTransactions(file)
+				if err != nil {
+					return err
+				}
+				transactions = append(transactions, read...)
+			}
+
+			results, err := redactionCfg.DryRun(transactions)
+			if err != nil {
+				return err
+			}
+			return printRedactionResults(cmd.OutOrStdout(), results)
+		},
+	}
+	return cmd
+}
+
+// printRedactionResults - writes the redacted transactions and their matches as indented JSON
+func printRedactionResults(out io.Writer, results []redaction.DryRunResult) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
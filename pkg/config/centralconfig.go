@@ -215,6 +215,7 @@ type CentralConfig interface {
 	GetAPIClientWorkers() int
 	GetAPIServiceRevisionPattern() string
 	GetAppendEnvironmentToTitle() bool
+	IsDryRun() bool
 	GetUsageReportingConfig() UsageReportingConfig
 	GetMetricReportingConfig() MetricReportingConfig
 	GetErrorSamplingEnabled() bool
@@ -258,6 +259,7 @@ type CentralConfiguration struct {
 	APIServerVersion          string                `config:"apiServerVersion"`
 	TagsToPublish             string                `config:"additionalTags"`
 	AppendEnvironmentToTitle  bool                  `config:"appendEnvironmentToTitle"`
+	DryRun                    bool                  `config:"dryRun"`
 	MigrationSettings         MigrationConfig       `config:"migration"`
 	Auth                      AuthConfig            `config:"auth"`
 	TLS                       TLSConfig             `config:"ssl"`
@@ -607,6 +609,11 @@ func (c *CentralConfiguration) GetAppendEnvironmentToTitle() bool {
 	return c.AppendEnvironmentToTitle
 }
 
+// IsDryRun - Returns true when the agent only plans the changes to Amplify Central, without making them
+func (c *CentralConfiguration) IsDryRun() bool {
+	return c.DryRun
+}
+
 // GetUsageReportingConfig -
 func (c *CentralConfiguration) GetUsageReportingConfig() UsageReportingConfig {
 	// Some paths in DA are checking usage reporting .  So return an empty usage reporting config if nil
@@ -784,6 +791,7 @@ const (
 	pathAPIServerVersion             = "central.apiServerVersion"
 	pathAdditionalTags               = "central.additionalTags"
 	pathAppendEnvironmentToTitle     = "central.appendEnvironmentToTitle"
+	pathDryRun                       = "central.dryRun"
 	pathAPIValidationCronSchedule    = "central.apiValidationCronSchedule"
 	pathJobTimeout                   = "central.jobTimeout"
 	pathGRPCEnabled                  = "central.grpc.enabled"
@@ -1001,6 +1009,7 @@ func AddCentralConfigProperties(props properties.Properties, agentType AgentType
 		props.AddStringSliceProperty(pathRootTagsToStrip, []string{}, "Strips specific tags from the root level of the spec")
 		props.AddStringProperty(pathAdditionalTags, "", "Additional Tags to Add to discovered APIs when publishing to Amplify Central")
 		props.AddBoolProperty(pathAppendEnvironmentToTitle, true, "When true API titles and descriptions will be appended with environment name")
+		props.AddBoolProperty(pathDryRun, false, "When true the agent logs a plan of the resources it would create, update or delete in Amplify Central, without changing them")
 		props.AddIntProperty(pathProvisioningRetryCount, 0, "The number of retries, in case it fails, for any provisioning event", properties.WithUpperLimitInt(3))
 		AddMigrationConfigProperties(props)
 	}
@@ -1082,6 +1091,7 @@ func ParseCentralConfig(props properties.Properties, agentType AgentType) (Centr
 		cfg.TeamName = props.StringPropertyValue(pathTeam)
 		cfg.TagsToPublish = props.StringPropertyValue(pathAdditionalTags)
 		cfg.AppendEnvironmentToTitle = props.BoolPropertyValue(pathAppendEnvironmentToTitle)
+		cfg.DryRun = props.BoolPropertyValue(pathDryRun)
 		cfg.MigrationSettings = ParseMigrationConfig(props)
 		cfg.CredentialConfig = newCredentialConfig()
 		cfg.CredentialConfig.SetAllowedOAuthMethods(props.StringSlicePropertyValue(pathCredentialsOAuthMethods))
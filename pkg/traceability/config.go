@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/Axway/agent-sdk/pkg/agent"
+	"github.com/Axway/agent-sdk/pkg/traceability/otlp"
 	"github.com/Axway/agent-sdk/pkg/traceability/redaction"
 	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
+	"github.com/Axway/agent-sdk/pkg/traceability/spool"
 	"github.com/Axway/agent-sdk/pkg/util/log"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
@@ -40,6 +42,8 @@ type Config struct {
 	Hosts             []string          `config:"hosts"`
 	Redaction         redaction.Config  `config:"redaction" yaml:"redaction"`
 	Sampling          sampling.Sampling `config:"sampling" yaml:"sampling"`
+	Spool             spool.Config      `config:"spool" yaml:"spool"`
+	OTLP              otlp.Config       `config:"otlp" yaml:"otlp"`
 	APIExceptionsList []string          `config:"apiExceptionsList"`
 }
 
@@ -81,6 +85,8 @@ func DefaultConfig() *Config {
 		Protocol:   "https",
 		Redaction:  redaction.DefaultConfig(),
 		Sampling:   sampling.DefaultConfig(),
+		Spool:      spool.DefaultConfig(),
+		OTLP:       otlp.DefaultConfig(),
 	}
 }
 
@@ -137,6 +143,14 @@ func readConfig(cfg *common.Config, info beat.Info) (*Config, error) {
 		}
 	}
 
+	// Setup the export of the transactions as OpenTelemetry spans
+	if outputConfig.OTLP.ServiceName == "" && agent.GetCentralConfig() != nil {
+		outputConfig.OTLP.ServiceName = agent.GetCentralConfig().GetAgentName()
+	}
+	if err := otlp.SetupGlobalExporter(outputConfig.OTLP); err != nil {
+		return nil, err
+	}
+
 	// set up the api exceptions list for logging events
 	exception, err := setUpAPIExceptionList(outputConfig.APIExceptionsList)
 	if err != nil {
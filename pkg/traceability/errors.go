@@ -8,4 +8,5 @@ var (
 	ErrJSONEncodeFailed = errors.New(1504, "failed to encode the json content")
 	ErrInvalidConfig    = errors.Newf(1505, "invalid traceability config. Config error: %s")
 	ErrInvalidRegex     = errors.Newf(1506, "could not compile the %s regex value (%v): %v")
+	ErrSpoolReplay      = errors.New(1507, "could not replay all of the spooled events")
 )
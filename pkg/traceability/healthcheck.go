@@ -52,9 +52,29 @@ func (j *traceabilityHealthCheck) healthcheck(name string) *hc.Status {
 	if j.prevErr != nil {
 		status.Details = fmt.Sprintf("connection error: %s Failed. %s", name, j.prevErr.Error())
 	}
+
+	// the depth of the spool holding the events not yet published
+	if depth := spoolDepth(); depth != "" {
+		if status.Details != "" {
+			status.Details += ". "
+		}
+		status.Details += depth
+	}
 	return status
 }
 
+func spoolDepth() string {
+	client, err := getClient()
+	if err != nil {
+		return ""
+	}
+	eventSpool := client.getSpool()
+	if eventSpool == nil {
+		return ""
+	}
+	return fmt.Sprintf("spooled %s", eventSpool.Depth())
+}
+
 func (j *traceabilityHealthCheck) checkConnections() error {
 	client, err := getClient()
 	if err != nil {
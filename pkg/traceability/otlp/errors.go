@@ -0,0 +1,9 @@
+package otlp
+
+import "github.com/Axway/agent-sdk/pkg/util/errors"
+
+// OTLP errors
+var (
+	ErrInvalidConfig = errors.Newf(1540, "invalid traceability otlp config. Config error: %s")
+	ErrExport        = errors.Newf(1541, "could not export the spans to the otlp endpoint: %v")
+)
@@ -0,0 +1,323 @@
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+const (
+	// ProtocolHTTP - OTLP/HTTP with the JSON encoding
+	ProtocolHTTP = "http"
+	// ProtocolGRPC - OTLP/gRPC with the protobuf encoding
+	ProtocolGRPC = "grpc"
+
+	// ScopeName - the instrumentation scope of the spans exported by the SDK
+	ScopeName = "github.com/Axway/agent-sdk/pkg/transaction"
+
+	defaultServiceName = "amplify-agent"
+	tracesPath         = "/v1/traces"
+	traceServiceRoute  = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	defaultQueueSize   = 2048
+	defaultBatchSize   = 512
+	defaultTimeout     = 10 * time.Second
+	flushInterval      = 5 * time.Second
+)
+
+// Config - the export of the transactions as OpenTelemetry spans to an OTLP endpoint
+type Config struct {
+	Enabled     bool              `config:"enabled" yaml:"enabled"`
+	Protocol    string            `config:"protocol" yaml:"protocol"`
+	Endpoint    string            `config:"endpoint" yaml:"endpoint"`
+	Headers     map[string]string `config:"headers" yaml:"headers"`
+	Insecure    bool              `config:"insecure" yaml:"insecure"`
+	Timeout     time.Duration     `config:"timeout" yaml:"timeout"`
+	ExportOnly  bool              `config:"exportOnly" yaml:"exportOnly"`
+	ServiceName string            `config:"serviceName" yaml:"serviceName"`
+	QueueSize   int               `config:"queueSize" yaml:"queueSize"`
+	BatchSize   int               `config:"batchSize" yaml:"batchSize"`
+}
+
+// DefaultConfig - returns the default otlp config, disabled
+func DefaultConfig() Config {
+	return Config{
+		Enabled:   false,
+		Protocol:  ProtocolHTTP,
+		Timeout:   defaultTimeout,
+		QueueSize: defaultQueueSize,
+		BatchSize: defaultBatchSize,
+	}
+}
+
+// Validate - validates the otlp config when the export is enabled
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return ErrInvalidConfig.FormatError("traceability.otlp.endpoint")
+	}
+	if c.Protocol != ProtocolHTTP && c.Protocol != ProtocolGRPC {
+		return ErrInvalidConfig.FormatError("traceability.otlp.protocol")
+	}
+	if c.QueueSize <= 0 || c.BatchSize <= 0 {
+		return ErrInvalidConfig.FormatError("traceability.otlp.queueSize")
+	}
+	return nil
+}
+
+// sender - sends the traces to the endpoint with one of the OTLP protocols
+type sender interface {
+	send(ctx context.Context, traces TracesData) error
+	close() error
+}
+
+// Exporter - queues the spans and exports them in batches to the OTLP endpoint, in the background
+type Exporter struct {
+	cfg      Config
+	sender   sender
+	resource Resource
+	logger   log.FieldLogger
+	mutex    sync.Mutex
+	queue    chan Span
+	stopped  bool
+	done     chan struct{}
+}
+
+// NewExporter - creates the exporter and starts exporting the queued spans
+func NewExporter(cfg Config) (*Exporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var s sender
+	var err error
+	if cfg.Protocol == ProtocolGRPC {
+		s, err = newGRPCSender(cfg)
+	} else {
+		s = newHTTPSender(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	e := &Exporter{
+		cfg:      cfg,
+		sender:   s,
+		resource: Resource{Attributes: []KeyValue{StringAttribute("service.name", serviceName)}},
+		logger: log.NewFieldLogger().
+			WithPackage("sdk.traceability.otlp").
+			WithComponent("exporter").
+			WithField("endpoint", cfg.Endpoint),
+		queue: make(chan Span, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// ExportOnly - true when the transactions are only exported as spans, and not sent to Amplify
+func (e *Exporter) ExportOnly() bool {
+	return e.cfg.ExportOnly
+}
+
+// Export - queues the spans to be exported, dropping them when the queue is full
+func (e *Exporter) Export(spans ...Span) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.stopped {
+		return
+	}
+	for i, span := range spans {
+		select {
+		case e.queue <- span:
+		default:
+			e.logger.WithField("spans", len(spans)-i).Warn("the otlp export queue is full, dropping spans")
+			return
+		}
+	}
+}
+
+// Stop - exports the queued spans and stops the exporter
+func (e *Exporter) Stop() {
+	e.mutex.Lock()
+	if e.stopped {
+		e.mutex.Unlock()
+		return
+	}
+	e.stopped = true
+	close(e.queue)
+	e.mutex.Unlock()
+
+	<-e.done
+	if err := e.sender.close(); err != nil {
+		e.logger.WithError(err).Debug("closing the otlp connection")
+	}
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Span, 0, e.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			e.logger.WithError(err).WithField("spans", len(batch)).Error("exporting spans")
+		}
+		batch = make([]Span, 0, e.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case span, ok := <-e.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= e.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send - exports the spans in a single request
+func (e *Exporter) send(spans []Span) error {
+	traces := TracesData{
+		ResourceSpans: []ResourceSpans{{
+			Resource:   e.resource,
+			ScopeSpans: []ScopeSpans{{Scope: Scope{Name: ScopeName}, Spans: spans}},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+	if err := e.sender.send(ctx, traces); err != nil {
+		return ErrExport.FormatError(err)
+	}
+	e.logger.WithField("spans", len(spans)).Trace("exported spans")
+	return nil
+}
+
+// httpSender - OTLP/HTTP with the JSON encoding
+type httpSender struct {
+	client  api.Client
+	url     string
+	headers map[string]string
+}
+
+func newHTTPSender(cfg Config) *httpSender {
+	url := strings.TrimSuffix(cfg.Endpoint, "/")
+	if !strings.HasSuffix(url, tracesPath) {
+		url += tracesPath
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	for k, v := range cfg.Headers {
+		headers[k] = v
+	}
+	return &httpSender{
+		client:  api.NewClient(nil, "", api.WithTimeout(cfg.Timeout)),
+		url:     url,
+		headers: headers,
+	}
+}
+
+func (s *httpSender) send(_ context.Context, traces TracesData) error {
+	body, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Send(api.Request{
+		Method:  http.MethodPost,
+		URL:     s.url,
+		Headers: s.headers,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Code < http.StatusOK || resp.Code >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d: %s", resp.Code, string(resp.Body))
+	}
+	return nil
+}
+
+func (s *httpSender) close() error {
+	return nil
+}
+
+// grpcSender - OTLP/gRPC with the protobuf encoding
+type grpcSender struct {
+	conn    *grpc.ClientConn
+	headers map[string]string
+}
+
+func newGRPCSender(cfg Config) (*grpcSender, error) {
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, ErrInvalidConfig.FormatError("traceability.otlp.endpoint")
+	}
+	return &grpcSender{conn: conn, headers: cfg.Headers}, nil
+}
+
+func (s *grpcSender) send(ctx context.Context, traces TracesData) error {
+	for k, v := range s.headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(k), v)
+	}
+	var resp []byte
+	return s.conn.Invoke(ctx, traceServiceRoute, traces, &resp, grpc.ForceCodec(protoCodec{}))
+}
+
+func (s *grpcSender) close() error {
+	return s.conn.Close()
+}
+
+// protoCodec - encodes the export request with the OTLP protobuf encoding, the response is not decoded
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v any) ([]byte, error) {
+	traces, ok := v.(TracesData)
+	if !ok {
+		return nil, fmt.Errorf("unexpected otlp request type %T", v)
+	}
+	return traces.MarshalProto(), nil
+}
+
+func (protoCodec) Unmarshal(data []byte, v any) error {
+	if resp, ok := v.(*[]byte); ok {
+		*resp = data
+	}
+	return nil
+}
+
+func (protoCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,244 @@
+package otlp
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testSpan(name string) Span {
+	return Span{
+		TraceID:           TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:            SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		Name:              name,
+		Kind:              SpanKindServer,
+		StartTimeUnixNano: 1700000000000000000,
+		EndTimeUnixNano:   1700000000050000000,
+		Attributes: []KeyValue{
+			StringAttribute("http.request.method", "GET"),
+			IntAttribute("http.response.status_code", 200),
+		},
+		Status: Status{Code: StatusCodeError, Message: "failed"},
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		update func(*Config)
+		valid  bool
+	}{
+		"disabled config is valid": {
+			update: func(c *Config) { c.Enabled = false },
+			valid:  true,
+		},
+		"enabled with an endpoint": {
+			update: func(c *Config) { c.Endpoint = "http://localhost:4318" },
+			valid:  true,
+		},
+		"enabled without an endpoint": {
+			update: func(c *Config) {},
+		},
+		"unknown protocol": {
+			update: func(c *Config) {
+				c.Endpoint = "http://localhost:4318"
+				c.Protocol = "thrift"
+			},
+		},
+		"no queue": {
+			update: func(c *Config) {
+				c.Endpoint = "http://localhost:4318"
+				c.QueueSize = 0
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Enabled = true
+			tc.update(&cfg)
+			assert.Equal(t, tc.valid, cfg.Validate() == nil)
+		})
+	}
+}
+
+func TestSpanJSON(t *testing.T) {
+	data, err := json.Marshal(testSpan("GET /pets"))
+	assert.Nil(t, err)
+
+	span := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &span))
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", span["traceId"])
+	assert.Equal(t, "0102030405060708", span["spanId"])
+	assert.Equal(t, "", span["parentSpanId"])
+	assert.Equal(t, "1700000000000000000", span["startTimeUnixNano"])
+	assert.Equal(t, float64(SpanKindServer), span["kind"])
+	attributes := span["attributes"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"key": "http.request.method", "value": map[string]interface{}{"stringValue": "GET"}}, attributes[0])
+	assert.Equal(t, map[string]interface{}{"key": "http.response.status_code", "value": map[string]interface{}{"intValue": "200"}}, attributes[1])
+}
+
+// protoFields - the fields of a protobuf message by number, the values of the repeated fields in order
+func protoFields(t *testing.T, b []byte) map[protowire.Number][]interface{} {
+	fields := map[protowire.Number][]interface{}{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		assert.True(t, n > 0)
+		b = b[n:]
+		var value interface{}
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			value, n = protowire.ConsumeFixed64(b)
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+		assert.True(t, n > 0)
+		b = b[n:]
+		fields[num] = append(fields[num], value)
+	}
+	return fields
+}
+
+func TestMarshalProto(t *testing.T) {
+	traces := TracesData{ResourceSpans: []ResourceSpans{{
+		Resource:   Resource{Attributes: []KeyValue{StringAttribute("service.name", "agent")}},
+		ScopeSpans: []ScopeSpans{{Scope: Scope{Name: ScopeName}, Spans: []Span{testSpan("GET /pets")}}},
+	}}}
+
+	request := protoFields(t, traces.MarshalProto())
+	resourceSpans := protoFields(t, request[1][0].([]byte))
+	scopeSpans := protoFields(t, resourceSpans[2][0].([]byte))
+	assert.Equal(t, ScopeName, string(protoFields(t, scopeSpans[1][0].([]byte))[1][0].([]byte)))
+
+	span := protoFields(t, scopeSpans[2][0].([]byte))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, span[1][0])
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, span[2][0])
+	assert.Nil(t, span[4])
+	assert.Equal(t, "GET /pets", string(span[5][0].([]byte)))
+	assert.Equal(t, uint64(SpanKindServer), span[6][0])
+	assert.Equal(t, uint64(1700000000000000000), span[7][0])
+	assert.Equal(t, uint64(1700000000050000000), span[8][0])
+	assert.Len(t, span[9], 2)
+
+	statusCode := protoFields(t, protoFields(t, span[9][1].([]byte))[2][0].([]byte))
+	assert.Equal(t, uint64(200), statusCode[3][0])
+	status := protoFields(t, span[15][0].([]byte))
+	assert.Equal(t, "failed", string(status[2][0].([]byte)))
+	assert.Equal(t, uint64(StatusCodeError), status[3][0])
+}
+
+func TestHTTPExport(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	cfg.Endpoint = server.URL
+	cfg.ServiceName = "traceability-agent"
+	cfg.Headers = map[string]string{"X-Api-Key": "secret"}
+	exporter, err := NewExporter(cfg)
+	assert.Nil(t, err)
+
+	exporter.Export(testSpan("one"), testSpan("two"))
+	exporter.Stop()
+	// spans exported after the exporter stopped are dropped
+	exporter.Export(testSpan("three"))
+
+	req := <-requests
+	assert.Equal(t, "/v1/traces", req.URL.Path)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+
+	traces := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(<-bodies, &traces))
+	resourceSpans := traces["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	resource := resourceSpans["resource"].(map[string]interface{})
+	assert.Equal(t, "traceability-agent", resource["attributes"].([]interface{})[0].(map[string]interface{})["value"].(map[string]interface{})["stringValue"])
+	spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	assert.Len(t, spans, 2)
+	assert.Len(t, requests, 0)
+}
+
+func TestGRPCExport(t *testing.T) {
+	received := make(chan []byte, 1)
+	headers := make(chan metadata.MD, 1)
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(protoCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			assert.Equal(t, traceServiceRoute, method)
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			headers <- md
+
+			var req []byte
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			received <- req
+			return stream.SendMsg(TracesData{})
+		}),
+	)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	cfg.Protocol = ProtocolGRPC
+	cfg.Endpoint = listener.Addr().String()
+	cfg.Insecure = true
+	cfg.Headers = map[string]string{"X-Api-Key": "secret"}
+	exporter, err := NewExporter(cfg)
+	assert.Nil(t, err)
+	exporter.Export(testSpan("one"))
+	exporter.Stop()
+
+	select {
+	case req := <-received:
+		resourceSpans := protoFields(t, protoFields(t, req)[1][0].([]byte))
+		scopeSpans := protoFields(t, resourceSpans[2][0].([]byte))
+		span := protoFields(t, scopeSpans[2][0].([]byte))
+		assert.Equal(t, "one", string(span[5][0].([]byte)))
+		assert.Equal(t, []string{"secret"}, (<-headers).Get("x-api-key"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("no export request received")
+	}
+}
+
+func TestSetupGlobalExporter(t *testing.T) {
+	assert.Nil(t, SetupGlobalExporter(DefaultConfig()))
+	assert.Nil(t, GetGlobalExporter())
+
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	assert.NotNil(t, SetupGlobalExporter(cfg))
+	assert.Nil(t, GetGlobalExporter())
+
+	cfg.Endpoint = "http://localhost:4318"
+	cfg.ExportOnly = true
+	assert.Nil(t, SetupGlobalExporter(cfg))
+	assert.True(t, GetGlobalExporter().ExportOnly())
+
+	assert.Nil(t, SetupGlobalExporter(DefaultConfig()))
+	assert.Nil(t, GetGlobalExporter())
+}
@@ -0,0 +1,38 @@
+package otlp
+
+import "sync"
+
+// Global exporter of the transaction spans
+var (
+	agentExporter *Exporter
+	exporterMutex sync.Mutex
+)
+
+// SetupGlobalExporter - creates the exporter of the transaction spans when enabled in the config, stopping the
+// previous exporter
+func SetupGlobalExporter(cfg Config) error {
+	exporterMutex.Lock()
+	defer exporterMutex.Unlock()
+
+	if agentExporter != nil {
+		agentExporter.Stop()
+		agentExporter = nil
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+
+	exporter, err := NewExporter(cfg)
+	if err != nil {
+		return err
+	}
+	agentExporter = exporter
+	return nil
+}
+
+// GetGlobalExporter - the exporter of the transaction spans, nil when the export is not enabled
+func GetGlobalExporter() *Exporter {
+	exporterMutex.Lock()
+	defer exporterMutex.Unlock()
+	return agentExporter
+}
@@ -0,0 +1,152 @@
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// SpanKind - the kind of a span, as defined by OTLP
+type SpanKind int
+
+// Span kinds
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode - the status of a span, as defined by OTLP
+type StatusCode int
+
+// Status codes
+const (
+	StatusCodeUnset StatusCode = iota
+	StatusCodeOk
+	StatusCodeError
+)
+
+// TraceID - the 16 byte id of a trace, encoded as hex in OTLP JSON
+type TraceID [16]byte
+
+// SpanID - the 8 byte id of a span, encoded as hex in OTLP JSON
+type SpanID [8]byte
+
+// IsEmpty - true when the trace id is all zeros
+func (t TraceID) IsEmpty() bool { return t == TraceID{} }
+
+// String - the hex encoding of the trace id
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// MarshalJSON - the hex encoding of the trace id
+func (t TraceID) MarshalJSON() ([]byte, error) { return json.Marshal(t.String()) }
+
+// IsEmpty - true when the span id is all zeros
+func (s SpanID) IsEmpty() bool { return s == SpanID{} }
+
+// String - the hex encoding of the span id
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// MarshalJSON - the hex encoding of the span id, empty for no span
+func (s SpanID) MarshalJSON() ([]byte, error) {
+	if s.IsEmpty() {
+		return json.Marshal("")
+	}
+	return json.Marshal(s.String())
+}
+
+// Span - a span of a trace
+type Span struct {
+	TraceID           TraceID    `json:"traceId"`
+	SpanID            SpanID     `json:"spanId"`
+	ParentSpanID      SpanID     `json:"parentSpanId"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano Uint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   Uint64     `json:"endTimeUnixNano"`
+	Attributes        []KeyValue `json:"attributes,omitempty"`
+	Status            Status     `json:"status"`
+}
+
+// Status - the status of a span
+type Status struct {
+	Message string     `json:"message,omitempty"`
+	Code    StatusCode `json:"code"`
+}
+
+// KeyValue - an attribute of a span or resource
+type KeyValue struct {
+	Key   string   `json:"key"`
+	Value AnyValue `json:"value"`
+}
+
+// AnyValue - the value of an attribute, one of the values is set
+type AnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *Int64   `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// Int64 - an int64 encoded as a string in OTLP JSON
+type Int64 int64
+
+// MarshalJSON - the decimal string of the value
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+// Uint64 - a uint64 encoded as a string in OTLP JSON
+type Uint64 uint64
+
+// MarshalJSON - the decimal string of the value
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+// StringAttribute - a string attribute
+func StringAttribute(key, value string) KeyValue {
+	return KeyValue{Key: key, Value: AnyValue{StringValue: &value}}
+}
+
+// IntAttribute - an int attribute
+func IntAttribute(key string, value int64) KeyValue {
+	v := Int64(value)
+	return KeyValue{Key: key, Value: AnyValue{IntValue: &v}}
+}
+
+// BoolAttribute - a bool attribute
+func BoolAttribute(key string, value bool) KeyValue {
+	return KeyValue{Key: key, Value: AnyValue{BoolValue: &value}}
+}
+
+// TracesData - the spans exported in a request, the body of an OTLP/HTTP JSON request
+type TracesData struct {
+	ResourceSpans []ResourceSpans `json:"resourceSpans"`
+}
+
+// ResourceSpans - the spans of a resource
+type ResourceSpans struct {
+	Resource   Resource     `json:"resource"`
+	ScopeSpans []ScopeSpans `json:"scopeSpans"`
+}
+
+// Resource - the entity producing the spans
+type Resource struct {
+	Attributes []KeyValue `json:"attributes,omitempty"`
+}
+
+// ScopeSpans - the spans of an instrumentation scope
+type ScopeSpans struct {
+	Scope Scope  `json:"scope"`
+	Spans []Span `json:"spans"`
+}
+
+// Scope - the instrumentation scope of the spans
+type Scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
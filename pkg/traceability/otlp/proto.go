@@ -0,0 +1,121 @@
+package otlp
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The OTLP protobuf encoding of the traces, for the gRPC trace service. The field numbers are those of
+// opentelemetry/proto/collector/trace/v1 ExportTraceServiceRequest and the messages it contains.
+
+// MarshalProto - the protobuf encoding of the export request holding the traces
+func (t TracesData) MarshalProto() []byte {
+	var b []byte
+	for _, rs := range t.ResourceSpans {
+		b = appendMessage(b, 1, rs.marshalProto())
+	}
+	return b
+}
+
+func (rs ResourceSpans) marshalProto() []byte {
+	b := appendMessage(nil, 1, rs.Resource.marshalProto())
+	for _, ss := range rs.ScopeSpans {
+		b = appendMessage(b, 2, ss.marshalProto())
+	}
+	return b
+}
+
+func (r Resource) marshalProto() []byte {
+	var b []byte
+	for _, kv := range r.Attributes {
+		b = appendMessage(b, 1, kv.marshalProto())
+	}
+	return b
+}
+
+func (ss ScopeSpans) marshalProto() []byte {
+	b := appendMessage(nil, 1, ss.Scope.marshalProto())
+	for _, s := range ss.Spans {
+		b = appendMessage(b, 2, s.marshalProto())
+	}
+	return b
+}
+
+func (s Scope) marshalProto() []byte {
+	b := appendString(nil, 1, s.Name)
+	return appendString(b, 2, s.Version)
+}
+
+func (s Span) marshalProto() []byte {
+	b := appendBytes(nil, 1, s.TraceID[:])
+	b = appendBytes(b, 2, s.SpanID[:])
+	if !s.ParentSpanID.IsEmpty() {
+		b = appendBytes(b, 4, s.ParentSpanID[:])
+	}
+	b = appendString(b, 5, s.Name)
+	if s.Kind != SpanKindUnspecified {
+		b = protowire.AppendTag(b, 6, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(s.Kind))
+	}
+	b = protowire.AppendTag(b, 7, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(s.StartTimeUnixNano))
+	b = protowire.AppendTag(b, 8, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(s.EndTimeUnixNano))
+	for _, kv := range s.Attributes {
+		b = appendMessage(b, 9, kv.marshalProto())
+	}
+	return appendMessage(b, 15, s.Status.marshalProto())
+}
+
+func (s Status) marshalProto() []byte {
+	b := appendString(nil, 2, s.Message)
+	if s.Code != StatusCodeUnset {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(s.Code))
+	}
+	return b
+}
+
+func (kv KeyValue) marshalProto() []byte {
+	b := appendString(nil, 1, kv.Key)
+	return appendMessage(b, 2, kv.Value.marshalProto())
+}
+
+func (v AnyValue) marshalProto() []byte {
+	var b []byte
+	switch {
+	case v.StringValue != nil:
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, *v.StringValue)
+	case v.BoolValue != nil:
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(*v.BoolValue))
+	case v.IntValue != nil:
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(*v.IntValue))
+	case v.DoubleValue != nil:
+		b = protowire.AppendTag(b, 4, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(*v.DoubleValue))
+	}
+	return b
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendBytes(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+// appendString - appends the string field, omitted when empty as proto3 does
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
@@ -0,0 +1,225 @@
+package redaction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const redactionConfigPath = "output.traceability.redaction"
+
+// The actions of the rules on the fields of the sample transactions
+const (
+	MatchShown     = "shown"
+	MatchRemoved   = "removed"
+	MatchSanitized = "sanitized"
+	MatchTruncated = "truncated"
+)
+
+// Transaction - a sample transaction to run through the redactions, the fields are not redacted
+type Transaction struct {
+	Name            string              `json:"name,omitempty"`
+	URI             string              `json:"uri,omitempty"`
+	QueryArgs       map[string][]string `json:"queryArgs,omitempty"`
+	RequestHeaders  map[string]string   `json:"requestHeaders,omitempty"`
+	ResponseHeaders map[string]string   `json:"responseHeaders,omitempty"`
+	JMSProperties   map[string]string   `json:"jmsProperties,omitempty"`
+	RequestPayload  string              `json:"requestPayload,omitempty"`
+	ResponsePayload string              `json:"responsePayload,omitempty"`
+}
+
+// Match - the action of a rule on a field of a sample transaction. Removed or sanitized fields without a rule did
+// not match any show keyMatch.
+type Match struct {
+	Field  string `json:"field"`
+	Action string `json:"action"`
+	Rule   string `json:"rule,omitempty"`
+}
+
+// DryRunResult - the redacted sample transaction and the rules that matched its fields
+type DryRunResult struct {
+	Redacted Transaction `json:"redacted"`
+	Matches  []Match     `json:"matches"`
+}
+
+// LoadConfigFile - reads the output.traceability.redaction config of the agent YAML file, the environment variables
+// in the file are resolved as when the agent is run. The default config is returned when the file has none.
+func LoadConfigFile(path string) (Config, error) {
+	cfg := DefaultConfig()
+	file, err := common.LoadFile(path)
+	if err != nil {
+		return cfg, ErrReadingConfig.FormatError(path, err)
+	}
+	if has, _ := file.Has(redactionConfigPath, -1); !has {
+		return cfg, nil
+	}
+	child, err := file.Child(redactionConfigPath, -1)
+	if err != nil {
+		return cfg, ErrReadingConfig.FormatError(path, err)
+	}
+	if err := child.Unpack(&cfg); err != nil {
+		return cfg, ErrReadingConfig.FormatError(path, err)
+	}
+	return cfg, nil
+}
+
+// DryRun - redacts the sample transactions with the config, returning the redacted transactions and the rules that
+// matched each of their fields
+func (cfg *Config) DryRun(transactions []Transaction) ([]DryRunResult, error) {
+	redactions, err := cfg.SetupRedactions()
+	if err != nil {
+		return nil, err
+	}
+	r := redactions.(*redactionRegex)
+
+	results := make([]DryRunResult, 0, len(transactions))
+	for _, transaction := range transactions {
+		result, err := r.dryRun(transaction)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *redactionRegex) dryRun(transaction Transaction) (DryRunResult, error) {
+	var err error
+	redacted := Transaction{Name: transaction.Name}
+	matches := &dryRunMatches{seen: map[Match]bool{}, matches: []Match{}}
+
+	if transaction.URI != "" {
+		argsMatched, argsDone := matches.filterMatches("queryArgument")
+		redacted.URI, err = r.uriRedaction(transaction.URI, matches.pathMatches(), argsMatched)
+		if err != nil {
+			return DryRunResult{}, err
+		}
+		argsDone()
+	}
+	if len(transaction.QueryArgs) > 0 {
+		argsMatched, argsDone := matches.filterMatches("queryArgument")
+		redacted.QueryArgs, _ = r.queryArgsRedaction(transaction.QueryArgs, argsMatched)
+		argsDone()
+	}
+
+	// the payloads are redacted with the content type before the headers are redacted
+	redacted.RequestPayload = r.payloadRedaction(transaction.RequestPayload, headerValue(transaction.RequestHeaders, "Content-Type"), r.payloadMatches("requestPayload", matches))
+	redacted.ResponsePayload = r.payloadRedaction(transaction.ResponsePayload, headerValue(transaction.ResponseHeaders, "Content-Type"), r.payloadMatches("responsePayload", matches))
+
+	if len(transaction.RequestHeaders) > 0 {
+		headersMatched, headersDone := matches.filterMatches("requestHeader")
+		redacted.RequestHeaders, _ = r.headersRedaction(transaction.RequestHeaders, r.requestHeaderFilters, headersMatched)
+		headersDone()
+	}
+	if len(transaction.ResponseHeaders) > 0 {
+		headersMatched, headersDone := matches.filterMatches("responseHeader")
+		redacted.ResponseHeaders, _ = r.headersRedaction(transaction.ResponseHeaders, r.responseHeaderFilters, headersMatched)
+		headersDone()
+	}
+	if len(transaction.JMSProperties) > 0 {
+		propertiesMatched, propertiesDone := matches.filterMatches("jmsProperty")
+		redacted.JMSProperties, _ = r.headersRedaction(transaction.JMSProperties, r.jmsPropertiesFilters, propertiesMatched)
+		propertiesDone()
+	}
+
+	return DryRunResult{Redacted: redacted, Matches: matches.matches}, nil
+}
+
+// filterMatched - the callback of the redactions given the action of the show and sanitize rules on a named value,
+// nil when the actions are not reported
+type filterMatched func(name, action, rule string)
+
+func (f filterMatched) shown(name string, show *showRegex) {
+	if f != nil {
+		f(name, MatchShown, "show keyMatch="+show.keyMatch.String())
+	}
+}
+
+func (f filterMatched) removed(name string) {
+	if f != nil {
+		f(name, MatchRemoved, "")
+	}
+}
+
+// sanitized - the value was sanitized by the rule, a nil rule when it was sanitized as no show rule matched
+func (f filterMatched) sanitized(name string, sanitize *sanitizeRegex) {
+	if f == nil {
+		return
+	}
+	rule := ""
+	if sanitize != nil {
+		rule = "sanitize keyMatch=" + sanitize.keyMatch.String() + ",valueMatch=" + sanitize.valueMatch.String()
+	}
+	f(name, MatchSanitized, rule)
+}
+
+// dryRunMatches - the matches of a transaction, in the order they were found, without duplicates
+type dryRunMatches struct {
+	seen    map[Match]bool
+	matches []Match
+}
+
+func (m *dryRunMatches) add(field, action, rule string) {
+	match := Match{Field: field, Action: action, Rule: rule}
+	if m.seen[match] {
+		return
+	}
+	m.seen[match] = true
+	m.matches = append(m.matches, match)
+}
+
+// pathMatches - the callback of pathRedaction adding the matches of the path segments
+func (m *dryRunMatches) pathMatches() filterMatched {
+	return func(index, action, rule string) {
+		m.add("path["+index+"]", action, rule)
+	}
+}
+
+// filterMatches - the callback of the redactions of named values, and the func adding their matches once the
+// redaction is done. The values are redacted in the random order of a map, their matches are added sorted by name.
+func (m *dryRunMatches) filterMatches(prefix string) (filterMatched, func()) {
+	found := []Match{}
+	matched := func(name, action, rule string) {
+		found = append(found, Match{Field: prefix + "." + name, Action: action, Rule: rule})
+	}
+	done := func() {
+		sort.SliceStable(found, func(i, j int) bool {
+			return found[i].Field < found[j].Field
+		})
+		for _, match := range found {
+			m.add(match.Field, match.Action, match.Rule)
+		}
+	}
+	return matched, done
+}
+
+// payloadMatches - the callback of payloadRedaction adding the matches of the payload fields
+func (r *redactionRegex) payloadMatches(prefix string, matches *dryRunMatches) func(field, rule string) {
+	return func(field, rule string) {
+		if field == "payload" {
+			field = prefix
+		} else {
+			field = prefix + ":" + field
+		}
+		switch rule {
+		case "contentTypes":
+			matches.add(field, MatchRemoved, "")
+		case "maxSize":
+			matches.add(field, MatchTruncated, fmt.Sprintf("maxSize=%d", r.payloadFilters.maxSize))
+		default:
+			matches.add(field, MatchSanitized, "sanitize "+rule)
+		}
+	}
+}
+
+// headerValue - the value of the header, the name is not case sensitive
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
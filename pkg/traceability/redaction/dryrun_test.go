@@ -0,0 +1,172 @@
+package redaction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Path.Allowed = []Show{{KeyMatch: "^users$"}}
+	cfg.Args.Allowed = []Show{{KeyMatch: "^id$"}}
+	cfg.RequestHeaders = Filter{
+		Allowed:  []Show{{KeyMatch: "(?i)^authorization$"}, {KeyMatch: "(?i)^content-type$"}},
+		Sanitize: []Sanitize{{KeyMatch: "(?i)^authorization$", ValueMatch: "Bearer .*"}},
+	}
+	cfg.JMSProperties.Allowed = []Show{{KeyMatch: "^jmsMessageID$"}}
+	cfg.Payload = Payload{Sanitize: []PayloadSanitize{{JSONPath: "$.password"}, {Pattern: PatternEmail}}, MaxSize: 40}
+
+	results, err := cfg.DryRun([]Transaction{
+		{
+			Name:      "http",
+			URI:       "/users/1234?id=5&token=abc",
+			QueryArgs: map[string][]string{"id": {"5"}, "token": {"abc"}},
+			RequestHeaders: map[string]string{
+				"Authorization": "Bearer xyz",
+				"Content-Type":  "application/json",
+				"Cookie":        "a=b",
+			},
+			RequestPayload: `{"user":"joe@example.com","password":"secret"}`,
+		},
+		{
+			Name:          "jms",
+			JMSProperties: map[string]string{"jmsMessageID": "1", "jmsReplyTo": "queue"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, Transaction{
+		Name:           "http",
+		URI:            "/users/{*}?id=5",
+		QueryArgs:      map[string][]string{"id": {"5"}},
+		RequestHeaders: map[string]string{"Authorization": "{*}", "Content-Type": "application/json"},
+		RequestPayload: `{"password":"{*}","user":"{*}"}`,
+	}, results[0].Redacted)
+	// the query arguments of the uri and of the args are listed once
+	assert.Equal(t, []Match{
+		{Field: "path[1]", Action: MatchShown, Rule: "show keyMatch=^users$"},
+		{Field: "path[2]", Action: MatchSanitized},
+		{Field: "queryArgument.id", Action: MatchShown, Rule: "show keyMatch=^id$"},
+		{Field: "queryArgument.token", Action: MatchRemoved},
+		{Field: "requestPayload:$.password", Action: MatchSanitized, Rule: "sanitize jsonPath=$.password"},
+		{Field: "requestPayload:$.user", Action: MatchSanitized, Rule: "sanitize pattern=email"},
+		{Field: "requestHeader.Authorization", Action: MatchShown, Rule: "show keyMatch=(?i)^authorization$"},
+		{Field: "requestHeader.Authorization", Action: MatchSanitized, Rule: "sanitize keyMatch=(?i)^authorization$,valueMatch=Bearer .*"},
+		{Field: "requestHeader.Content-Type", Action: MatchShown, Rule: "show keyMatch=(?i)^content-type$"},
+		{Field: "requestHeader.Cookie", Action: MatchRemoved},
+	}, results[0].Matches)
+
+	assert.Equal(t, map[string]string{"jmsMessageID": "1"}, results[1].Redacted.JMSProperties)
+	assert.Equal(t, []Match{
+		{Field: "jmsProperty.jmsMessageID", Action: MatchShown, Rule: "show keyMatch=^jmsMessageID$"},
+		{Field: "jmsProperty.jmsReplyTo", Action: MatchRemoved},
+	}, results[1].Matches)
+
+	// the payload rules that apply to the whole payload
+	results, err = cfg.DryRun([]Transaction{{ResponsePayload: "mail joe@example.com for the details of the order"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "mail {*} for the details of the order", results[0].Redacted.ResponsePayload)
+	assert.Equal(t, []Match{{Field: "responsePayload", Action: MatchSanitized, Rule: "sanitize pattern=email"}}, results[0].Matches)
+
+	cfg.Payload.MaxSize = 10
+	results, err = cfg.DryRun([]Transaction{{ResponsePayload: "the details of the order"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Match{{Field: "responsePayload", Action: MatchTruncated, Rule: "maxSize=10"}}, results[0].Matches)
+
+	// invalid rules fail the dry run
+	cfg.Path.Allowed = []Show{{KeyMatch: "(["}}
+	_, err = cfg.DryRun([]Transaction{})
+	assert.NotNil(t, err)
+}
+
+func TestReadTransactions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	har := write("sample.har", `{"log":{"entries":[{
+		"request":{"method":"POST","url":"https://api.example.com/users?id=5",
+			"headers":[{"name":"Accept","value":"text/plain"},{"name":"Accept","value":"application/json"}],
+			"queryString":[{"name":"id","value":"5"}],"postData":{"mimeType":"application/json","text":"{\"a\":1}"}},
+		"response":{"status":200,"headers":[],"content":{"mimeType":"application/json","text":"eyJvayI6dHJ1ZX0=","encoding":"base64"}}}]}}`)
+	transactions, err := ReadTransactions(har)
+	assert.Nil(t, err)
+	assert.Equal(t, []Transaction{{
+		Name:            "POST https://api.example.com/users?id=5",
+		URI:             "https://api.example.com/users?id=5",
+		QueryArgs:       map[string][]string{"id": {"5"}},
+		RequestHeaders:  map[string]string{"Accept": "text/plain, application/json"},
+		ResponseHeaders: map[string]string{},
+		RequestPayload:  `{"a":1}`,
+		ResponsePayload: `{"ok":true}`,
+	}}, transactions)
+
+	// one event per line, the summary is skipped
+	events := write("events.json", `{"transactionId":"t1","transactionSummary":{"status":"Success"}}
+{"transactionId":"t1","transactionEvent":{"id":"leg0","protocol":{"type":"http","uri":"/users/1","args":"{\"id\":[\"5\"]}",`+
+		`"requestHeaders":"{\"Authorization\":\"Bearer xyz\"}","responseHeaders":"{\"Content-Length\":12}","responsePayload":"hello"}}}
+[{"transactionId":"t2","transactionEvent":{"id":"leg0","protocol":{"type":"jms","jmsMessageID":"m1","jmsTimestamp":1700000000000}}}]`)
+	transactions, err = ReadTransactions(events)
+	assert.Nil(t, err)
+	assert.Equal(t, []Transaction{
+		{
+			Name:            "t1/leg0",
+			URI:             "/users/1",
+			QueryArgs:       map[string][]string{"id": {"5"}},
+			RequestHeaders:  map[string]string{"Authorization": "Bearer xyz"},
+			ResponseHeaders: map[string]string{"Content-Length": "12"},
+			ResponsePayload: "hello",
+		},
+		{
+			Name:          "t2/leg0",
+			JMSProperties: map[string]string{"jmsMessageID": "m1", "jmsTimestamp": "1700000000000"},
+		},
+	}, transactions)
+
+	_, err = ReadTransactions(write("invalid.json", `{"log":`))
+	assert.NotNil(t, err)
+	_, err = ReadTransactions(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.yml")
+	os.Setenv("TEST_REDACTION_MASK", "###")
+	defer os.Unsetenv("TEST_REDACTION_MASK")
+	assert.Nil(t, os.WriteFile(path, []byte(`output.traceability:
+  hosts: ["ingestion:5044"]
+  redaction:
+    maskingCharacters: ${TEST_REDACTION_MASK}
+    path:
+      show:
+        - keyMatch: "^users$"
+    payload:
+      sanitize:
+        - jsonPath: "$.password"
+      maxSize: 100
+`), 0600))
+
+	cfg, err := LoadConfigFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "###", cfg.MaskingCharacters)
+	assert.Equal(t, []Show{{KeyMatch: "^users$"}}, cfg.Path.Allowed)
+	assert.Equal(t, []PayloadSanitize{{JSONPath: "$.password"}}, cfg.Payload.Sanitize)
+	assert.Equal(t, 100, cfg.Payload.MaxSize)
+
+	// the default config when the file has no redaction config
+	assert.Nil(t, os.WriteFile(path, []byte("central:\n  url: https://central\n"), 0600))
+	cfg, err = LoadConfigFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+
+	_, err = LoadConfigFile(filepath.Join(dir, "missing.yml"))
+	assert.NotNil(t, err)
+}
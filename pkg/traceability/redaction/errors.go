@@ -6,4 +6,12 @@ import "github.com/Axway/agent-sdk/pkg/util/errors"
 var (
 	ErrGlobalRedactionCfg = errors.New(1510, "the global redaction config has not been initialized")
 	ErrInvalidRegex       = errors.Newf(1511, "could not compile the %s regex value (%v): %v")
+	ErrInvalidPath        = errors.Newf(1512, "could not parse the %s payload path (%v): %v")
+	ErrInvalidPayloadRule = errors.Newf(1513, "invalid payload redaction rule: %s")
+)
+
+// Dry run errors
+var (
+	ErrReadingConfig  = errors.Newf(1514, "could not read the redaction config of %s: %v")
+	ErrReadingSamples = errors.Newf(1515, "could not read the sample transactions of %s: %v")
 )
@@ -121,20 +121,38 @@ func JMSPropertiesRedaction(properties map[string]string) (map[string]string, er
 	return agentRedactions.JMSPropertiesRedaction(properties)
 }
 
+// PayloadRedaction - accepts a request or response payload and its content type and returns the redacted payload
+func PayloadRedaction(payload, contentType string) (string, error) {
+	if agentRedactions == nil {
+		return "", ErrGlobalRedactionCfg
+	}
+	payloadRedactions, ok := agentRedactions.(PayloadRedactions)
+	if !ok {
+		return payload, nil
+	}
+	return payloadRedactions.PayloadRedaction(payload, contentType)
+}
+
 func isValidValueToShow(value string, matchers []showRegex) bool {
-	for _, matcher := range matchers {
+	return firstShow(value, matchers) != nil
+}
+
+// firstShow - the first of the show rules matching the value, nil when none match
+func firstShow(value string, matchers []showRegex) *showRegex {
+	for i, matcher := range matchers {
 		if matcher.keyMatch.MatchString(value) {
-			return true
+			return &matchers[i]
 		}
 	}
-	return false
+	return nil
 }
 
-func shouldSanitize(value string, matchers []sanitizeRegex) (bool, *regexp.Regexp) {
-	for _, matcher := range matchers {
+// firstSanitize - the first of the sanitize rules matching the value, nil when none match
+func firstSanitize(value string, matchers []sanitizeRegex) *sanitizeRegex {
+	for i, matcher := range matchers {
 		if matcher.keyMatch.MatchString(value) {
-			return true, matcher.valueMatch
+			return &matchers[i]
 		}
 	}
-	return false, nil
+	return nil
 }
@@ -0,0 +1,168 @@
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath - a compiled JSONPath selecting the values of a JSON payload. The supported subset is the root ($),
+// members (.name or ['name']), indexes ([0], negative from the end), wildcards (.* or [*]) and the recursive
+// descent (..name or ..*)
+type jsonPath struct {
+	raw      string
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	key       string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool
+}
+
+var jsonPathIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*`)
+
+func compileJSONPath(path string) (*jsonPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("the path must start with $")
+	}
+	p := &jsonPath{raw: path, segments: []jsonPathSegment{}}
+	rest := path[1:]
+	for rest != "" {
+		seg := jsonPathSegment{}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			seg.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			if strings.HasPrefix(rest, "*") {
+				seg.wildcard = true
+				rest = rest[1:]
+				p.segments = append(p.segments, seg)
+				continue
+			}
+			name := jsonPathIdentifier.FindString(rest)
+			if name == "" {
+				return nil, fmt.Errorf("expected a member name at %q", rest)
+			}
+			seg.key = name
+			rest = rest[len(name):]
+			p.segments = append(p.segments, seg)
+			continue
+		case !strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		// bracket notation
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed bracket at %q", rest)
+		}
+		inner := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+		switch {
+		case inner == "*":
+			seg.wildcard = true
+		case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+			seg.key = inner[1 : len(inner)-1]
+		default:
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q", inner)
+			}
+			seg.index = index
+			seg.isIndex = true
+		}
+		p.segments = append(p.segments, seg)
+	}
+	return p, nil
+}
+
+// visit - calls fn with the path, value and setter of each value the json path selects in the document
+func (p *jsonPath) visit(doc interface{}, fn func(path string, value interface{}, set func(interface{}))) {
+	walkJSONPath(doc, p.segments, "$", nil, fn)
+}
+
+func walkJSONPath(node interface{}, segments []jsonPathSegment, path string, set func(interface{}), fn func(string, interface{}, func(interface{}))) {
+	if len(segments) == 0 {
+		if set != nil {
+			fn(path, node, set)
+		}
+		return
+	}
+
+	seg := segments[0]
+	if seg.recursive {
+		// match the segment here, and in all the descendants
+		here := seg
+		here.recursive = false
+		walkJSONPath(node, append([]jsonPathSegment{here}, segments[1:]...), path, set, fn)
+		forEachJSONChild(node, path, func(child interface{}, childPath string, childSet func(interface{})) {
+			walkJSONPath(child, segments, childPath, childSet, fn)
+		})
+		return
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if seg.isIndex {
+			return
+		}
+		forEachJSONChild(n, path, func(child interface{}, childPath string, childSet func(interface{})) {
+			if seg.wildcard || childPath == jsonMemberPath(path, seg.key) {
+				walkJSONPath(child, segments[1:], childPath, childSet, fn)
+			}
+		})
+	case []interface{}:
+		if seg.key != "" {
+			return
+		}
+		index := seg.index
+		if index < 0 {
+			index += len(n)
+		}
+		forEachJSONChild(n, path, func(child interface{}, childPath string, childSet func(interface{})) {
+			if seg.wildcard || childPath == fmt.Sprintf("%s[%d]", path, index) {
+				walkJSONPath(child, segments[1:], childPath, childSet, fn)
+			}
+		})
+	}
+}
+
+// forEachJSONChild - calls fn for the members of an object, sorted by name, or the items of an array
+func forEachJSONChild(node interface{}, path string, fn func(child interface{}, childPath string, set func(interface{}))) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			fn(n[key], jsonMemberPath(path, key), func(v interface{}) { n[key] = v })
+		}
+	case []interface{}:
+		for i := range n {
+			index := i
+			fn(n[index], fmt.Sprintf("%s[%d]", path, index), func(v interface{}) { n[index] = v })
+		}
+	}
+}
+
+// jsonMemberPath - the path of the member, in dot notation when the name allows it
+func jsonMemberPath(path, key string) string {
+	if jsonPathIdentifier.FindString(key) == key {
+		return path + "." + key
+	}
+	return path + "['" + key + "']"
+}
@@ -0,0 +1,466 @@
+package redaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// Payload value patterns, the names of the built in valueMatch expressions
+const (
+	PatternCreditCard = "creditCard"
+	PatternEmail      = "email"
+	PatternJWT        = "jwt"
+)
+
+var payloadPatterns = map[string]*regexp.Regexp{
+	PatternCreditCard: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+	PatternEmail:      regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	PatternJWT:        regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+}
+
+type payloadFormat int
+
+const (
+	payloadText payloadFormat = iota
+	payloadJSON
+	payloadXML
+)
+
+// Payload - the redaction of the request and response payloads
+type Payload struct {
+	Sanitize     []PayloadSanitize `config:"sanitize" yaml:"sanitize"`
+	ContentTypes []Show            `config:"contentTypes" yaml:"contentTypes"`
+	MaxSize      int               `config:"maxSize" yaml:"maxSize"`
+}
+
+// PayloadSanitize - the fields and values of the payloads to sanitize. A jsonPath or xPath selects the fields of the
+// JSON or XML payloads, the whole field value is sanitized unless a valueMatch or pattern is set. Without a path the
+// valueMatch or pattern is applied to all the values of the payloads.
+type PayloadSanitize struct {
+	JSONPath   string `config:"jsonPath" yaml:"jsonPath"`
+	XPath      string `config:"xPath" yaml:"xPath"`
+	ValueMatch string `config:"valueMatch" yaml:"valueMatch"`
+	Pattern    string `config:"pattern" yaml:"pattern"`
+}
+
+// payloadRegex - the compiled payload redaction config
+type payloadRegex struct {
+	rules        []payloadRule
+	contentTypes []showRegex
+	maxSize      int
+}
+
+type payloadRule struct {
+	name       string
+	jsonPath   *jsonPath
+	xPath      *xPath
+	valueMatch *regexp.Regexp
+	validate   func(string) bool
+}
+
+func setupPayloadRegex(cfg Payload) (payloadRegex, error) {
+	var err error
+	payload := payloadRegex{rules: make([]payloadRule, 0), maxSize: cfg.MaxSize}
+	payload.contentTypes, err = setupShowRegex(cfg.ContentTypes)
+	if err != nil {
+		return payloadRegex{}, err
+	}
+
+	for _, sanitize := range cfg.Sanitize {
+		if sanitize == (PayloadSanitize{}) {
+			continue // ignore blank rules as they match nothing
+		}
+		rule, err := setupPayloadRule(sanitize)
+		if err != nil {
+			log.Error(err)
+			return payloadRegex{}, err
+		}
+		payload.rules = append(payload.rules, rule)
+	}
+	return payload, nil
+}
+
+func setupPayloadRule(sanitize PayloadSanitize) (payloadRule, error) {
+	rule := payloadRule{}
+	names := []string{}
+	var err error
+
+	if sanitize.JSONPath != "" && sanitize.XPath != "" {
+		return rule, ErrInvalidPayloadRule.FormatError("jsonPath and xPath can not both be set")
+	}
+	if sanitize.ValueMatch != "" && sanitize.Pattern != "" {
+		return rule, ErrInvalidPayloadRule.FormatError("valueMatch and pattern can not both be set")
+	}
+
+	if sanitize.JSONPath != "" {
+		rule.jsonPath, err = compileJSONPath(sanitize.JSONPath)
+		if err != nil {
+			return rule, ErrInvalidPath.FormatError("jsonPath", sanitize.JSONPath, err)
+		}
+		names = append(names, "jsonPath="+sanitize.JSONPath)
+	}
+	if sanitize.XPath != "" {
+		rule.xPath, err = compileXPath(sanitize.XPath)
+		if err != nil {
+			return rule, ErrInvalidPath.FormatError("xPath", sanitize.XPath, err)
+		}
+		names = append(names, "xPath="+sanitize.XPath)
+	}
+	if sanitize.ValueMatch != "" {
+		rule.valueMatch, err = regexp.Compile(sanitize.ValueMatch)
+		if err != nil {
+			return rule, ErrInvalidRegex.FormatError("valueMatch", sanitize.ValueMatch, err)
+		}
+		names = append(names, "valueMatch="+sanitize.ValueMatch)
+	}
+	if sanitize.Pattern != "" {
+		pattern, ok := payloadPatterns[sanitize.Pattern]
+		if !ok {
+			return rule, ErrInvalidPayloadRule.FormatError("unknown pattern " + sanitize.Pattern)
+		}
+		rule.valueMatch = pattern
+		if sanitize.Pattern == PatternCreditCard {
+			rule.validate = luhnValid
+		}
+		names = append(names, "pattern="+sanitize.Pattern)
+	}
+	rule.name = strings.Join(names, ",")
+	return rule, nil
+}
+
+// PayloadRedaction - accepts a request or response payload and its content type and returns the redacted payload
+func (r *redactionRegex) PayloadRedaction(payload, contentType string) (string, error) {
+	return r.payloadRedaction(payload, contentType, nil), nil
+}
+
+// payloadRedaction - redacts the payload, calling matched with the field and name of each rule applied
+func (r *redactionRegex) payloadRedaction(payload, contentType string, matched func(field, rule string)) string {
+	// without payload rules the payload is kept as it was sent, it is not parsed
+	filters := r.payloadFilters
+	if payload == "" || (len(filters.rules) == 0 && len(filters.contentTypes) == 0 && filters.maxSize <= 0) {
+		return payload
+	}
+	if matched == nil {
+		matched = func(string, string) {}
+	}
+
+	// remove the payloads of the content types that are not shown
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	if len(r.payloadFilters.contentTypes) > 0 && mediaType != "" && !isValidValueToShow(mediaType, r.payloadFilters.contentTypes) {
+		matched("payload", "contentTypes")
+		return ""
+	}
+
+	redacted := payload
+	switch detectPayloadFormat(payload, mediaType) {
+	case payloadJSON:
+		redacted = r.jsonPayloadRedaction(payload, matched)
+	case payloadXML:
+		redacted = r.xmlPayloadRedaction(payload, matched)
+	default:
+		redacted = r.textPayloadRedaction(payload, matched)
+	}
+
+	if max := r.payloadFilters.maxSize; max > 0 && len(redacted) > max {
+		matched("payload", "maxSize")
+		redacted = truncateUTF8(redacted, max)
+	}
+	return redacted
+}
+
+// detectPayloadFormat - the format from the content type, or from the payload when the content type is not set
+func detectPayloadFormat(payload, mediaType string) payloadFormat {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case strings.Contains(mediaType, "json"):
+		return payloadJSON
+	case strings.Contains(mediaType, "xml"):
+		return payloadXML
+	case mediaType != "":
+		return payloadText
+	}
+	trimmed := strings.TrimSpace(payload)
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return payloadJSON
+	case strings.HasPrefix(trimmed, "<"):
+		return payloadXML
+	}
+	return payloadText
+}
+
+// sanitizePayloadValue - the value with the matches of the rule sanitized, or all of it when the rule has no value match
+func (r *redactionRegex) sanitizePayloadValue(value string, rule payloadRule) (string, bool) {
+	if rule.valueMatch == nil {
+		return r.sanitizeValue, value != r.sanitizeValue
+	}
+	changed := false
+	sanitized := rule.valueMatch.ReplaceAllStringFunc(value, func(match string) string {
+		if rule.validate != nil && !rule.validate(match) {
+			return match
+		}
+		changed = true
+		return r.sanitizeValue
+	})
+	return sanitized, changed
+}
+
+// valueRules - the rules applied to all the values, without a path
+func (r *redactionRegex) valueRules() []payloadRule {
+	rules := []payloadRule{}
+	for _, rule := range r.payloadFilters.rules {
+		if rule.jsonPath == nil && rule.xPath == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (r *redactionRegex) textPayloadRedaction(payload string, matched func(field, rule string)) string {
+	for _, rule := range r.valueRules() {
+		var changed bool
+		if payload, changed = r.sanitizePayloadValue(payload, rule); changed {
+			matched("payload", rule.name)
+		}
+	}
+	return payload
+}
+
+func (r *redactionRegex) jsonPayloadRedaction(payload string, matched func(field, rule string)) string {
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil || decoder.More() {
+		// not valid json, i.e. a truncated snippet
+		return r.textPayloadRedaction(payload, matched)
+	}
+
+	changed := false
+	sanitize := func(rule payloadRule) func(string, interface{}, func(interface{})) {
+		return func(path string, value interface{}, set func(interface{})) {
+			var str string
+			switch v := value.(type) {
+			case string:
+				str = v
+			case json.Number:
+				str = v.String()
+			default:
+				if rule.valueMatch != nil {
+					return // only the strings and numbers are matched
+				}
+				str, _ = toJSONString(v)
+			}
+			if sanitized, ok := r.sanitizePayloadValue(str, rule); ok {
+				set(sanitized)
+				changed = true
+				matched(path, rule.name)
+			}
+		}
+	}
+
+	for _, rule := range r.payloadFilters.rules {
+		switch {
+		case rule.jsonPath != nil:
+			rule.jsonPath.visit(doc, sanitize(rule))
+		case rule.xPath == nil:
+			visitJSONValues(doc, "$", nil, sanitize(rule))
+		}
+	}
+	if !changed {
+		return payload
+	}
+
+	redacted, err := toJSONString(doc)
+	if err != nil {
+		return r.textPayloadRedaction(payload, matched)
+	}
+	return redacted
+}
+
+// visitJSONValues - calls fn with each of the strings and numbers in the document
+func visitJSONValues(node interface{}, path string, set func(interface{}), fn func(string, interface{}, func(interface{}))) {
+	switch node.(type) {
+	case map[string]interface{}, []interface{}:
+		forEachJSONChild(node, path, func(child interface{}, childPath string, childSet func(interface{})) {
+			visitJSONValues(child, childPath, childSet, fn)
+		})
+	case string, json.Number:
+		if set != nil {
+			fn(path, node, set)
+		}
+	}
+}
+
+func toJSONString(v interface{}) (string, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// xmlEdit - the replacement of a range of the raw payload
+type xmlEdit struct {
+	start, end int
+	value      string
+}
+
+func (r *redactionRegex) xmlPayloadRedaction(payload string, matched func(field, rule string)) string {
+	decoder := xml.NewDecoder(strings.NewReader(payload))
+	decoder.Strict = false
+
+	edits := []xmlEdit{}
+	elements := []string{}
+	// the depth of the outermost element selected by each rule
+	selected := map[int]int{}
+
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// not valid xml, i.e. a truncated snippet
+			return r.textPayloadRedaction(payload, matched)
+		}
+		end := int(decoder.InputOffset())
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			elements = append(elements, t.Name.Local)
+			path := "/" + strings.Join(elements, "/")
+			for i, rule := range r.payloadFilters.rules {
+				if rule.xPath != nil && rule.xPath.matchesElement(elements) {
+					if _, ok := selected[i]; !ok {
+						selected[i] = len(elements)
+					}
+				}
+			}
+			edits = append(edits, r.xmlAttributeEdits(payload[start:end], start, t, elements, path, matched)...)
+		case xml.EndElement:
+			for i, depth := range selected {
+				if depth == len(elements) {
+					delete(selected, i)
+				}
+			}
+			if len(elements) > 0 {
+				elements = elements[:len(elements)-1]
+			}
+		case xml.CharData:
+			text := string(t)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			path := "/" + strings.Join(elements, "/")
+			value, changed := text, false
+			for i, rule := range r.payloadFilters.rules {
+				_, isSelected := selected[i]
+				if !isSelected && (rule.xPath != nil || rule.jsonPath != nil) {
+					continue
+				}
+				if sanitized, ok := r.sanitizePayloadValue(value, rule); ok {
+					value, changed = sanitized, true
+					matched(path, rule.name)
+				}
+			}
+			if changed {
+				edits = append(edits, xmlEdit{start: start, end: end, value: escapeXMLText(value)})
+			}
+		}
+	}
+
+	if len(edits) == 0 {
+		return payload
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	out := strings.Builder{}
+	last := 0
+	for _, edit := range edits {
+		out.WriteString(payload[last:edit.start])
+		out.WriteString(edit.value)
+		last = edit.end
+	}
+	out.WriteString(payload[last:])
+	return out.String()
+}
+
+// xmlAttributeEdits - the edits of the raw start element for the sanitized attribute values
+func (r *redactionRegex) xmlAttributeEdits(raw string, offset int, element xml.StartElement, elements []string, path string, matched func(field, rule string)) []xmlEdit {
+	edits := []xmlEdit{}
+	for _, attr := range element.Attr {
+		name := attr.Name.Local
+		if attr.Name.Space != "" {
+			name = attr.Name.Space + ":" + name
+		}
+		value, changed := attr.Value, false
+		for _, rule := range r.payloadFilters.rules {
+			if rule.xPath != nil && !rule.xPath.matchesAttribute(elements, name) {
+				continue
+			}
+			if rule.xPath == nil && rule.jsonPath != nil {
+				continue
+			}
+			if sanitized, ok := r.sanitizePayloadValue(value, rule); ok {
+				value, changed = sanitized, true
+				matched(path+"/@"+name, rule.name)
+			}
+		}
+		if !changed {
+			continue
+		}
+		attrRegex := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `\s*=\s*("[^"]*"|'[^']*')`)
+		loc := attrRegex.FindStringSubmatchIndex(raw)
+		if loc == nil {
+			continue
+		}
+		edits = append(edits, xmlEdit{start: offset + loc[2] + 1, end: offset + loc[3] - 1, value: escapeXMLText(value)})
+	}
+	return edits
+}
+
+func escapeXMLText(value string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(value))
+	return buf.String()
+}
+
+// truncateUTF8 - the first max bytes of the value, without splitting a character
+func truncateUTF8(value string, max int) string {
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
+
+// luhnValid - true when the digits of the value pass the Luhn check of the card numbers
+func luhnValid(value string) bool {
+	sum, double, digits := 0, false, 0
+	for i := len(value) - 1; i >= 0; i-- {
+		c := value[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
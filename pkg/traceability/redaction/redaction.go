@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/Axway/agent-sdk/pkg/util/log"
@@ -26,14 +27,21 @@ type Redactions interface {
 	JMSPropertiesRedaction(jmsProperties map[string]string) (map[string]string, error)
 }
 
+// PayloadRedactions - the payload redaction of a redaction config, optional so the existing implementations of
+// Redactions are still valid. The payloads are not redacted by the configs that do not implement it.
+type PayloadRedactions interface {
+	PayloadRedaction(payload, contentType string) (string, error)
+}
+
 // Config - the configuration of all redactions
 type Config struct {
-	Path              Path   `config:"path" yaml:"path"`
-	Args              Filter `config:"queryArgument" yaml:"queryArgument"`
-	RequestHeaders    Filter `config:"requestHeader" yaml:"requestHeader"`
-	ResponseHeaders   Filter `config:"responseHeader" yaml:"responseHeader"`
-	MaskingCharacters string `config:"maskingCharacters" yaml:"maskingCharacters"`
-	JMSProperties     Filter `config:"jmsProperties" yaml:"jmsProperties"`
+	Path              Path    `config:"path" yaml:"path"`
+	Args              Filter  `config:"queryArgument" yaml:"queryArgument"`
+	RequestHeaders    Filter  `config:"requestHeader" yaml:"requestHeader"`
+	ResponseHeaders   Filter  `config:"responseHeader" yaml:"responseHeader"`
+	MaskingCharacters string  `config:"maskingCharacters" yaml:"maskingCharacters"`
+	JMSProperties     Filter  `config:"jmsProperties" yaml:"jmsProperties"`
+	Payload           Payload `config:"payload" yaml:"payload"`
 }
 
 // path - the keyMatches to show, all else are redacted
@@ -66,6 +74,7 @@ type redactionRegex struct {
 	requestHeaderFilters  filterRegex
 	responseHeaderFilters filterRegex
 	jmsPropertiesFilters  filterRegex
+	payloadFilters        payloadRegex
 	sanitizeValue         string
 }
 
@@ -106,6 +115,10 @@ func DefaultConfig() Config {
 			Allowed:  []Show{},
 			Sanitize: []Sanitize{},
 		},
+		Payload: Payload{
+			Sanitize:     []PayloadSanitize{},
+			ContentTypes: []Show{},
+		},
 	}
 }
 
@@ -160,6 +173,12 @@ func (cfg *Config) SetupRedactions() (Redactions, error) {
 		return nil, err
 	}
 
+	// Setup the payload filters
+	redactionSetup.payloadFilters, err = setupPayloadRegex(cfg.Payload)
+	if err != nil {
+		return nil, err
+	}
+
 	isValidMask, err := validateMaskingChars(cfg.MaskingCharacters)
 	if err != nil {
 		err = ErrInvalidRegex.FormatError("validate masking characters", cfg.MaskingCharacters, err)
@@ -188,6 +207,11 @@ func validateMaskingChars(mask string) (bool, error) {
 
 // URIRedaction - takes a uri and returns the redacted version of that URI
 func (r *redactionRegex) URIRedaction(fullURI string) (string, error) {
+	return r.uriRedaction(fullURI, nil, nil)
+}
+
+// uriRedaction - redacts the uri, reporting the actions on the path segments and query arguments to the matched callbacks
+func (r *redactionRegex) uriRedaction(fullURI string, pathMatched, argsMatched filterMatched) (string, error) {
 	// skip redaction if nothing sent in
 	if fullURI == "" {
 		return "", nil
@@ -204,9 +228,9 @@ func (r *redactionRegex) URIRedaction(fullURI string) (string, error) {
 	}
 	switch parsedURL.Scheme {
 	case http, https, "":
-		parsedURL.Path = r.PathRedaction(parsedURL.Path)
+		parsedURL.Path = r.pathRedaction(parsedURL.Path, pathMatched)
 
-		parsedURL.RawQuery, err = r.QueryArgsRedactionString(parsedURL.RawQuery)
+		parsedURL.RawQuery, err = r.queryArgsRedactionString(parsedURL.RawQuery, argsMatched)
 		if err != nil {
 			return "", err
 		}
@@ -217,6 +241,11 @@ func (r *redactionRegex) URIRedaction(fullURI string) (string, error) {
 
 // PathRedaction - returns a string that has only allowed path elements
 func (r *redactionRegex) PathRedaction(path string) string {
+	return r.pathRedaction(path, nil)
+}
+
+// pathRedaction - redacts the path, the matched callback is given the index of the segments
+func (r *redactionRegex) pathRedaction(path string, matched filterMatched) string {
 	pathSegments := strings.Split(path, "/")
 
 	for i, segment := range pathSegments {
@@ -224,9 +253,13 @@ func (r *redactionRegex) PathRedaction(path string) string {
 			continue // skip blank segments
 		}
 		// If the value is not matched, sanitize it
-		if !isValidValueToShow(segment, r.pathFilters) {
+		show := firstShow(segment, r.pathFilters)
+		if show == nil {
 			pathSegments[i] = r.sanitizeValue
+			matched.sanitized(strconv.Itoa(i), nil)
+			continue
 		}
+		matched.shown(strconv.Itoa(i), show)
 	}
 
 	return strings.Join(pathSegments, "/")
@@ -234,29 +267,33 @@ func (r *redactionRegex) PathRedaction(path string) string {
 
 // QueryArgsRedaction - accepts a map[string][]string for arguments and returns the same map[string][]string with redacted
 func (r *redactionRegex) QueryArgsRedaction(args map[string][]string) (map[string][]string, error) {
+	return r.queryArgsRedaction(args, nil)
+}
+
+func (r *redactionRegex) queryArgsRedaction(args map[string][]string, matched filterMatched) (map[string][]string, error) {
 	queryArgs := url.Values{}
 
 	for argName, argValue := range args {
-		// First check for removals
-		removed := false
 		// If the name is not matched, remove it
-		if !isValidValueToShow(argName, r.argsFilters.show) {
-			removed = true
-		}
-
-		// Don't check for sanitization if arg was removed entirely
-		if removed {
+		show := firstShow(argName, r.argsFilters.show)
+		if show == nil {
+			matched.removed(argName)
 			continue
 		}
+		matched.shown(argName, show)
 
 		// Now check for sanitization
-		runSanitize, sanitizeRegex := shouldSanitize(argName, r.argsFilters.sanitize)
+		sanitize := firstSanitize(argName, r.argsFilters.sanitize)
 		for _, value := range argValue {
-			if runSanitize {
-				queryArgs.Add(argName, sanitizeRegex.ReplaceAllLiteralString(value, r.sanitizeValue))
-			} else {
+			if sanitize == nil {
 				queryArgs.Add(argName, value)
+				continue
+			}
+			sanitized := sanitize.valueMatch.ReplaceAllLiteralString(value, r.sanitizeValue)
+			if sanitized != value {
+				matched.sanitized(argName, sanitize)
 			}
+			queryArgs.Add(argName, sanitized)
 		}
 	}
 
@@ -265,13 +302,17 @@ func (r *redactionRegex) QueryArgsRedaction(args map[string][]string) (map[strin
 
 // QueryArgsRedactionString - accepts a string for arguments and returns the same string with redacted
 func (r *redactionRegex) QueryArgsRedactionString(args string) (string, error) {
+	return r.queryArgsRedactionString(args, nil)
+}
+
+func (r *redactionRegex) queryArgsRedactionString(args string, matched filterMatched) (string, error) {
 	if args == "" {
 		return "", nil // skip if there are no query args
 	}
 
 	queryArgs, _ := url.ParseQuery(args)
 
-	redactedArgs, err := r.QueryArgsRedaction(queryArgs)
+	redactedArgs, err := r.queryArgsRedaction(queryArgs, matched)
 	if err != nil {
 		return "", err
 	}
@@ -289,33 +330,40 @@ func (r *redactionRegex) QueryArgsRedactionString(args string) (string, error) {
 
 // RequestHeadersRedaction - accepts a map of response headers and returns the redacted and sanitize map
 func (r *redactionRegex) RequestHeadersRedaction(headers map[string]string) (map[string]string, error) {
-	return r.headersRedaction(headers, r.requestHeaderFilters)
+	return r.headersRedaction(headers, r.requestHeaderFilters, nil)
 }
 
 // ResponseHeadersRedaction - accepts a map of response headers and returns the redacted and sanitize map
 func (r *redactionRegex) ResponseHeadersRedaction(headers map[string]string) (map[string]string, error) {
-	return r.headersRedaction(headers, r.responseHeaderFilters)
+	return r.headersRedaction(headers, r.responseHeaderFilters, nil)
 }
 
 // JMSPropertiesRedaction - accepts a map of JMS properties and returns the redacted and sanitize map
 func (r *redactionRegex) JMSPropertiesRedaction(properties map[string]string) (map[string]string, error) {
-	return r.headersRedaction(properties, r.jmsPropertiesFilters)
+	return r.headersRedaction(properties, r.jmsPropertiesFilters, nil)
 }
 
-// headersRedaction - accepts a string of headers and the filters to apply then returns the redacted and sanitize map
-func (r *redactionRegex) headersRedaction(properties map[string]string, filters filterRegex) (map[string]string, error) {
+// headersRedaction - accepts a string of headers and the filters to apply then returns the redacted and sanitize map,
+// reporting the actions on the headers to the matched callback
+func (r *redactionRegex) headersRedaction(properties map[string]string, filters filterRegex, matched filterMatched) (map[string]string, error) {
 	newProperties := make(map[string]string)
 
 	for propName, propValue := range properties {
 		// If the name is not matched, remove it
-		if !isValidValueToShow(propName, filters.show) {
+		show := firstShow(propName, filters.show)
+		if show == nil {
+			matched.removed(propName)
 			continue
 		}
+		matched.shown(propName, show)
 
 		newProperties[propName] = propValue
 		// Now check for sanitization
-		if runSanitize, sanitizeRegex := shouldSanitize(propName, filters.sanitize); runSanitize {
-			newProperties[propName] = sanitizeRegex.ReplaceAllLiteralString(propValue, r.sanitizeValue)
+		if sanitize := firstSanitize(propName, filters.sanitize); sanitize != nil {
+			newProperties[propName] = sanitize.valueMatch.ReplaceAllLiteralString(propValue, r.sanitizeValue)
+			if newProperties[propName] != propValue {
+				matched.sanitized(propName, sanitize)
+			}
 		}
 	}
 
@@ -0,0 +1,226 @@
+package redaction
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// harFile - the parts of a HTTP archive that are redacted
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method      string         `json:"method"`
+				URL         string         `json:"url"`
+				Headers     []harNameValue `json:"headers"`
+				QueryString []harNameValue `json:"queryString"`
+				PostData    *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Headers []harNameValue `json:"headers"`
+				Content *struct {
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// capturedEvent - the parts of a transaction event, as sent by the agent, that are redacted
+type capturedEvent struct {
+	TransactionID    string `json:"transactionId"`
+	TransactionEvent *struct {
+		ID       string                 `json:"id"`
+		Protocol map[string]interface{} `json:"protocol"`
+	} `json:"transactionEvent"`
+}
+
+// ReadTransactions - reads the sample transactions of a HAR file, or of a file of transaction events captured before
+// they were redacted. The events may be a JSON array or one JSON object per line, the summary events are skipped.
+func ReadTransactions(path string) ([]Transaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrReadingSamples.FormatError(path, err)
+	}
+	transactions, err := parseTransactions(data)
+	if err != nil {
+		return nil, ErrReadingSamples.FormatError(path, err)
+	}
+	return transactions, nil
+}
+
+func parseTransactions(data []byte) ([]Transaction, error) {
+	transactions := []Transaction{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		objects := []json.RawMessage{value}
+		if strings.HasPrefix(strings.TrimSpace(string(value)), "[") {
+			objects = nil
+			if err := json.Unmarshal(value, &objects); err != nil {
+				return nil, err
+			}
+		}
+		for _, object := range objects {
+			parsed, err := parseTransactionObject(object)
+			if err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, parsed...)
+		}
+	}
+	return transactions, nil
+}
+
+func parseTransactionObject(object json.RawMessage) ([]Transaction, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["log"]; ok {
+		har := harFile{}
+		if err := json.Unmarshal(object, &har); err != nil {
+			return nil, err
+		}
+		return harTransactions(har), nil
+	}
+
+	event := capturedEvent{}
+	if err := json.Unmarshal(object, &event); err != nil {
+		return nil, err
+	}
+	if event.TransactionEvent == nil {
+		return []Transaction{}, nil
+	}
+	transaction, err := eventTransaction(event)
+	if err != nil {
+		return nil, err
+	}
+	return []Transaction{transaction}, nil
+}
+
+func harTransactions(har harFile) []Transaction {
+	transactions := make([]Transaction, 0, len(har.Log.Entries))
+	for _, entry := range har.Log.Entries {
+		transaction := Transaction{
+			Name:            entry.Request.Method + " " + entry.Request.URL,
+			URI:             entry.Request.URL,
+			RequestHeaders:  harHeaders(entry.Request.Headers),
+			ResponseHeaders: harHeaders(entry.Response.Headers),
+		}
+		if len(entry.Request.QueryString) > 0 {
+			transaction.QueryArgs = map[string][]string{}
+			for _, arg := range entry.Request.QueryString {
+				transaction.QueryArgs[arg.Name] = append(transaction.QueryArgs[arg.Name], arg.Value)
+			}
+		}
+		if entry.Request.PostData != nil {
+			transaction.RequestPayload = entry.Request.PostData.Text
+		}
+		if content := entry.Response.Content; content != nil {
+			transaction.ResponsePayload = content.Text
+			if content.Encoding == "base64" {
+				if decoded, err := base64.StdEncoding.DecodeString(content.Text); err == nil {
+					transaction.ResponsePayload = string(decoded)
+				}
+			}
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions
+}
+
+// harHeaders - the headers as a map, the values of repeated headers are joined
+func harHeaders(headers []harNameValue) map[string]string {
+	values := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if value, ok := values[header.Name]; ok {
+			values[header.Name] = value + ", " + header.Value
+			continue
+		}
+		values[header.Name] = header.Value
+	}
+	return values
+}
+
+func eventTransaction(event capturedEvent) (Transaction, error) {
+	transaction := Transaction{Name: event.TransactionID}
+	if event.TransactionEvent.ID != "" {
+		transaction.Name += "/" + event.TransactionEvent.ID
+	}
+
+	protocol := event.TransactionEvent.Protocol
+	if protocol["type"] == "jms" {
+		transaction.JMSProperties = map[string]string{}
+		for key, value := range protocol {
+			if key != "type" {
+				transaction.JMSProperties[key] = eventValue(value)
+			}
+		}
+		return transaction, nil
+	}
+
+	transaction.URI, _ = protocol["uri"].(string)
+	transaction.RequestPayload, _ = protocol["requestPayload"].(string)
+	transaction.ResponsePayload, _ = protocol["responsePayload"].(string)
+	// the args and headers are JSON strings in the events
+	if args, _ := protocol["args"].(string); args != "" {
+		if err := json.Unmarshal([]byte(args), &transaction.QueryArgs); err != nil {
+			return transaction, err
+		}
+	}
+	var err error
+	if transaction.RequestHeaders, err = eventHeaders(protocol["requestHeaders"]); err != nil {
+		return transaction, err
+	}
+	if transaction.ResponseHeaders, err = eventHeaders(protocol["responseHeaders"]); err != nil {
+		return transaction, err
+	}
+	return transaction, nil
+}
+
+func eventHeaders(value interface{}) (map[string]string, error) {
+	headers, _ := value.(string)
+	if headers == "" {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(headers), &parsed); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(parsed))
+	for name, value := range parsed {
+		values[name] = eventValue(value)
+	}
+	return values, nil
+}
+
+// eventValue - the string of a value of the event, without the exponent of the large numbers
+func eventValue(value interface{}) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,108 @@
+package redaction
+
+import (
+	"fmt"
+	"strings"
+)
+
+// xPath - a compiled XPath selecting the elements or attributes of an XML payload. The supported subset is child
+// (/name) and descendant (//name) steps, wildcards (*) and a last attribute step (@name or @*). Names are matched
+// without their namespace prefix.
+type xPath struct {
+	raw       string
+	steps     []xPathStep
+	attribute string
+}
+
+type xPathStep struct {
+	name       string
+	descendant bool
+}
+
+func compileXPath(path string) (*xPath, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("the path must start with /")
+	}
+	p := &xPath{raw: path, steps: []xPathStep{}}
+	rest := path
+	for rest != "" {
+		step := xPathStep{}
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			step.descendant = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "/"):
+			rest = rest[1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		end := strings.Index(rest, "/")
+		if end < 0 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		rest = rest[end:]
+		if name == "" {
+			return nil, fmt.Errorf("empty step in %q", path)
+		}
+		if strings.HasPrefix(name, "@") {
+			if rest != "" || len(name) == 1 {
+				return nil, fmt.Errorf("the attribute %q must be the last step", name)
+			}
+			p.attribute = localName(name[1:])
+			if step.descendant {
+				// //@id is any element's attribute
+				p.steps = append(p.steps, xPathStep{name: "*", descendant: true})
+			}
+			break
+		}
+		step.name = localName(name)
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// matchesElement - true when the path selects the element with the stack of element names, from the root
+func (p *xPath) matchesElement(elements []string) bool {
+	return p.attribute == "" && matchXPathSteps(p.steps, elements)
+}
+
+// matchesAttribute - true when the path selects the attribute of the element with the stack of element names
+func (p *xPath) matchesAttribute(elements []string, attribute string) bool {
+	if p.attribute == "" || (p.attribute != "*" && p.attribute != localName(attribute)) {
+		return false
+	}
+	return matchXPathSteps(p.steps, elements)
+}
+
+func matchXPathSteps(steps []xPathStep, elements []string) bool {
+	if len(steps) == 0 {
+		return len(elements) == 0
+	}
+	if len(elements) == 0 {
+		return false
+	}
+	step := steps[0]
+	if !step.descendant {
+		return step.matches(elements[0]) && matchXPathSteps(steps[1:], elements[1:])
+	}
+	for i := range elements {
+		if step.matches(elements[i]) && matchXPathSteps(steps[1:], elements[i+1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s xPathStep) matches(name string) bool {
+	return s.name == "*" || s.name == localName(name)
+}
+
+// localName - the name without its namespace prefix
+func localName(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,9 @@
+package spool
+
+import "github.com/Axway/agent-sdk/pkg/util/errors"
+
+// Spool errors
+var (
+	ErrSpoolDir   = errors.Newf(1530, "could not create the traceability spool directory %s: %v")
+	ErrSpoolWrite = errors.Newf(1531, "could not write the events to the traceability spool: %v")
+)
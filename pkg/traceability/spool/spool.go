@@ -0,0 +1,346 @@
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const (
+	defaultDirName = "spool"
+	defaultMaxSize = 100 * 1024 * 1024
+	defaultMaxAge  = 24 * time.Hour
+	batchExt       = ".json"
+	tmpExt         = ".tmp"
+)
+
+// Config - the disk spool buffering the events while the ingestion endpoint can not be reached
+type Config struct {
+	Enabled bool          `config:"enabled" yaml:"enabled"`
+	Path    string        `config:"path" yaml:"path"`
+	MaxSize int64         `config:"maxSize" yaml:"maxSize"`
+	MaxAge  time.Duration `config:"maxAge" yaml:"maxAge"`
+}
+
+// DefaultConfig - returns the default spool config, disabled
+func DefaultConfig() Config {
+	return Config{
+		Enabled: false,
+		MaxSize: defaultMaxSize,
+		MaxAge:  defaultMaxAge,
+	}
+}
+
+// Depth - the batches, events and bytes in the spool
+type Depth struct {
+	Batches int
+	Events  int
+	Bytes   int64
+}
+
+func (d Depth) String() string {
+	return fmt.Sprintf("%d events in %d batches (%d bytes)", d.Events, d.Batches, d.Bytes)
+}
+
+// PublishFunc - publishes the events of a spooled batch, the batch is kept in the spool when an error is returned
+type PublishFunc func(events []beat.Event) error
+
+// Spool - a queue of event batches on disk, bounded in size and age. Each batch is a file named by its sequence number
+// and event count, so the batches are replayed in the order they were spooled.
+type Spool struct {
+	mutex       sync.Mutex
+	replayMutex sync.Mutex
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	seq         uint64
+	logger      log.FieldLogger
+}
+
+type spooledEvent struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Meta      common.MapStr `json:"meta,omitempty"`
+	Fields    common.MapStr `json:"fields"`
+}
+
+type batchFile struct {
+	name    string
+	seq     uint64
+	events  int
+	size    int64
+	modTime time.Time
+}
+
+// New - creates the spool in the configured path, or in the spool directory of the data directory, continuing the
+// sequence of the batches already spooled there
+func New(cfg Config, dataDir string) (*Spool, error) {
+	dir := cfg.Path
+	if dir == "" {
+		dir = filepath.Join(dataDir, defaultDirName)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, ErrSpoolDir.FormatError(dir, err)
+	}
+
+	s := &Spool{
+		dir:     dir,
+		maxSize: cfg.MaxSize,
+		maxAge:  cfg.MaxAge,
+		logger:  log.NewFieldLogger().WithPackage("sdk.traceability.spool").WithComponent("spool").WithField("dir", dir),
+	}
+
+	files, err := s.batchFiles()
+	if err != nil {
+		return nil, ErrSpoolDir.FormatError(dir, err)
+	}
+	if len(files) > 0 {
+		s.seq = files[len(files)-1].seq
+		s.logger.WithField("depth", s.depth(files).String()).Info("found spooled events to replay")
+	}
+	return s, nil
+}
+
+// Dir - the directory of the spool
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Append - writes the events to the spool as a new batch, dropping the oldest batches when the spool is over its size
+func (s *Spool) Append(events []beat.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	spooled := make([]spooledEvent, len(events))
+	for i, event := range events {
+		spooled[i] = spooledEvent{Timestamp: event.Timestamp, Meta: event.Meta, Fields: event.Fields}
+	}
+	data, err := json.Marshal(spooled)
+	if err != nil {
+		return ErrSpoolWrite.FormatError(err)
+	}
+
+	// write to a temporary file first so a partially written batch is never replayed
+	s.seq++
+	name := fmt.Sprintf("%020d-%d%s", s.seq, len(events), batchExt)
+	tmp := filepath.Join(s.dir, name+tmpExt)
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return ErrSpoolWrite.FormatError(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		os.Remove(tmp)
+		return ErrSpoolWrite.FormatError(err)
+	}
+	s.logger.WithField("events", len(events)).Debug("spooled events")
+
+	s.enforceBounds()
+	return nil
+}
+
+// Replay - publishes the spooled batches in order, removing each batch once published. The replay stops at the first
+// batch that could not be published, which is kept with the batches after it. The spool is not locked while a batch is
+// published, so the events can be appended and the depth read during a replay.
+func (s *Spool) Replay(publish PublishFunc) error {
+	s.replayMutex.Lock()
+	defer s.replayMutex.Unlock()
+
+	s.mutex.Lock()
+	files, err := s.batchFiles()
+	if err == nil {
+		files = s.dropExpired(files)
+	}
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		events, err := s.readReplayBatch(file)
+		if err != nil {
+			return err
+		}
+		if events == nil {
+			continue
+		}
+		if err := publish(events); err != nil {
+			return err
+		}
+		s.mutex.Lock()
+		s.remove(file)
+		s.mutex.Unlock()
+		s.logger.WithField("events", file.events).Debug("replayed spooled events")
+	}
+	return nil
+}
+
+// readReplayBatch - reads a batch to replay, nil when the batch was dropped since the batches were listed or could
+// not be read
+func (s *Spool) readReplayBatch(file batchFile) ([]beat.Event, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	events, err := s.readBatch(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("batch", file.name).Error("dropping spooled batch that could not be read")
+		s.remove(file)
+		return nil, nil
+	}
+	return events, nil
+}
+
+// IsEmpty - true when there are no batches in the spool
+func (s *Spool) IsEmpty() bool {
+	return s.Depth().Batches == 0
+}
+
+// Depth - the batches, events and bytes in the spool
+func (s *Spool) Depth() Depth {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	files, err := s.batchFiles()
+	if err != nil {
+		return Depth{}
+	}
+	return s.depth(files)
+}
+
+func (s *Spool) depth(files []batchFile) Depth {
+	d := Depth{Batches: len(files)}
+	for _, file := range files {
+		d.Events += file.events
+		d.Bytes += file.size
+	}
+	return d
+}
+
+// enforceBounds - drops the batches older than the max age, then the oldest batches until the spool is within its size
+func (s *Spool) enforceBounds() {
+	files, err := s.batchFiles()
+	if err != nil {
+		s.logger.WithError(err).Error("could not read the spool directory")
+		return
+	}
+	files = s.dropExpired(files)
+	if s.maxSize <= 0 {
+		return
+	}
+
+	size := s.depth(files).Bytes
+	dropped := 0
+	// the newest batch is always kept
+	for i := 0; size > s.maxSize && i < len(files)-1; i++ {
+		size -= files[i].size
+		dropped += files[i].events
+		s.remove(files[i])
+	}
+	if dropped > 0 {
+		s.logger.WithField("events", dropped).Warn("the spool is full, dropped the oldest spooled events")
+	}
+}
+
+// dropExpired - removes the batches older than the max age, returning the batches kept
+func (s *Spool) dropExpired(files []batchFile) []batchFile {
+	if s.maxAge <= 0 {
+		return files
+	}
+	kept := make([]batchFile, 0, len(files))
+	dropped := 0
+	for _, file := range files {
+		if time.Since(file.modTime) > s.maxAge {
+			dropped += file.events
+			s.remove(file)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	if dropped > 0 {
+		s.logger.WithField("events", dropped).Warn("dropped spooled events older than the max age")
+	}
+	return kept
+}
+
+// batchFiles - the batches in the spool, in the order they were spooled
+func (s *Spool) batchFiles() ([]batchFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]batchFile, 0, len(entries))
+	for _, entry := range entries {
+		file, ok := parseBatchName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		file.size = info.Size()
+		file.modTime = info.ModTime()
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].seq < files[j].seq
+	})
+	return files, nil
+}
+
+func (s *Spool) readBatch(file batchFile) ([]beat.Event, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, file.name))
+	if err != nil {
+		return nil, err
+	}
+	spooled := []spooledEvent{}
+	if err := json.Unmarshal(data, &spooled); err != nil {
+		return nil, err
+	}
+
+	events := make([]beat.Event, len(spooled))
+	for i, event := range spooled {
+		events[i] = beat.Event{Timestamp: event.Timestamp, Meta: event.Meta, Fields: event.Fields}
+	}
+	return events, nil
+}
+
+func (s *Spool) remove(file batchFile) {
+	if err := os.Remove(filepath.Join(s.dir, file.name)); err != nil && !os.IsNotExist(err) {
+		s.logger.WithError(err).WithField("batch", file.name).Error("could not remove spooled batch")
+	}
+}
+
+// parseBatchName - the sequence number and event count of a batch file named <seq>-<events>.json
+func parseBatchName(name string) (batchFile, bool) {
+	if !strings.HasSuffix(name, batchExt) {
+		return batchFile{}, false
+	}
+	parts := strings.Split(strings.TrimSuffix(name, batchExt), "-")
+	if len(parts) != 2 {
+		return batchFile{}, false
+	}
+	seq, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return batchFile{}, false
+	}
+	events, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return batchFile{}, false
+	}
+	return batchFile{name: name, seq: seq, events: events}, true
+}
@@ -0,0 +1,139 @@
+package spool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func createEvents(messages ...string) []beat.Event {
+	events := make([]beat.Event, len(messages))
+	for i, msg := range messages {
+		events[i] = beat.Event{
+			Timestamp: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			Meta:      common.MapStr{"sample": true},
+			Fields:    common.MapStr{"message": msg},
+		}
+	}
+	return events
+}
+
+func replayed(t *testing.T, s *Spool) []string {
+	messages := []string{}
+	err := s.Replay(func(events []beat.Event) error {
+		for _, event := range events {
+			messages = append(messages, event.Fields["message"].(string))
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	return messages
+}
+
+func TestSpoolAppendReplay(t *testing.T) {
+	s, err := New(DefaultConfig(), t.TempDir())
+	assert.Nil(t, err)
+	assert.True(t, s.IsEmpty())
+
+	assert.Nil(t, s.Append(createEvents("one", "two")))
+	assert.Nil(t, s.Append(createEvents("three")))
+	assert.Nil(t, s.Append(nil))
+	depth := s.Depth()
+	assert.Equal(t, 2, depth.Batches)
+	assert.Equal(t, 3, depth.Events)
+	assert.NotZero(t, depth.Bytes)
+
+	assert.Equal(t, []string{"one", "two", "three"}, replayed(t, s))
+	assert.True(t, s.IsEmpty())
+}
+
+func TestSpoolReplayStopsOnError(t *testing.T) {
+	s, err := New(DefaultConfig(), t.TempDir())
+	assert.Nil(t, err)
+	assert.Nil(t, s.Append(createEvents("one")))
+	assert.Nil(t, s.Append(createEvents("two")))
+	assert.Nil(t, s.Append(createEvents("three")))
+
+	published := 0
+	err = s.Replay(func(events []beat.Event) error {
+		if events[0].Fields["message"] == "two" {
+			return fmt.Errorf("endpoint down")
+		}
+		published++
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"two", "three"}, replayed(t, s))
+}
+
+func TestSpoolAppendDuringReplay(t *testing.T) {
+	s, err := New(DefaultConfig(), t.TempDir())
+	assert.Nil(t, err)
+	assert.Nil(t, s.Append(createEvents("one")))
+
+	// the depth is read and the events appended while a batch is published
+	err = s.Replay(func(events []beat.Event) error {
+		assert.Equal(t, 1, s.Depth().Batches)
+		return s.Append(createEvents("two"))
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"two"}, replayed(t, s))
+}
+
+func TestSpoolContinuesSequence(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Path = filepath.Join(dir, "events")
+
+	s, err := New(cfg, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg.Path, s.Dir())
+	assert.Nil(t, s.Append(createEvents("one")))
+	assert.Nil(t, s.Append(createEvents("two")))
+
+	// a batch left partially written is ignored
+	assert.Nil(t, os.WriteFile(filepath.Join(cfg.Path, "00000000000000000003-1.json.tmp"), []byte("[{"), 0600))
+
+	restarted, err := New(cfg, dir)
+	assert.Nil(t, err)
+	assert.Nil(t, restarted.Append(createEvents("three")))
+	assert.Equal(t, []string{"one", "two", "three"}, replayed(t, restarted))
+}
+
+func TestSpoolBounds(t *testing.T) {
+	t.Run("drops the oldest batches over the max size", func(t *testing.T) {
+		cfg := DefaultConfig()
+		s, err := New(cfg, t.TempDir())
+		assert.Nil(t, err)
+		assert.Nil(t, s.Append(createEvents("one")))
+		// room for two batches of the same size
+		s.maxSize = s.Depth().Bytes * 2
+
+		assert.Nil(t, s.Append(createEvents("two")))
+		assert.Nil(t, s.Append(createEvents("six")))
+		assert.Equal(t, 2, s.Depth().Batches)
+		assert.Equal(t, []string{"two", "six"}, replayed(t, s))
+	})
+
+	t.Run("drops the batches older than the max age", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.MaxAge = time.Hour
+		s, err := New(cfg, t.TempDir())
+		assert.Nil(t, err)
+		assert.Nil(t, s.Append(createEvents("one")))
+		files, err := s.batchFiles()
+		assert.Nil(t, err)
+		old := time.Now().Add(-2 * time.Hour)
+		assert.Nil(t, os.Chtimes(filepath.Join(s.Dir(), files[0].name), old, old))
+
+		assert.Nil(t, s.Append(createEvents("two")))
+		assert.Equal(t, []string{"two"}, replayed(t, s))
+	})
+}
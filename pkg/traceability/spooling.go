@@ -0,0 +1,119 @@
+package traceability
+
+import (
+	"context"
+
+	"github.com/Axway/agent-sdk/pkg/traceability/spool"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/outputs"
+	"github.com/elastic/beats/v7/libbeat/publisher"
+)
+
+// spoolBatch - a batch handed to the transport client capturing its signals, so the events that were not published
+// can be spooled instead of retried in memory
+type spoolBatch struct {
+	events  []publisher.Event
+	done    bool
+	pending []publisher.Event
+}
+
+func newSpoolBatch(events []publisher.Event) *spoolBatch {
+	return &spoolBatch{events: events}
+}
+
+func (b *spoolBatch) Events() []publisher.Event { return b.events }
+func (b *spoolBatch) ACK()                      { b.done = true }
+func (b *spoolBatch) Drop()                     { b.done = true }
+func (b *spoolBatch) Retry()                    { b.pending = b.events }
+func (b *spoolBatch) Cancelled()                { b.pending = b.events }
+
+func (b *spoolBatch) RetryEvents(events []publisher.Event)     { b.pending = events }
+func (b *spoolBatch) CancelledEvents(events []publisher.Event) { b.pending = events }
+
+// unpublished - the events the transport client did not publish
+func (b *spoolBatch) unpublished() []publisher.Event {
+	if b.pending != nil {
+		return b.pending
+	}
+	if b.done {
+		return nil
+	}
+	return b.events
+}
+
+// SetSpool - set the disk spool buffering the events that could not be published
+func (client *Client) SetSpool(s *spool.Spool) {
+	client.Lock()
+	defer client.Unlock()
+	client.spool = s
+}
+
+func (client *Client) getSpool() *spool.Spool {
+	client.Lock()
+	defer client.Unlock()
+	return client.spool
+}
+
+// publishWithSpool - replays the spooled events, then publishes the batch. When the ingestion endpoint can not be
+// reached, the events are written to the spool and the batch is acknowledged, so they are not lost on restart.
+func (client *Client) publishWithSpool(ctx context.Context, batch publisher.Batch, eventSpool *spool.Spool, logger log.FieldLogger) error {
+	transportClient := client.getTransportClient()
+
+	// the spooled events are sent before the batch to keep the order of the events
+	err := eventSpool.Replay(func(events []beat.Event) error {
+		return publishSpooled(ctx, transportClient, events)
+	})
+
+	var unpublished []publisher.Event
+	if err == nil {
+		spooled := newSpoolBatch(batch.Events())
+		err = transportClient.Publish(ctx, spooled)
+		unpublished = spooled.unpublished()
+	} else {
+		unpublished = batch.Events()
+	}
+
+	if len(unpublished) == 0 {
+		batch.ACK()
+		return nil
+	}
+
+	logger = logger.WithField("spool", eventSpool.Dir())
+	if spoolErr := eventSpool.Append(toBeatEvents(unpublished)); spoolErr != nil {
+		logger.WithError(spoolErr).Error("failed to spool events")
+		batch.RetryEvents(unpublished)
+		if err == nil {
+			err = spoolErr
+		}
+		return err
+	}
+
+	logger.WithError(err).WithField(countStr, len(unpublished)).Warn("could not publish events, spooled them to replay")
+	batch.ACK()
+	return nil
+}
+
+// publishSpooled - publishes the events read from the spool with the transport client
+func publishSpooled(ctx context.Context, transportClient outputs.Client, events []beat.Event) error {
+	spooled := make([]publisher.Event, len(events))
+	for i, event := range events {
+		spooled[i] = publisher.Event{Content: event}
+	}
+	batch := newSpoolBatch(spooled)
+	if err := transportClient.Publish(ctx, batch); err != nil {
+		return err
+	}
+	if len(batch.unpublished()) > 0 {
+		return ErrSpoolReplay
+	}
+	return nil
+}
+
+func toBeatEvents(events []publisher.Event) []beat.Event {
+	beatEvents := make([]beat.Event, len(events))
+	for i, event := range events {
+		beatEvents[i] = event.Content
+	}
+	return beatEvents
+}
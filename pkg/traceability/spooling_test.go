@@ -0,0 +1,111 @@
+package traceability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Axway/agent-sdk/pkg/agent"
+	"github.com/Axway/agent-sdk/pkg/apic"
+	"github.com/Axway/agent-sdk/pkg/traceability/spool"
+	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/elastic/beats/v7/libbeat/publisher"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTransportClient struct {
+	mockTransportClient
+	fail      bool
+	published []string
+}
+
+func (m *recordingTransportClient) Publish(_ context.Context, batch publisher.Batch) error {
+	if m.fail {
+		batch.RetryEvents(batch.Events())
+		return fmt.Errorf("endpoint down")
+	}
+	for _, event := range batch.Events() {
+		m.published = append(m.published, event.Content.Fields["message"].(string))
+	}
+	batch.ACK()
+	return nil
+}
+
+func newSpoolClient(t *testing.T) (*Client, *recordingTransportClient, *spool.Spool) {
+	eventSpool, err := spool.New(spool.DefaultConfig(), t.TempDir())
+	assert.Nil(t, err)
+	transport := &recordingTransportClient{}
+	client := &Client{transportClient: transport, logger: log.NewFieldLogger()}
+	client.SetSpool(eventSpool)
+	return client, transport, eventSpool
+}
+
+func TestPublishWithSpool(t *testing.T) {
+	agent.InitializeForTest(&apic.ServiceClient{})
+	client, transport, eventSpool := newSpoolClient(t)
+
+	// the endpoint is down, the events are spooled and the batches acknowledged
+	transport.fail = true
+	for _, msg := range []string{"one", "two"} {
+		batch := createBatch(msg)
+		err := client.Publish(context.Background(), batch)
+		assert.Nil(t, err)
+		assert.True(t, batch.acked)
+		assert.Equal(t, 0, batch.retryCount)
+	}
+	assert.Equal(t, 2, eventSpool.Depth().Events)
+	assert.Empty(t, transport.published)
+
+	// the endpoint is back, the spooled events are replayed before the batch
+	transport.fail = false
+	batch := createBatch("three")
+	err := client.Publish(context.Background(), batch)
+	assert.Nil(t, err)
+	assert.True(t, batch.acked)
+	assert.Equal(t, []string{"one", "two", "three"}, transport.published)
+	assert.True(t, eventSpool.IsEmpty())
+}
+
+func TestPublishWithSpoolError(t *testing.T) {
+	agent.InitializeForTest(&apic.ServiceClient{})
+	client, transport, eventSpool := newSpoolClient(t)
+	transport.fail = true
+
+	// the spool can not be written, the events are retried by the pipeline
+	assert.Nil(t, os.RemoveAll(eventSpool.Dir()))
+	batch := createBatch("one")
+	err := client.Publish(context.Background(), batch)
+	assert.NotNil(t, err)
+	assert.False(t, batch.acked)
+	assert.Equal(t, 1, batch.retryCount)
+}
+
+func TestHealthCheckSpoolDepth(t *testing.T) {
+	agent.InitializeForTest(&apic.ServiceClient{})
+	client, _, eventSpool := newSpoolClient(t)
+	assert.Nil(t, eventSpool.Append(toBeatEvents(createEvent("one"))))
+
+	clientMutex.Lock()
+	prevClients := traceabilityClients
+	traceabilityClients = []*Client{client}
+	clientMutex.Unlock()
+	defer func() {
+		clientMutex.Lock()
+		traceabilityClients = prevClients
+		clientMutex.Unlock()
+	}()
+
+	job := newTraceabilityHealthCheckJob()
+	job.ready = true
+	status := job.healthcheck("name")
+	assert.Equal(t, hc.OK, status.Result)
+	assert.True(t, strings.HasPrefix(status.Details, "spooled 1 events in 1 batches"))
+
+	job.prevErr = fmt.Errorf("error")
+	status = job.healthcheck("name")
+	assert.Equal(t, hc.FAIL, status.Result)
+	assert.True(t, strings.HasPrefix(status.Details, "connection error: name Failed. error. spooled 1 events"))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/jobs"
 	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
+	"github.com/Axway/agent-sdk/pkg/traceability/spool"
 	"github.com/Axway/agent-sdk/pkg/util"
 	"github.com/Axway/agent-sdk/pkg/util/log"
 	"github.com/elastic/beats/v7/libbeat/beat"
@@ -82,6 +83,7 @@ func getClient() (*Client, error) {
 type Client struct {
 	sync.Mutex
 	transportClient outputs.Client
+	spool           *spool.Spool
 	logger          log.FieldLogger
 }
 
@@ -235,9 +237,20 @@ func makeTraceabilityAgent(
 	}
 	clients := make([]outputs.Client, 0)
 
+	// the clients share the spool, so the spooled events are replayed by the first client to publish
+	var eventSpool *spool.Spool
+	if traceCfg.Spool.Enabled {
+		eventSpool, err = spool.New(traceCfg.Spool, GetDataDirPath())
+		if err != nil {
+			logger.WithError(err).Error("creating traceability spool, events will not be spooled")
+			err = nil
+		}
+	}
+
 	for _, client := range transportGroup.Clients {
 		outputClient := &Client{
 			transportClient: client,
+			spool:           eventSpool,
 			logger:          logger.WithComponent("traceabilityClient").WithPackage("sdk.traceability"),
 		}
 		clients = append(clients, outputClient)
@@ -441,6 +454,10 @@ func (client *Client) Publish(ctx context.Context, batch publisher.Batch) error
 	logger = logger.WithField(countStr, len(events))
 	logger.Info("publishing events")
 
+	if eventSpool := client.getSpool(); eventSpool != nil {
+		return client.publishWithSpool(ctx, batch, eventSpool, logger)
+	}
+
 	err := client.getTransportClient().Publish(ctx, batch)
 	if err != nil {
 		logger.WithError(err).Error("failed to publish events")
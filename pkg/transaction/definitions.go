@@ -56,20 +56,21 @@ const (
 
 // LogEvent - Log event to be sent to Condor
 type LogEvent struct {
-	Version            string   `json:"version"`
-	Stamp              int64    `json:"timestamp"`
-	TransactionID      string   `json:"transactionId"`
-	Environment        string   `json:"environment,omitempty"`
-	APICDeployment     string   `json:"apicDeployment,omitempty"`
-	EnvironmentName    string   `json:"environmentName,omitempty"`
-	EnvironmentID      string   `json:"environmentId"`
-	TenantID           string   `json:"tenantId"`
-	TrcbltPartitionID  string   `json:"trcbltPartitionId"`
-	Type               string   `json:"type"`
-	TargetPath         string   `json:"targetPath,omitempty"`
-	ResourcePath       string   `json:"resourcePath,omitempty"`
-	TransactionEvent   *Event   `json:"transactionEvent,omitempty"`
-	TransactionSummary *Summary `json:"transactionSummary,omitempty"`
+	Version            string        `json:"version"`
+	Stamp              int64         `json:"timestamp"`
+	TransactionID      string        `json:"transactionId"`
+	Environment        string        `json:"environment,omitempty"`
+	APICDeployment     string        `json:"apicDeployment,omitempty"`
+	EnvironmentName    string        `json:"environmentName,omitempty"`
+	EnvironmentID      string        `json:"environmentId"`
+	TenantID           string        `json:"tenantId"`
+	TrcbltPartitionID  string        `json:"trcbltPartitionId"`
+	Type               string        `json:"type"`
+	TargetPath         string        `json:"targetPath,omitempty"`
+	ResourcePath       string        `json:"resourcePath,omitempty"`
+	TransactionEvent   *Event        `json:"transactionEvent,omitempty"`
+	TransactionSummary *Summary      `json:"transactionSummary,omitempty"`
+	TraceContext       *TraceContext `json:"traceContext,omitempty"`
 }
 
 // Summary - Represent the transaction summary event
@@ -176,4 +177,10 @@ type Protocol struct {
 	WafStatus              int    `json:"wafStatus,omitempty"`
 	Timing                 string `json:"timing,omitempty"`
 	uriRaw                 string
+	traceContext           *TraceContext
+}
+
+// GetTraceContext - the trace context parsed from the request headers, before they were redacted
+func (p *Protocol) GetTraceContext() *TraceContext {
+	return p.traceContext
 }
@@ -17,6 +17,7 @@ import (
 	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1"
 	"github.com/Axway/agent-sdk/pkg/cmd"
 	"github.com/Axway/agent-sdk/pkg/traceability"
+	"github.com/Axway/agent-sdk/pkg/traceability/otlp"
 	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
 	"github.com/Axway/agent-sdk/pkg/transaction/metric"
 	"github.com/Axway/agent-sdk/pkg/transaction/models"
@@ -115,7 +116,8 @@ func (e *Generator) CreateFromEventReport(eventReport EventReport) ([]beat.Event
 
 	//if no summary is sent then prepare the array of TransactionEvents for publishing
 	if eventReport.GetSummaryEvent() == (LogEvent{}) {
-		return e.handleTransactionEvents(eventReport.GetDetailEvents(), nil, eventReport.GetEventTime(), metadata, eventReport.GetFields(), eventReport.GetPrivateData())
+		detailEvents, err := e.handleTransactionEvents(eventReport.GetDetailEvents(), nil, eventReport.GetEventTime(), metadata, eventReport.GetFields(), eventReport.GetPrivateData())
+		return e.outputEvents(detailEvents), err
 	}
 
 	// Check to see if marketplace provisioning/subs is enabled
@@ -125,7 +127,8 @@ func (e *Generator) CreateFromEventReport(eventReport EventReport) ([]beat.Event
 		return events, err
 	}
 
-	if eventReport.ShouldHandleSampling() && !eventReport.ShouldForceSample() {
+	// the transactions only exported as spans are not sampled, so each of them is exported
+	if eventReport.ShouldHandleSampling() && !eventReport.ShouldForceSample() && !isExportOnly() {
 		shouldSample, err := sampling.ShouldSampleTransaction(e.createSamplingTransactionDetails(eventReport.GetSummaryEvent()))
 		if err != nil || !shouldSample {
 			// do not need to create the event structure if it will not be sampled
@@ -151,7 +154,35 @@ func (e *Generator) CreateFromEventReport(eventReport EventReport) ([]beat.Event
 	}
 
 	events = append(events, newEvent)
-	return append(events, detailEvents...), nil
+	return e.outputEvents(append(events, detailEvents...)), nil
+}
+
+// outputEvents - the events sent to Amplify, none when the transactions are only exported as OTLP spans
+func (e *Generator) outputEvents(events []beat.Event) []beat.Event {
+	if isExportOnly() && events != nil {
+		return []beat.Event{}
+	}
+	return events
+}
+
+// isExportOnly - true when the transactions are only exported as OTLP spans, and not sent to Amplify
+func isExportOnly() bool {
+	exporter := otlp.GetGlobalExporter()
+	return exporter != nil && exporter.ExportOnly()
+}
+
+// exportSpan - queues the span of the insights event for the OTLP export, when enabled
+func (e *Generator) exportSpan(logEvent LogEvent, insightsEvent *InsightsEvent) {
+	exporter := otlp.GetGlobalExporter()
+	if exporter == nil {
+		return
+	}
+	span, err := NewOTLPSpan(logEvent, insightsEvent)
+	if err != nil {
+		e.logger.WithError(err).Debug("could not build the otlp span of the transaction event")
+		return
+	}
+	exporter.Export(span)
 }
 
 func (e *Generator) AddMetricDetailsFromEventReport(eventReport EventReport) error {
@@ -270,6 +301,8 @@ func (e *Generator) createEvent(logEvent LogEvent, summaryProxy *Proxy, eventTim
 		WithField("envelopeVersion", insightsEvent.Version).
 		Info("insights transaction event built")
 
+	e.exportSpan(logEvent, insightsEvent)
+
 	serialized, err := json.Marshal(insightsEvent)
 	if err != nil {
 		return event, err
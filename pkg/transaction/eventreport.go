@@ -41,6 +41,7 @@ type eventReport struct {
 	forceSample      bool
 	skipTracking     bool
 	trackOnly        bool
+	traceCorrelation bool
 }
 
 func (e *eventReport) GetSummaryEvent() LogEvent {
@@ -123,6 +124,7 @@ type EventReportBuilder interface {
 	SetForceSample() EventReportBuilder
 	SetSkipMetricTracking() EventReportBuilder
 	SetOnlyTrackMetrics(trackOnly bool) EventReportBuilder
+	SetTraceContextCorrelation() EventReportBuilder
 	Build() (EventReport, error)
 }
 
@@ -220,6 +222,12 @@ func (e *eventReport) SetOnlyTrackMetrics(trackOnly bool) EventReportBuilder {
 	return e
 }
 
+// SetTraceContextCorrelation - derives the missing transaction ids and leg parents from the trace context of the events
+func (e *eventReport) SetTraceContextCorrelation() EventReportBuilder {
+	e.traceCorrelation = true
+	return e
+}
+
 func (e *eventReport) Build() (EventReport, error) {
 	if e.skipTracking && e.trackOnly {
 		return nil, errors.New("can't set skip tracking and track only in a single event")
@@ -247,5 +255,90 @@ func (e *eventReport) Build() (EventReport, error) {
 		}
 	}
 
+	e.setSummaryTraceContext()
+	if e.traceCorrelation {
+		e.correlateTraceContext()
+	}
+
 	return e, nil
 }
+
+// rootTraceContext - the trace context of the transaction, from the summary or else the legs, the first leg without
+// a parent leg first
+func (e *eventReport) rootTraceContext() *TraceContext {
+	if e.summaryEvent != nil && e.summaryEvent.TraceContext != nil {
+		return e.summaryEvent.TraceContext
+	}
+	spans := map[string]bool{}
+	for _, detail := range e.detailEvents {
+		if detail.TraceContext != nil && detail.TraceContext.SpanID != "" {
+			spans[detail.TraceContext.SpanID] = true
+		}
+	}
+
+	var root *TraceContext
+	for _, detail := range e.detailEvents {
+		if detail.TraceContext == nil {
+			continue
+		}
+		if detail.TransactionEvent != nil && detail.TransactionEvent.ParentID == "" && !spans[detail.TraceContext.ParentSpanID] {
+			return detail.TraceContext
+		}
+		if root == nil {
+			root = detail.TraceContext
+		}
+	}
+	return root
+}
+
+// setSummaryTraceContext - the summary is part of the trace of its legs
+func (e *eventReport) setSummaryTraceContext() {
+	if e.summaryEvent == nil || e.summaryEvent.TraceContext != nil {
+		return
+	}
+	if root := e.rootTraceContext(); root != nil {
+		traceContext := *root
+		e.summaryEvent.TraceContext = &traceContext
+	}
+}
+
+// correlateTraceContext - sets the transaction id of the events without one to the trace id, and the parent of the
+// legs without one to the leg whose span is the parent span of their trace context
+func (e *eventReport) correlateTraceContext() {
+	root := e.rootTraceContext()
+	if root == nil {
+		return
+	}
+
+	setTransactionID := func(logEvent *LogEvent) {
+		if logEvent.TransactionID != "" {
+			return
+		}
+		logEvent.TransactionID = root.TraceID
+		if logEvent.TraceContext != nil {
+			logEvent.TransactionID = logEvent.TraceContext.TraceID
+		}
+	}
+	if e.summaryEvent != nil {
+		setTransactionID(e.summaryEvent)
+	}
+
+	legsBySpan := map[string]string{}
+	for i := range e.detailEvents {
+		detail := &e.detailEvents[i]
+		setTransactionID(detail)
+		if detail.TraceContext != nil && detail.TransactionEvent != nil && detail.TraceContext.SpanID != "" {
+			legsBySpan[detail.TraceContext.SpanID] = detail.TransactionEvent.ID
+		}
+	}
+
+	for i := range e.detailEvents {
+		detail := &e.detailEvents[i]
+		if detail.TraceContext == nil || detail.TransactionEvent == nil || detail.TransactionEvent.ParentID != "" {
+			continue
+		}
+		if parentID, ok := legsBySpan[detail.TraceContext.ParentSpanID]; ok && parentID != detail.TransactionEvent.ID {
+			detail.TransactionEvent.ParentID = parentID
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Axway/agent-sdk/pkg/traceability/redaction"
 )
@@ -351,11 +352,19 @@ func (b *httpProtocolBuilder) Build() (TransportProtocol, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
+	// Parse the trace context before the redaction may remove its headers
+	b.httpProtocol.traceContext = ParseTraceContext(b.requestHeaders)
+
 	// Complete the redactions
 	b.queryArgsRedaction()
 	if b.err != nil {
 		return nil, b.err
 	}
+	// the payloads are redacted first, the content type headers may be redacted
+	b.payloadRedaction()
+	if b.err != nil {
+		return nil, b.err
+	}
 	b.headersRedaction()
 	if b.err != nil {
 		return nil, b.err
@@ -420,6 +429,48 @@ func (b *httpProtocolBuilder) queryArgsRedaction() {
 	}
 }
 
+func (b *httpProtocolBuilder) payloadRedaction() {
+	// skip if there is already an error
+	if b.err != nil {
+		return
+	}
+
+	b.httpProtocol.RequestPayload, b.err = payloadRedaction(b.httpProtocol.RequestPayload, contentType(b.requestHeaders), b.redactionConfig)
+	if b.err != nil {
+		b.err = ErrInRedactions.FormatError("RequestPayload", b.err)
+		return
+	}
+	b.httpProtocol.ResponsePayload, b.err = payloadRedaction(b.httpProtocol.ResponsePayload, contentType(b.responseHeaders), b.redactionConfig)
+	if b.err != nil {
+		b.err = ErrInRedactions.FormatError("ResponsePayload", b.err)
+	}
+}
+
+func payloadRedaction(payload, contentType string, redactionConfig redaction.Redactions) (string, error) {
+	if payload == "" {
+		return payload, nil
+	}
+	if redactionConfig == nil {
+		return redaction.PayloadRedaction(payload, contentType)
+	}
+	// the payloads are kept when the redaction config has no payload redaction
+	payloadRedactions, ok := redactionConfig.(redaction.PayloadRedactions)
+	if !ok {
+		return payload, nil
+	}
+	return payloadRedactions.PayloadRedaction(payload, contentType)
+}
+
+// contentType - the value of the content type header
+func contentType(headers map[string]string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			return value
+		}
+	}
+	return ""
+}
+
 func (b *httpProtocolBuilder) headersRedaction() {
 	// skip if there is already an error
 	if b.err != nil {
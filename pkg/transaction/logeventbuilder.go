@@ -55,6 +55,7 @@ type EventBuilder interface {
 	SetRedactionConfig(config redaction.Redactions) EventBuilder
 	SetProxy(proxyID, proxyName string) EventBuilder
 	SetProxyWithStage(proxyID, proxyName, proxyStage string) EventBuilder
+	SetTraceContext(traceContext *TraceContext) EventBuilder
 
 	Build() (*LogEvent, error)
 }
@@ -91,6 +92,7 @@ type SummaryBuilder interface {
 	SetEntryPoint(entryPointType, method, path, host string) SummaryBuilder
 	SetIsInMetricEvent(isInMetricEvent bool) SummaryBuilder
 	SetRedactionConfig(config redaction.Redactions) SummaryBuilder
+	SetTraceContext(traceContext *TraceContext) SummaryBuilder
 	Build() (*LogEvent, error)
 }
 
@@ -322,11 +324,25 @@ func (b *transactionEventBuilder) SetRedactionConfig(config redaction.Redactions
 	return b
 }
 
+// SetTraceContext - sets the trace context of the event, when not set it is taken from the HTTP protocol details
+func (b *transactionEventBuilder) SetTraceContext(traceContext *TraceContext) EventBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.logEvent.TraceContext = traceContext
+	return b
+}
+
 func (b *transactionEventBuilder) Build() (*LogEvent, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
 
+	// Set the trace context parsed from the request headers
+	if httpProtocol, ok := b.logEvent.TransactionEvent.Protocol.(*Protocol); ok && httpProtocol != nil && b.logEvent.TraceContext == nil {
+		b.logEvent.TraceContext = httpProtocol.GetTraceContext()
+	}
+
 	// Set Target Path
 	if b.redactionConfig == nil {
 		b.logEvent.TargetPath, _ = redaction.URIRedaction(b.logEvent.TargetPath)
@@ -611,6 +627,15 @@ func (b *transactionSummaryBuilder) SetRedactionConfig(config redaction.Redactio
 	return b
 }
 
+// SetTraceContext - sets the trace context of the transaction
+func (b *transactionSummaryBuilder) SetTraceContext(traceContext *TraceContext) SummaryBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.logEvent.TraceContext = traceContext
+	return b
+}
+
 func (b *transactionSummaryBuilder) Build() (*LogEvent, error) {
 	if b.err != nil {
 		return nil, b.err
@@ -0,0 +1,230 @@
+package transaction
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/traceability/otlp"
+)
+
+// OpenTelemetry semantic convention attribute keys of the transaction spans
+const (
+	otelHTTPMethod           = "http.request.method"
+	otelHTTPStatusCode       = "http.response.status_code"
+	otelURLPath              = "url.path"
+	otelServerAddress        = "server.address"
+	otelClientAddress        = "client.address"
+	otelUserAgent            = "user_agent.original"
+	otelNetworkProtocol      = "network.protocol.name"
+	otelMessagingSystem      = "messaging.system"
+	otelMessagingDestination = "messaging.destination.name"
+	otelMessagingMessageID   = "messaging.message.id"
+	otelMessagingConvID      = "messaging.message.conversation_id"
+	otelMessagingOperation   = "messaging.operation.type"
+
+	axwayTransactionID     = "axway.transaction.id"
+	axwayTransactionStatus = "axway.transaction.status"
+	axwayEnvironmentID     = "axway.environment.id"
+	axwayAPIID             = "axway.api.id"
+	axwayAPIName           = "axway.api.name"
+	axwayLegID             = "axway.leg.id"
+	axwayLegSource         = "axway.leg.source"
+	axwayLegDestination    = "axway.leg.destination"
+)
+
+// TransactionTraceID - the trace id of the spans of a transaction, derived from the transaction id so the spans of
+// the summary and the legs are in the same trace when exported separately
+func TransactionTraceID(transactionID string) otlp.TraceID {
+	return otlp.TraceID(md5.Sum([]byte(transactionID)))
+}
+
+// spanTraceID - the trace id of the trace context propagated to the transaction, or else derived from the transaction id
+func spanTraceID(logEvent LogEvent) otlp.TraceID {
+	traceID := otlp.TraceID{}
+	if tc := logEvent.TraceContext; tc != nil {
+		if id, err := hex.DecodeString(tc.TraceID); err == nil && len(id) == len(traceID) {
+			copy(traceID[:], id)
+			return traceID
+		}
+	}
+	return TransactionTraceID(logEvent.TransactionID)
+}
+
+// callerSpanID - the span of the caller of the transaction, from the propagated trace context
+func callerSpanID(logEvent LogEvent) otlp.SpanID {
+	spanID := otlp.SpanID{}
+	if tc := logEvent.TraceContext; tc != nil {
+		if id, err := hex.DecodeString(tc.SpanID); err == nil && len(id) == len(spanID) {
+			copy(spanID[:], id)
+		}
+	}
+	return spanID
+}
+
+func summarySpanID(transactionID string) otlp.SpanID {
+	return newSpanID(transactionID, "summary")
+}
+
+func legSpanID(transactionID, legID string) otlp.SpanID {
+	return newSpanID(transactionID, legID)
+}
+
+func newSpanID(transactionID, name string) otlp.SpanID {
+	sum := sha256.Sum256([]byte(transactionID + "/" + name))
+	id := otlp.SpanID{}
+	copy(id[:], sum[:len(id)])
+	return id
+}
+
+// NewOTLPSpan - the span of the insights event built from the log event. The summary is the root span of the
+// transaction, a child of the caller span when a trace context was propagated, and each leg is a child span of its
+// parent leg, or of the summary.
+func NewOTLPSpan(logEvent LogEvent, ie *InsightsEvent) (otlp.Span, error) {
+	if ie == nil {
+		return otlp.Span{}, fmt.Errorf("no insights event to build the span of transaction %s", logEvent.TransactionID)
+	}
+	span := otlp.Span{
+		TraceID:           spanTraceID(logEvent),
+		StartTimeUnixNano: otlp.Uint64(ie.Timestamp) * 1e6,
+	}
+	attributes := newSpanAttributes()
+	attributes.addString(axwayTransactionID, logEvent.TransactionID)
+	if ie.Distribution != nil {
+		attributes.addString(axwayEnvironmentID, ie.Distribution.Environment)
+	}
+
+	switch data := ie.Data.(type) {
+	case *TransactionSummaryData:
+		addSummarySpan(&span, attributes, logEvent.TransactionID, data)
+		span.ParentSpanID = callerSpanID(logEvent)
+	case *TransactionLegData:
+		var protocol TransportProtocol
+		if logEvent.TransactionEvent != nil {
+			protocol = logEvent.TransactionEvent.Protocol
+		}
+		addLegSpan(&span, attributes, logEvent.TransactionID, data, protocol)
+	default:
+		return otlp.Span{}, fmt.Errorf("no span for the data of the insights event %s", ie.Event)
+	}
+	span.Attributes = attributes.kvs
+	return span, nil
+}
+
+func addSummarySpan(span *otlp.Span, attributes *spanAttributes, transactionID string, data *TransactionSummaryData) {
+	span.SpanID = summarySpanID(transactionID)
+	span.Kind = otlp.SpanKindServer
+	span.EndTimeUnixNano = span.StartTimeUnixNano + otlp.Uint64(data.Duration)*1e6
+	span.Name = "transaction"
+
+	attributes.addString(axwayTransactionStatus, data.Status)
+	if data.API != nil {
+		attributes.addString(axwayAPIID, data.API.ID)
+		attributes.addString(axwayAPIName, data.API.Name)
+		if data.API.Name != "" {
+			span.Name = data.API.Name
+		}
+	}
+	if ep := data.EntryPoint; ep != nil {
+		attributes.addString(otelHTTPMethod, ep.Method)
+		attributes.addString(otelURLPath, ep.Path)
+		attributes.addString(otelServerAddress, ep.Host)
+		if ep.Method != "" {
+			span.Name = strings.TrimSpace(ep.Method + " " + ep.Path)
+		}
+	}
+	if code, err := strconv.Atoi(data.StatusDetail); err == nil {
+		attributes.addInt(otelHTTPStatusCode, int64(code))
+	}
+
+	switch TxSummaryStatus(data.Status) {
+	case TxSummaryStatusFailure, TxSummaryStatusException:
+		span.Status = otlp.Status{Code: otlp.StatusCodeError, Message: data.StatusDetail}
+	}
+}
+
+func addLegSpan(span *otlp.Span, attributes *spanAttributes, transactionID string, data *TransactionLegData, protocol TransportProtocol) {
+	span.SpanID = legSpanID(transactionID, data.ID)
+	span.ParentSpanID = summarySpanID(transactionID)
+	if data.ParentID != "" {
+		span.ParentSpanID = legSpanID(transactionID, data.ParentID)
+	}
+	span.EndTimeUnixNano = span.StartTimeUnixNano + otlp.Uint64(data.Duration)*1e6
+	span.Name = data.ID
+
+	attributes.addString(axwayLegID, data.ID)
+	attributes.addString(axwayLegSource, data.Source)
+	attributes.addString(axwayLegDestination, data.Destination)
+	if data.API != nil {
+		attributes.addString(axwayAPIID, data.API.ID)
+	}
+
+	inbound := strings.EqualFold(data.Direction, "inbound")
+	outbound := strings.EqualFold(data.Direction, "outbound")
+	switch p := protocol.(type) {
+	case *JMSProtocol:
+		span.Kind = otlp.SpanKindInternal
+		operation := "process"
+		if inbound {
+			span.Kind = otlp.SpanKindConsumer
+			operation = "receive"
+		} else if outbound {
+			span.Kind = otlp.SpanKindProducer
+			operation = "send"
+		}
+		span.Name = strings.TrimSpace(p.JMSDestination + " " + operation)
+		attributes.addString(otelMessagingSystem, "jms")
+		attributes.addString(otelMessagingDestination, p.JMSDestination)
+		attributes.addString(otelMessagingMessageID, p.JMSMessageID)
+		attributes.addString(otelMessagingConvID, p.JMSCorrelationID)
+		attributes.addString(otelMessagingOperation, operation)
+	default:
+		span.Kind = otlp.SpanKindInternal
+		if inbound {
+			span.Kind = otlp.SpanKindServer
+		} else if outbound {
+			span.Kind = otlp.SpanKindClient
+		}
+		if proto := data.Protocol; proto != nil {
+			path := strings.SplitN(proto.URI, "?", 2)[0]
+			span.Name = strings.TrimSpace(proto.Method + " " + path)
+			attributes.addString(otelNetworkProtocol, "http")
+			attributes.addString(otelHTTPMethod, proto.Method)
+			attributes.addString(otelURLPath, path)
+			if proto.Status != 0 {
+				attributes.addInt(otelHTTPStatusCode, int64(proto.Status))
+			}
+		}
+		if p, ok := protocol.(*Protocol); ok && p != nil {
+			attributes.addString(otelServerAddress, p.Host)
+			attributes.addString(otelClientAddress, p.RemoteAddr)
+			attributes.addString(otelUserAgent, p.UserAgent)
+		}
+	}
+
+	if TxEventStatus(data.Status) == TxEventStatusFail {
+		span.Status = otlp.Status{Code: otlp.StatusCodeError}
+	}
+}
+
+// spanAttributes - the attributes of a span, skipping empty values
+type spanAttributes struct {
+	kvs []otlp.KeyValue
+}
+
+func newSpanAttributes() *spanAttributes {
+	return &spanAttributes{kvs: []otlp.KeyValue{}}
+}
+
+func (a *spanAttributes) addString(key, value string) {
+	if value != "" {
+		a.kvs = append(a.kvs, otlp.StringAttribute(key, value))
+	}
+}
+
+func (a *spanAttributes) addInt(key string, value int64) {
+	a.kvs = append(a.kvs, otlp.IntAttribute(key, value))
+}
@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Axway/agent-sdk/pkg/traceability/otlp"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+func spanAttributeValues(span otlp.Span) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, kv := range span.Attributes {
+		switch {
+		case kv.Value.StringValue != nil:
+			values[kv.Key] = *kv.Value.StringValue
+		case kv.Value.IntValue != nil:
+			values[kv.Key] = int64(*kv.Value.IntValue)
+		}
+	}
+	return values
+}
+
+func TestNewOTLPSpan(t *testing.T) {
+	const txID = "txn-otlp-1"
+	traceID := TransactionTraceID(txID)
+	summarySpan := summarySpanID(txID)
+
+	cases := map[string]struct {
+		logEvent   LogEvent
+		name       string
+		kind       otlp.SpanKind
+		parent     otlp.SpanID
+		duration   otlp.Uint64
+		status     otlp.StatusCode
+		attributes map[string]interface{}
+	}{
+		"summary is the root span": {
+			logEvent: LogEvent{
+				Type:          TypeTransactionSummary,
+				TransactionID: txID,
+				Stamp:         1700000000000,
+				TransactionSummary: &Summary{
+					Status:       "Success",
+					StatusDetail: "200",
+					Duration:     50,
+					EntryPoint:   &EntryPoint{Method: "GET", Path: "/pets", Host: "petstore.io"},
+				},
+			},
+			name:     "GET /pets",
+			kind:     otlp.SpanKindServer,
+			duration: 50,
+			attributes: map[string]interface{}{
+				otelHTTPMethod:         "GET",
+				otelURLPath:            "/pets",
+				otelServerAddress:      "petstore.io",
+				otelHTTPStatusCode:     int64(200),
+				axwayTransactionID:     txID,
+				axwayTransactionStatus: "Success",
+				axwayEnvironmentID:     testEnvID,
+			},
+		},
+		"failed summary has an error status": {
+			logEvent: LogEvent{
+				Type:               TypeTransactionSummary,
+				TransactionID:      txID,
+				Stamp:              1700000000000,
+				TransactionSummary: &Summary{Status: "Failure", StatusDetail: "500"},
+			},
+			name:   "transaction",
+			kind:   otlp.SpanKindServer,
+			status: otlp.StatusCodeError,
+		},
+		"inbound http leg is a child of the summary": {
+			logEvent: LogEvent{
+				Type:          TypeTransactionEvent,
+				TransactionID: txID,
+				Stamp:         1700000000000,
+				TransactionEvent: &Event{
+					ID:        "0",
+					Status:    "Pass",
+					Duration:  20,
+					Direction: "Inbound",
+					Protocol: &Protocol{
+						Type:       "http",
+						Method:     "POST",
+						URI:        "/pets?limit=1",
+						Status:     201,
+						Host:       "petstore.io",
+						RemoteAddr: "10.0.0.1",
+					},
+				},
+			},
+			name:     "POST /pets",
+			kind:     otlp.SpanKindServer,
+			parent:   summarySpan,
+			duration: 20,
+			attributes: map[string]interface{}{
+				otelHTTPMethod:     "POST",
+				otelURLPath:        "/pets",
+				otelHTTPStatusCode: int64(201),
+				otelServerAddress:  "petstore.io",
+				otelClientAddress:  "10.0.0.1",
+				axwayLegID:         "leg0",
+			},
+		},
+		"failed outbound http leg is a child of its parent leg": {
+			logEvent: LogEvent{
+				Type:          TypeTransactionEvent,
+				TransactionID: txID,
+				Stamp:         1700000000000,
+				TransactionEvent: &Event{
+					ID:        "1",
+					ParentID:  "0",
+					Status:    "Fail",
+					Direction: "Outbound",
+					Protocol:  &Protocol{Type: "http", Method: "GET", URI: "/backend", Status: 503},
+				},
+			},
+			name:   "GET /backend",
+			kind:   otlp.SpanKindClient,
+			parent: legSpanID(txID, "leg0"),
+			status: otlp.StatusCodeError,
+			attributes: map[string]interface{}{
+				otelHTTPStatusCode: int64(503),
+				axwayLegID:         "leg1",
+			},
+		},
+		"outbound jms leg is a producer span": {
+			logEvent: LogEvent{
+				Type:          TypeTransactionEvent,
+				TransactionID: txID,
+				Stamp:         1700000000000,
+				TransactionEvent: &Event{
+					ID:        "2",
+					Status:    "Pass",
+					Direction: "Outbound",
+					Protocol:  &JMSProtocol{Type: "jms", JMSDestination: "orders", JMSMessageID: "msg-1"},
+				},
+			},
+			name:   "orders send",
+			kind:   otlp.SpanKindProducer,
+			parent: summarySpan,
+			attributes: map[string]interface{}{
+				otelMessagingSystem:      "jms",
+				otelMessagingDestination: "orders",
+				otelMessagingMessageID:   "msg-1",
+				otelMessagingOperation:   "send",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ie, err := BuildTransactionV2Data(log.NewFieldLogger(), tc.logEvent, testOrgID, testEnvID, nil, nil, ReporterInfo{})
+			require.NoError(t, err)
+
+			span, err := NewOTLPSpan(tc.logEvent, ie)
+			require.NoError(t, err)
+			assert.Equal(t, traceID, span.TraceID)
+			assert.False(t, span.SpanID.IsEmpty())
+			assert.Equal(t, tc.parent, span.ParentSpanID)
+			assert.Equal(t, tc.name, span.Name)
+			assert.Equal(t, tc.kind, span.Kind)
+			assert.Equal(t, otlp.Uint64(1700000000000000000), span.StartTimeUnixNano)
+			assert.Equal(t, tc.duration*1e6, span.EndTimeUnixNano-span.StartTimeUnixNano)
+			assert.Equal(t, tc.status, span.Status.Code)
+
+			values := spanAttributeValues(span)
+			for key, value := range tc.attributes {
+				assert.Equal(t, value, values[key], key)
+			}
+		})
+	}
+
+	_, err := NewOTLPSpan(LogEvent{TransactionID: txID}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewOTLPSpanTraceContext(t *testing.T) {
+	logEvent := LogEvent{
+		Type:               TypeTransactionSummary,
+		TransactionID:      "txn-otlp-2",
+		TransactionSummary: &Summary{Status: "Success"},
+		TraceContext:       &TraceContext{Format: TraceContextW3C, TraceID: testTraceID, SpanID: testSpanID},
+	}
+	ie, err := BuildTransactionV2Data(log.NewFieldLogger(), logEvent, testOrgID, testEnvID, nil, nil, ReporterInfo{})
+	require.NoError(t, err)
+
+	// the summary is part of the propagated trace, a child of the caller span
+	span, err := NewOTLPSpan(logEvent, ie)
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, span.TraceID.String())
+	assert.Equal(t, testSpanID, span.ParentSpanID.String())
+	assert.Equal(t, summarySpanID("txn-otlp-2"), span.SpanID)
+}
@@ -0,0 +1,173 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// TraceContextFormat - the format of the headers the trace context was propagated in
+type TraceContextFormat string
+
+const (
+	// TraceContextW3C - W3C Trace Context, the traceparent and tracestate headers
+	TraceContextW3C TraceContextFormat = "w3c"
+	// TraceContextB3 - Zipkin B3, the single b3 header or the X-B3-* headers
+	TraceContextB3 TraceContextFormat = "b3"
+)
+
+// Trace context header names, lower case
+const (
+	traceparentHeader    = "traceparent"
+	tracestateHeader     = "tracestate"
+	b3Header             = "b3"
+	b3TraceIDHeader      = "x-b3-traceid"
+	b3SpanIDHeader       = "x-b3-spanid"
+	b3ParentSpanIDHeader = "x-b3-parentspanid"
+	b3SampledHeader      = "x-b3-sampled"
+	b3FlagsHeader        = "x-b3-flags"
+)
+
+// TraceContext - the distributed trace a transaction is part of, as propagated in the request headers.
+// The SpanID is the span of the caller that sent the request.
+type TraceContext struct {
+	Format       TraceContextFormat `json:"format"`
+	TraceID      string             `json:"traceId"`
+	SpanID       string             `json:"spanId,omitempty"`
+	ParentSpanID string             `json:"parentSpanId,omitempty"`
+	Sampled      *bool              `json:"sampled,omitempty"`
+	TraceState   string             `json:"traceState,omitempty"`
+}
+
+// ParseTraceContext - parses the W3C Trace Context or B3 headers, W3C first, returns nil when none are valid.
+// The header names are matched case insensitively.
+func ParseTraceContext(headers map[string]string) *TraceContext {
+	if len(headers) == 0 {
+		return nil
+	}
+	lowerHeaders := make(map[string]string, len(headers))
+	for k, v := range headers {
+		lowerHeaders[strings.ToLower(k)] = strings.TrimSpace(v)
+	}
+
+	if tc := parseTraceparent(lowerHeaders[traceparentHeader]); tc != nil {
+		tc.TraceState = lowerHeaders[tracestateHeader]
+		return tc
+	}
+	if tc := parseB3Single(lowerHeaders[b3Header]); tc != nil {
+		return tc
+	}
+	return parseB3Multi(lowerHeaders)
+}
+
+// parseTraceparent - version-traceid-parentid-flags, i.e. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func parseTraceparent(value string) *TraceContext {
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 {
+		return nil
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	// version ff is invalid, version 00 has exactly 4 parts, later versions may add parts
+	if !isHexID(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return nil
+	}
+	if !isHexID(traceID, 32) || !isHexID(spanID, 16) || !isHexID(flags, 2) {
+		return nil
+	}
+	flagBits, _ := hex.DecodeString(flags)
+	sampled := flagBits[0]&0x01 == 0x01
+	return &TraceContext{
+		Format:  TraceContextW3C,
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: &sampled,
+	}
+}
+
+// parseB3Single - traceid-spanid-sampled-parentspanid, the sampled and parent span are optional
+func parseB3Single(value string) *TraceContext {
+	parts := strings.Split(value, "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		// a lone sampling decision carries no ids
+		return nil
+	}
+	tc := &TraceContext{Format: TraceContextB3}
+	tc.TraceID = normalizeB3TraceID(parts[0])
+	tc.SpanID = strings.ToLower(parts[1])
+	if tc.TraceID == "" || !isHexID(tc.SpanID, 16) {
+		return nil
+	}
+	if len(parts) > 2 {
+		tc.Sampled = parseB3Sampled(parts[2])
+	}
+	if len(parts) > 3 {
+		if !isHexID(strings.ToLower(parts[3]), 16) {
+			return nil
+		}
+		tc.ParentSpanID = strings.ToLower(parts[3])
+	}
+	return tc
+}
+
+func parseB3Multi(headers map[string]string) *TraceContext {
+	tc := &TraceContext{
+		Format:  TraceContextB3,
+		TraceID: normalizeB3TraceID(headers[b3TraceIDHeader]),
+		SpanID:  strings.ToLower(headers[b3SpanIDHeader]),
+	}
+	if tc.TraceID == "" || !isHexID(tc.SpanID, 16) {
+		return nil
+	}
+	if parent := strings.ToLower(headers[b3ParentSpanIDHeader]); isHexID(parent, 16) {
+		tc.ParentSpanID = parent
+	}
+	tc.Sampled = parseB3Sampled(headers[b3SampledHeader])
+	if headers[b3FlagsHeader] == "1" {
+		// debug implies sampled
+		tc.Sampled = parseB3Sampled("d")
+	}
+	return tc
+}
+
+// normalizeB3TraceID - the 128 bit trace id, 64 bit ids are left padded with zeros, empty when invalid
+func normalizeB3TraceID(traceID string) string {
+	traceID = strings.ToLower(traceID)
+	if isHexID(traceID, 16) {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+	if !isHexID(traceID, 32) {
+		return ""
+	}
+	return traceID
+}
+
+func parseB3Sampled(value string) *bool {
+	var sampled bool
+	switch strings.ToLower(value) {
+	case "1", "d", "true":
+		sampled = true
+	case "0", "false":
+		sampled = false
+	default:
+		return nil
+	}
+	return &sampled
+}
+
+// isHexID - true when the id is lower case hex of the length and not all zeros
+func isHexID(id string, length int) bool {
+	if len(id) != length {
+		return false
+	}
+	allZeros := true
+	for _, c := range id {
+		switch {
+		case c == '0':
+		case (c >= '1' && c <= '9') || (c >= 'a' && c <= 'f'):
+			allZeros = false
+		default:
+			return false
+		}
+	}
+	// the version and flags of traceparent may be all zeros
+	return !allZeros || length == 2
+}
@@ -0,0 +1,181 @@
+package transaction
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Axway/agent-sdk/pkg/traceability/redaction"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID   = "00f067aa0ba902b7"
+	testParentID = "05e3ac9a4f6e3b90"
+)
+
+func TestParseTraceContext(t *testing.T) {
+	sampled, notSampled := true, false
+	cases := map[string]struct {
+		headers  map[string]string
+		expected *TraceContext
+	}{
+		"no headers": {},
+		"w3c traceparent and tracestate": {
+			headers: map[string]string{
+				"Traceparent": "00-" + testTraceID + "-" + testSpanID + "-01",
+				"TraceState":  "congo=t61rcWkgMzE",
+			},
+			expected: &TraceContext{Format: TraceContextW3C, TraceID: testTraceID, SpanID: testSpanID, Sampled: &sampled, TraceState: "congo=t61rcWkgMzE"},
+		},
+		"w3c not sampled": {
+			headers:  map[string]string{"traceparent": "00-" + testTraceID + "-" + testSpanID + "-00"},
+			expected: &TraceContext{Format: TraceContextW3C, TraceID: testTraceID, SpanID: testSpanID, Sampled: &notSampled},
+		},
+		"w3c future version with more parts": {
+			headers:  map[string]string{"traceparent": "01-" + testTraceID + "-" + testSpanID + "-01-future"},
+			expected: &TraceContext{Format: TraceContextW3C, TraceID: testTraceID, SpanID: testSpanID, Sampled: &sampled},
+		},
+		"w3c invalid version": {
+			headers: map[string]string{"traceparent": "ff-" + testTraceID + "-" + testSpanID + "-01"},
+		},
+		"w3c all zero trace id": {
+			headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-" + testSpanID + "-01"},
+		},
+		"w3c upper case trace id": {
+			headers: map[string]string{"traceparent": "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanID + "-01"},
+		},
+		"w3c is preferred over b3": {
+			headers: map[string]string{
+				"traceparent": "00-" + testTraceID + "-" + testSpanID + "-01",
+				"b3":          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			expected: &TraceContext{Format: TraceContextW3C, TraceID: testTraceID, SpanID: testSpanID, Sampled: &sampled},
+		},
+		"invalid w3c falls back to b3": {
+			headers: map[string]string{
+				"traceparent": "garbage",
+				"b3":          testTraceID + "-" + testSpanID,
+			},
+			expected: &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testSpanID},
+		},
+		"b3 single header with parent": {
+			headers:  map[string]string{"B3": testTraceID + "-" + testSpanID + "-1-" + testParentID},
+			expected: &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testSpanID, ParentSpanID: testParentID, Sampled: &sampled},
+		},
+		"b3 single header 64 bit trace id": {
+			headers:  map[string]string{"b3": "a3ce929d0e0e4736-" + testSpanID + "-0"},
+			expected: &TraceContext{Format: TraceContextB3, TraceID: "0000000000000000a3ce929d0e0e4736", SpanID: testSpanID, Sampled: &notSampled},
+		},
+		"b3 single header sampling only": {
+			headers: map[string]string{"b3": "0"},
+		},
+		"b3 multiple headers": {
+			headers: map[string]string{
+				"X-B3-TraceId":      testTraceID,
+				"X-B3-SpanId":       testSpanID,
+				"X-B3-ParentSpanId": testParentID,
+				"X-B3-Sampled":      "0",
+			},
+			expected: &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testSpanID, ParentSpanID: testParentID, Sampled: &notSampled},
+		},
+		"b3 multiple headers debug": {
+			headers: map[string]string{
+				"x-b3-traceid": testTraceID,
+				"x-b3-spanid":  testSpanID,
+				"x-b3-flags":   "1",
+			},
+			expected: &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testSpanID, Sampled: &sampled},
+		},
+		"b3 multiple headers without span": {
+			headers: map[string]string{"X-B3-TraceId": testTraceID},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseTraceContext(tc.headers))
+		})
+	}
+}
+
+func TestHTTPProtocolBuilderTraceContext(t *testing.T) {
+	// the default redaction removes all the headers
+	redaction.SetupGlobalRedaction(redaction.DefaultConfig())
+	protocol, err := NewHTTPProtocolBuilder().
+		SetURI("/pets").
+		SetMethod("GET").
+		SetStatus(200, "OK").
+		SetHost("petstore.io").
+		SetRequestHeaders(map[string]string{"traceparent": "00-" + testTraceID + "-" + testSpanID + "-01"}).
+		SetResponseHeaders(map[string]string{"content-type": "application/json"}).
+		Build()
+	require.NoError(t, err)
+
+	httpProtocol := protocol.(*Protocol)
+	assert.NotContains(t, httpProtocol.RequestHeaders, "traceparent")
+	require.NotNil(t, httpProtocol.GetTraceContext())
+	assert.Equal(t, testTraceID, httpProtocol.GetTraceContext().TraceID)
+
+	logEvent, err := NewTransactionEventBuilder().
+		SetTransactionID("txn-1").
+		SetID("0").
+		SetDirection("Inbound").
+		SetStatus(TxEventStatusPass).
+		SetProtocolDetail(protocol).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, httpProtocol.GetTraceContext(), logEvent.TraceContext)
+
+	data, err := json.Marshal(logEvent)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"traceContext":{"format":"w3c","traceId":"`+testTraceID+`"`)
+
+	// an explicit trace context is kept
+	explicit := &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testParentID}
+	logEvent, err = NewTransactionEventBuilder().
+		SetTransactionID("txn-1").
+		SetID("0").
+		SetDirection("Inbound").
+		SetStatus(TxEventStatusPass).
+		SetTraceContext(explicit).
+		SetProtocolDetail(protocol).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, explicit, logEvent.TraceContext)
+}
+
+func TestEventReportTraceContextCorrelation(t *testing.T) {
+	inbound := &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: testSpanID}
+	outbound := &TraceContext{Format: TraceContextB3, TraceID: testTraceID, SpanID: "e457b5a2e4d86bd1", ParentSpanID: testSpanID}
+	newReport := func() EventReportBuilder {
+		return NewEventReportBuilder().
+			SetSummaryEvent(LogEvent{TransactionSummary: &Summary{}}).
+			SetDetailEvents([]LogEvent{
+				{TransactionEvent: &Event{ID: "1"}, TraceContext: outbound},
+				{TransactionEvent: &Event{ID: "0"}, TraceContext: inbound},
+				{TransactionID: "txn-1", TransactionEvent: &Event{ID: "2"}},
+			})
+	}
+
+	// without the correlation only the summary gets the trace context of the root leg
+	report, err := newReport().Build()
+	require.NoError(t, err)
+	summary := report.GetSummaryEvent()
+	assert.Equal(t, inbound, summary.TraceContext)
+	assert.Equal(t, "", summary.TransactionID)
+	assert.Equal(t, "", report.GetDetailEvents()[0].TransactionEvent.ParentID)
+
+	report, err = newReport().SetTraceContextCorrelation().Build()
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, report.GetSummaryEvent().TransactionID)
+	details := report.GetDetailEvents()
+	assert.Equal(t, testTraceID, details[0].TransactionID)
+	assert.Equal(t, "0", details[0].TransactionEvent.ParentID)
+	assert.Equal(t, testTraceID, details[1].TransactionID)
+	assert.Equal(t, "", details[1].TransactionEvent.ParentID)
+	assert.Equal(t, "txn-1", details[2].TransactionID)
+	assert.Equal(t, "", details[2].TransactionEvent.ParentID)
+}
@@ -114,6 +114,7 @@ type TransactionLegData struct {
 	Protocol       *legProtocol       `json:"protocol,omitempty"`
 	API            *insightsAPIDetail `json:"-"`
 	Reporter       *insightsReporter  `json:"reporter,omitempty"`
+	TraceContext   *TraceContext      `json:"traceContext,omitempty"`
 }
 
 // GetStartTime implements metric.V4Data.
@@ -163,6 +164,7 @@ type TransactionSummaryData struct {
 	Reporter           *insightsReporter        `json:"reporter,omitempty"`
 	ConsumerDetails    *insightsConsumerDetails `json:"consumerDetails,omitempty"`
 	Proxy              *insightsProxy           `json:"proxy,omitempty"`
+	TraceContext       *TraceContext            `json:"traceContext,omitempty"`
 }
 
 // GetStartTime implements metric.V4Data.
@@ -306,6 +308,7 @@ func buildLegV2Data(logEvent LogEvent, summaryProxy *Proxy, cacheManager cache.M
 			AgentSDKVersion: reporter.AgentSDKVersion,
 			AgentName:       reporter.AgentName,
 		},
+		TraceContext: logEvent.TraceContext,
 	}
 
 	return data, nil
@@ -341,6 +344,7 @@ func buildSummaryV2Data(logger log.FieldLogger, logEvent LogEvent, cacheManager
 			AgentName:        reporter.AgentName,
 			ObservationDelta: reporter.ObservationDelta,
 		},
+		TraceContext: logEvent.TraceContext,
 	}
 
 	if summary.Product != nil && summary.Product.ID != "" {
@@ -0,0 +1,169 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bookstoreSDL = `
+"""
+  Bookstore API
+"""
+schema { query: RootQuery, mutation: RootMutation }
+
+type RootQuery {
+  "Find a book"
+  book(id: ID!): Book
+  books(first: Int = 10, tags: [String!] = ["a", "b"]): [Book!]! @deprecated
+}
+
+type RootMutation {
+  addBook(input: BookInput!): Book!
+}
+
+type Book implements Node & Titled @key(fields: "id") {
+  id: ID!
+  title: String
+}
+
+interface Node { id: ID! }
+interface Titled { title: String }
+
+input BookInput { title: String! = "untitled" }
+
+enum Genre { FICTION, SCIENCE }
+
+union Item = Book
+
+extend type RootQuery {
+  genres: [Genre]
+}
+`
+
+func TestParseSDL(t *testing.T) {
+	schema, err := ParseSDL([]byte(bookstoreSDL))
+	assert.Nil(t, err)
+	assert.Nil(t, schema.Validate())
+
+	assert.Equal(t, "Bookstore API", schema.Description)
+	assert.Equal(t, "RootQuery", schema.QueryType)
+	assert.Equal(t, "RootMutation", schema.MutationType)
+	assert.Equal(t, "", schema.SubscriptionType)
+
+	queries := schema.RootOperations(OperationQuery)
+	assert.Len(t, queries, 3)
+	assert.Equal(t, "book", queries[0].Name)
+	assert.Equal(t, "Find a book", queries[0].Description)
+	assert.Equal(t, "Book", queries[0].Type.String())
+	assert.Equal(t, "ID!", queries[0].Arguments[0].Type.String())
+	assert.Equal(t, "[Book!]!", queries[1].Type.String())
+	assert.Equal(t, "Book", queries[1].Type.NamedType())
+	assert.True(t, queries[1].IsDeprecated)
+	assert.Equal(t, "genres", queries[2].Name)
+
+	assert.Len(t, schema.RootOperations(OperationMutation), 1)
+	assert.Nil(t, schema.RootOperations(OperationSubscription))
+	assert.Equal(t, []string{"Node", "Titled"}, schema.Types["Book"].Interfaces)
+	assert.Equal(t, []string{"FICTION", "SCIENCE"}, schema.Types["Genre"].EnumValues)
+	assert.Equal(t, []string{"Book"}, schema.Types["Item"].PossibleTypes)
+}
+
+func TestParseSDLErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty document":          "  # only a comment",
+		"executable definition":   "query { books { id } }",
+		"missing field type":      "type Query { books }",
+		"unterminated string":     "\"description\ntype Query { a: String }",
+		"unterminated block":      "\"\"\" description type Query { a: String }",
+		"unclosed type":           "type Query { a: String",
+		"duplicate type":          "type Query { a: String } type Query { b: String }",
+		"extend undefined type":   "type Query { a: String } extend type Book { b: String }",
+		"unknown operation":       "schema { read: Query } type Query { a: String }",
+		"unexpected character":    "type Query { a: String% }",
+		"variable in const value": "type Query { a(b: Int = $c): String }",
+	}
+	for name, sdl := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSDL([]byte(sdl))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		sdl   string
+		valid bool
+	}{
+		"valid schema": {
+			sdl:   "type Query { a: String }",
+			valid: true,
+		},
+		"no query type": {
+			sdl: "type Book { a: String }",
+		},
+		"unknown field type": {
+			sdl: "type Query { a: Book }",
+		},
+		"unknown argument type": {
+			sdl: "type Query { a(b: Filter): String }",
+		},
+		"duplicate field": {
+			sdl: "type Query { a: String a: Int }",
+		},
+		"root type is not an object": {
+			sdl: "schema { query: Q } input Q { a: String }",
+		},
+		"implements an object": {
+			sdl: "type Query { a: String } type B implements Query { a: String }",
+		},
+		"union of scalars": {
+			sdl: "type Query { a: String } union U = String",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, err := ParseSDL([]byte(tc.sdl))
+			assert.Nil(t, err)
+			err = schema.Validate()
+			if tc.valid {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestParseIntrospection(t *testing.T) {
+	introspection := `{"__schema":{"queryType":{"name":"Query"},"subscriptionType":{"name":"Sub"},"types":[
+		{"kind":"OBJECT","name":"Query","description":"root","fields":[{"name":"items","args":[{"name":"first","type":{"kind":"SCALAR","name":"Int"}}],
+			"type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"kind":"OBJECT","name":"Item"}}}}]},
+		{"kind":"OBJECT","name":"Sub","fields":[{"name":"itemAdded","args":[],"type":{"kind":"OBJECT","name":"Item"},"isDeprecated":true,"deprecationReason":"gone"}]},
+		{"kind":"OBJECT","name":"Item","fields":[{"name":"id","args":[],"type":{"kind":"SCALAR","name":"ID"}}]},
+		{"kind":"INPUT_OBJECT","name":"Filter","inputFields":[{"name":"id","type":{"kind":"SCALAR","name":"ID"}}]}
+	]}}`
+
+	assert.True(t, IsIntrospection([]byte(introspection)))
+	assert.False(t, IsIntrospection([]byte(`{"openapi":"3.0.0"}`)))
+	assert.False(t, IsIntrospection([]byte("type Query { a: String }")))
+
+	schema, err := Parse([]byte(introspection))
+	assert.Nil(t, err)
+	assert.Nil(t, schema.Validate())
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Equal(t, "Sub", schema.SubscriptionType)
+
+	queries := schema.RootOperations(OperationQuery)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, "[Item]!", queries[0].Type.String())
+	assert.Equal(t, "first", queries[0].Arguments[0].Name)
+	subscriptions := schema.RootOperations(OperationSubscription)
+	assert.True(t, subscriptions[0].IsDeprecated)
+	assert.Equal(t, "gone", subscriptions[0].DeprecationReason)
+	assert.Len(t, schema.Types["Filter"].Fields, 1)
+
+	_, err = ParseIntrospection([]byte(`{"data":{}}`))
+	assert.NotNil(t, err)
+}
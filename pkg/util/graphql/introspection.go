@@ -0,0 +1,171 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type introspectionResult struct {
+	Data   *introspectionData   `json:"data"`
+	Schema *introspectionSchema `json:"__schema"`
+}
+
+type introspectionData struct {
+	Schema *introspectionSchema `json:"__schema"`
+}
+
+type introspectionSchema struct {
+	Description      string              `json:"description"`
+	QueryType        *introspectionName  `json:"queryType"`
+	MutationType     *introspectionName  `json:"mutationType"`
+	SubscriptionType *introspectionName  `json:"subscriptionType"`
+	Types            []introspectionType `json:"types"`
+	Directives       []introspectionName `json:"directives"`
+}
+
+type introspectionName struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          string               `json:"kind"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	Fields        []introspectionField `json:"fields"`
+	InputFields   []introspectionField `json:"inputFields"`
+	Interfaces    []introspectionName  `json:"interfaces"`
+	PossibleTypes []introspectionName  `json:"possibleTypes"`
+	EnumValues    []introspectionName  `json:"enumValues"`
+}
+
+type introspectionField struct {
+	Name              string               `json:"name"`
+	Description       string               `json:"description"`
+	Args              []introspectionField `json:"args"`
+	Type              *introspectionRef    `json:"type"`
+	IsDeprecated      bool                 `json:"isDeprecated"`
+	DeprecationReason string               `json:"deprecationReason"`
+}
+
+type introspectionRef struct {
+	Kind   string            `json:"kind"`
+	Name   string            `json:"name"`
+	OfType *introspectionRef `json:"ofType"`
+}
+
+// IsIntrospection returns true when the document is a json introspection result
+func IsIntrospection(data []byte) bool {
+	result := introspectionResult{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return false
+	}
+	return result.Schema != nil || (result.Data != nil && result.Data.Schema != nil)
+}
+
+// ParseIntrospection parses the json result of an introspection query, with or without the data wrapper
+func ParseIntrospection(data []byte) (*Schema, error) {
+	result := introspectionResult{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid graphql introspection result: %s", err)
+	}
+	is := result.Schema
+	if result.Data != nil && result.Data.Schema != nil {
+		is = result.Data.Schema
+	}
+	if is == nil {
+		return nil, errors.New("invalid graphql introspection result: '__schema' key not found")
+	}
+
+	schema := &Schema{
+		Description:      is.Description,
+		QueryType:        nameOf(is.QueryType),
+		MutationType:     nameOf(is.MutationType),
+		SubscriptionType: nameOf(is.SubscriptionType),
+		Types:            map[string]*Type{},
+	}
+	for _, d := range is.Directives {
+		schema.Directives = append(schema.Directives, d.Name)
+	}
+	for _, it := range is.Types {
+		if it.Name == "" {
+			return nil, errors.New("invalid graphql introspection result: type without a name")
+		}
+		t := &Type{
+			Kind:        it.Kind,
+			Name:        it.Name,
+			Description: it.Description,
+		}
+		fields := it.Fields
+		if it.Kind == KindInputObject {
+			fields = it.InputFields
+		}
+		for _, f := range fields {
+			t.Fields = append(t.Fields, f.toField())
+		}
+		t.Interfaces = namesOf(it.Interfaces)
+		t.PossibleTypes = namesOf(it.PossibleTypes)
+		t.EnumValues = namesOf(it.EnumValues)
+		schema.Types[it.Name] = t
+	}
+	return schema, nil
+}
+
+// Parse parses either an SDL document or a json introspection result
+func Parse(data []byte) (*Schema, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' && json.Valid(trimmed) {
+		return ParseIntrospection(trimmed)
+	}
+	return ParseSDL(data)
+}
+
+func (f introspectionField) toField() *Field {
+	field := &Field{
+		Name:              f.Name,
+		Description:       f.Description,
+		Type:              f.Type.toTypeRef(),
+		IsDeprecated:      f.IsDeprecated,
+		DeprecationReason: f.DeprecationReason,
+	}
+	for _, a := range f.Args {
+		field.Arguments = append(field.Arguments, a.toField())
+	}
+	return field
+}
+
+func (r *introspectionRef) toTypeRef() *TypeRef {
+	if r == nil {
+		return nil
+	}
+	switch r.Kind {
+	case "NON_NULL":
+		ref := r.OfType.toTypeRef()
+		if ref != nil {
+			ref.NonNull = true
+		}
+		return ref
+	case "LIST":
+		return &TypeRef{OfType: r.OfType.toTypeRef()}
+	}
+	return &TypeRef{Name: r.Name}
+}
+
+func nameOf(n *introspectionName) string {
+	if n == nil {
+		return ""
+	}
+	return n.Name
+}
+
+func namesOf(names []introspectionName) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(names))
+	for _, n := range names {
+		list = append(list, n.Name)
+	}
+	return list
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+	tokenBlockString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+	col   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "<EOF>"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// lexer splits a GraphQL document into tokens, ignoring whitespace, commas and comments
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: strings.TrimPrefix(src, "\ufeff"), line: 1, col: 1}
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graphql syntax error (%d:%d): %s", l.line, l.col, fmt.Sprintf(format, args...))
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	tok := token{line: l.line, col: l.col}
+	if l.pos >= len(l.src) {
+		tok.kind = tokenEOF
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		tok.kind, tok.value = tokenPunctuator, "..."
+		l.advance(3)
+		return tok, nil
+	case strings.ContainsRune("!$&():=@[]{|}", rune(c)):
+		tok.kind, tok.value = tokenPunctuator, string(c)
+		l.advance(1)
+		return tok, nil
+	case isNameStart(c):
+		start := l.pos
+		for l.pos < len(l.src) && isNameContinue(l.src[l.pos]) {
+			l.advance(1)
+		}
+		tok.kind, tok.value = tokenName, l.src[start:l.pos]
+		return tok, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber(tok)
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		return l.readBlockString(tok)
+	case c == '"':
+		return l.readString(tok)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return tok, l.errorf("unexpected character %q", r)
+}
+
+func (l *lexer) readNumber(tok token) (token, error) {
+	start := l.pos
+	tok.kind = tokenInt
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+		return tok, l.errorf("invalid number")
+	}
+	l.readDigits()
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		tok.kind = tokenFloat
+		l.advance(1)
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return tok, l.errorf("invalid number")
+		}
+		l.readDigits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		tok.kind = tokenFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return tok, l.errorf("invalid number")
+		}
+		l.readDigits()
+	}
+	tok.value = l.src[start:l.pos]
+	return tok, nil
+}
+
+func (l *lexer) readDigits() {
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.advance(1)
+	}
+}
+
+func (l *lexer) readString(tok token) (token, error) {
+	tok.kind = tokenString
+	l.advance(1)
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.advance(1)
+			tok.value = sb.String()
+			return tok, nil
+		case '\n', '\r':
+			return tok, l.errorf("unterminated string")
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return tok, l.errorf("unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+6 > len(l.src) {
+					return tok, l.errorf("invalid unicode escape")
+				}
+				var r rune
+				if _, err := fmt.Sscanf(l.src[l.pos+2:l.pos+6], "%04x", &r); err != nil {
+					return tok, l.errorf("invalid unicode escape")
+				}
+				sb.WriteRune(r)
+				l.advance(4)
+			default:
+				return tok, l.errorf("invalid escape sequence \\%c", esc)
+			}
+			l.advance(2)
+		default:
+			sb.WriteByte(c)
+			l.advance(1)
+		}
+	}
+	return tok, l.errorf("unterminated string")
+}
+
+func (l *lexer) readBlockString(tok token) (token, error) {
+	tok.kind = tokenBlockString
+	l.advance(3)
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			l.advance(3)
+			tok.value = blockStringValue(sb.String())
+			return tok, nil
+		}
+		if strings.HasPrefix(l.src[l.pos:], `\"""`) {
+			sb.WriteString(`"""`)
+			l.advance(4)
+			continue
+		}
+		sb.WriteByte(l.src[l.pos])
+		l.advance(1)
+	}
+	return tok, l.errorf("unterminated block string")
+}
+
+// blockStringValue removes the common indentation and the leading and trailing blank lines of a block string
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	commonIndent := -1
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < len(line) && (commonIndent == -1 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
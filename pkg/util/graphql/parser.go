@@ -0,0 +1,571 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// parser builds a Schema from the type system definitions in an SDL document
+type parser struct {
+	lex        *lexer
+	tok        token
+	schema     *Schema
+	extensions []*Type
+	hasSchema  bool
+}
+
+// ParseSDL parses a GraphQL schema definition language document
+func ParseSDL(sdl []byte) (*Schema, error) {
+	p := &parser{
+		lex:    newLexer(string(sdl)),
+		schema: &Schema{Types: map[string]*Type{}},
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return nil, fmt.Errorf("graphql syntax error: the document does not contain any definitions")
+	}
+	for p.tok.kind != tokenEOF {
+		if err := p.parseDefinition(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.applyExtensions(); err != nil {
+		return nil, err
+	}
+	p.setDefaultRootTypes()
+	return p.schema, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graphql syntax error (%d:%d): %s", p.tok.line, p.tok.col, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) unexpected() error {
+	return p.errorf("unexpected %s", p.tok)
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+// skip advances past the token when it matches
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.errorf("expected %q, found %s", value, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name, found %s", p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) parseDescription() (string, error) {
+	if p.tok.kind != tokenString && p.tok.kind != tokenBlockString {
+		return "", nil
+	}
+	description := p.tok.value
+	return description, p.advance()
+}
+
+func (p *parser) parseDefinition() error {
+	description, err := p.parseDescription()
+	if err != nil {
+		return err
+	}
+
+	if p.peek(tokenPunctuator, "{") {
+		return p.errorf("executable definitions are not allowed in a schema document")
+	}
+	if p.tok.kind != tokenName {
+		return p.unexpected()
+	}
+
+	extend := false
+	if p.tok.value == "extend" {
+		extend = true
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+
+	switch p.tok.value {
+	case "schema":
+		return p.parseSchemaDefinition(description, extend)
+	case "scalar", "type", "interface", "union", "enum", "input":
+		return p.parseTypeDefinition(description, extend)
+	case "directive":
+		if extend {
+			return p.unexpected()
+		}
+		return p.parseDirectiveDefinition()
+	case "query", "mutation", "subscription", "fragment":
+		return p.errorf("executable definitions are not allowed in a schema document")
+	}
+	return p.unexpected()
+}
+
+func (p *parser) parseSchemaDefinition(description string, extend bool) error {
+	if !extend {
+		if p.hasSchema {
+			return p.errorf("must provide only one schema definition")
+		}
+		p.hasSchema = true
+		p.schema.Description = description
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if err := p.parseDirectives(); err != nil {
+		return err
+	}
+	if !p.peek(tokenPunctuator, "{") && extend {
+		return nil
+	}
+	if err := p.expect(tokenPunctuator, "{"); err != nil {
+		return err
+	}
+	for {
+		if ok, err := p.skip(tokenPunctuator, "}"); ok || err != nil {
+			return err
+		}
+		operation, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(tokenPunctuator, ":"); err != nil {
+			return err
+		}
+		typeName, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		switch operation {
+		case OperationQuery:
+			p.schema.QueryType = typeName
+		case OperationMutation:
+			p.schema.MutationType = typeName
+		case OperationSubscription:
+			p.schema.SubscriptionType = typeName
+		default:
+			return p.errorf("unknown operation type %q", operation)
+		}
+	}
+}
+
+func (p *parser) parseTypeDefinition(description string, extend bool) error {
+	keyword := p.tok.value
+	if err := p.advance(); err != nil {
+		return err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+	t := &Type{Name: name, Description: description}
+
+	switch keyword {
+	case "scalar":
+		t.Kind = KindScalar
+		err = p.parseDirectives()
+	case "type", "interface":
+		t.Kind = KindObject
+		if keyword == "interface" {
+			t.Kind = KindInterface
+		}
+		if t.Interfaces, err = p.parseImplements(); err != nil {
+			return err
+		}
+		if err = p.parseDirectives(); err != nil {
+			return err
+		}
+		t.Fields, err = p.parseFields("{", "}", true)
+	case "union":
+		t.Kind = KindUnion
+		if err = p.parseDirectives(); err != nil {
+			return err
+		}
+		t.PossibleTypes, err = p.parseUnionMembers()
+	case "enum":
+		t.Kind = KindEnum
+		if err = p.parseDirectives(); err != nil {
+			return err
+		}
+		t.EnumValues, err = p.parseEnumValues()
+	case "input":
+		t.Kind = KindInputObject
+		if err = p.parseDirectives(); err != nil {
+			return err
+		}
+		t.Fields, err = p.parseFields("{", "}", false)
+	}
+	if err != nil {
+		return err
+	}
+
+	if extend {
+		p.extensions = append(p.extensions, t)
+		return nil
+	}
+	if _, found := p.schema.Types[name]; found {
+		return fmt.Errorf("graphql schema error: there can be only one type named %q", name)
+	}
+	p.schema.Types[name] = t
+	return nil
+}
+
+func (p *parser) parseImplements() ([]string, error) {
+	interfaces := []string{}
+	if ok, err := p.skip(tokenName, "implements"); !ok || err != nil {
+		return interfaces, err
+	}
+	if _, err := p.skip(tokenPunctuator, "&"); err != nil {
+		return nil, err
+	}
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, name)
+		if ok, err := p.skip(tokenPunctuator, "&"); err != nil {
+			return nil, err
+		} else if !ok && (p.tok.kind != tokenName || p.peekDefinitionStart()) {
+			// legacy SDL separated interfaces with spaces only
+			return interfaces, nil
+		}
+	}
+}
+
+// peekDefinitionStart returns true when the current name token starts the next definition
+func (p *parser) peekDefinitionStart() bool {
+	switch p.tok.value {
+	case "schema", "scalar", "type", "interface", "union", "enum", "input", "directive", "extend":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseUnionMembers() ([]string, error) {
+	members := []string{}
+	if ok, err := p.skip(tokenPunctuator, "="); !ok || err != nil {
+		return members, err
+	}
+	if _, err := p.skip(tokenPunctuator, "|"); err != nil {
+		return nil, err
+	}
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, name)
+		if ok, err := p.skip(tokenPunctuator, "|"); !ok || err != nil {
+			return members, err
+		}
+	}
+}
+
+func (p *parser) parseEnumValues() ([]string, error) {
+	values := []string{}
+	if ok, err := p.skip(tokenPunctuator, "{"); !ok || err != nil {
+		return values, err
+	}
+	for {
+		if ok, err := p.skip(tokenPunctuator, "}"); ok || err != nil {
+			return values, err
+		}
+		if _, err := p.parseDescription(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if name == "true" || name == "false" || name == "null" {
+			return nil, p.errorf("enum value cannot be %q", name)
+		}
+		if err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		values = append(values, name)
+	}
+}
+
+// parseFields parses field definitions, or input value definitions when arguments are not allowed
+func (p *parser) parseFields(open, close string, withArguments bool) ([]*Field, error) {
+	fields := []*Field{}
+	if ok, err := p.skip(tokenPunctuator, open); !ok || err != nil {
+		return fields, err
+	}
+	for {
+		if ok, err := p.skip(tokenPunctuator, close); ok || err != nil {
+			return fields, err
+		}
+		field, err := p.parseField(withArguments)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+}
+
+func (p *parser) parseField(withArguments bool) (*Field, error) {
+	description, err := p.parseDescription()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	field := &Field{Name: name, Description: description}
+	if withArguments {
+		if field.Arguments, err = p.parseFields("(", ")", false); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokenPunctuator, ":"); err != nil {
+		return nil, err
+	}
+	if field.Type, err = p.parseTypeRef(); err != nil {
+		return nil, err
+	}
+	if !withArguments {
+		// default value of an input value
+		if ok, err := p.skip(tokenPunctuator, "="); err != nil {
+			return nil, err
+		} else if ok {
+			if err := p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	field.IsDeprecated, field.DeprecationReason, err = p.parseFieldDirectives()
+	return field, err
+}
+
+func (p *parser) parseTypeRef() (*TypeRef, error) {
+	ref := &TypeRef{}
+	if ok, err := p.skip(tokenPunctuator, "["); err != nil {
+		return nil, err
+	} else if ok {
+		ofType, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunctuator, "]"); err != nil {
+			return nil, err
+		}
+		ref.OfType = ofType
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		ref.Name = name
+	}
+	ok, err := p.skip(tokenPunctuator, "!")
+	ref.NonNull = ok
+	return ref, err
+}
+
+func (p *parser) parseDirectiveDefinition() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if err := p.expect(tokenPunctuator, "@"); err != nil {
+		return err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+	if _, err := p.parseFields("(", ")", false); err != nil {
+		return err
+	}
+	if _, err := p.skip(tokenName, "repeatable"); err != nil {
+		return err
+	}
+	if err := p.expect(tokenName, "on"); err != nil {
+		return err
+	}
+	if _, err := p.skip(tokenPunctuator, "|"); err != nil {
+		return err
+	}
+	for {
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if ok, err := p.skip(tokenPunctuator, "|"); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+	}
+	p.schema.Directives = append(p.schema.Directives, name)
+	return nil
+}
+
+// parseFieldDirectives parses the directives on a field, returning the @deprecated details
+func (p *parser) parseFieldDirectives() (bool, string, error) {
+	deprecated, reason := false, ""
+	for p.peek(tokenPunctuator, "@") {
+		if err := p.advance(); err != nil {
+			return false, "", err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return false, "", err
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return false, "", err
+		}
+		if name == "deprecated" {
+			deprecated = true
+			reason = args["reason"]
+			if reason == "" {
+				reason = "No longer supported"
+			}
+		}
+	}
+	return deprecated, reason, nil
+}
+
+func (p *parser) parseDirectives() error {
+	_, _, err := p.parseFieldDirectives()
+	return err
+}
+
+// parseArguments parses the arguments of a directive, returning the string arguments
+func (p *parser) parseArguments() (map[string]string, error) {
+	args := map[string]string{}
+	if ok, err := p.skip(tokenPunctuator, "("); !ok || err != nil {
+		return args, err
+	}
+	for {
+		if ok, err := p.skip(tokenPunctuator, ")"); ok || err != nil {
+			return args, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenString || p.tok.kind == tokenBlockString {
+			args[name] = p.tok.value
+		}
+		if err := p.parseValue(false); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseValue validates and skips a value, variables are not allowed in const values
+func (p *parser) parseValue(isConst bool) error {
+	switch p.tok.kind {
+	case tokenInt, tokenFloat, tokenString, tokenBlockString, tokenName:
+		return p.advance()
+	case tokenPunctuator:
+		switch p.tok.value {
+		case "$":
+			if isConst {
+				return p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return err
+			}
+			_, err := p.expectName()
+			return err
+		case "[":
+			if err := p.advance(); err != nil {
+				return err
+			}
+			for {
+				if ok, err := p.skip(tokenPunctuator, "]"); ok || err != nil {
+					return err
+				}
+				if err := p.parseValue(isConst); err != nil {
+					return err
+				}
+			}
+		case "{":
+			if err := p.advance(); err != nil {
+				return err
+			}
+			for {
+				if ok, err := p.skip(tokenPunctuator, "}"); ok || err != nil {
+					return err
+				}
+				if _, err := p.expectName(); err != nil {
+					return err
+				}
+				if err := p.expect(tokenPunctuator, ":"); err != nil {
+					return err
+				}
+				if err := p.parseValue(isConst); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return p.unexpected()
+}
+
+// applyExtensions merges the type extensions into the types they extend
+func (p *parser) applyExtensions() error {
+	for _, ext := range p.extensions {
+		t, found := p.schema.Types[ext.Name]
+		if !found {
+			return fmt.Errorf("graphql schema error: cannot extend type %q because it is not defined", ext.Name)
+		}
+		if t.Kind != ext.Kind {
+			return fmt.Errorf("graphql schema error: cannot extend type %q of kind %s as %s", ext.Name, t.Kind, ext.Kind)
+		}
+		t.Fields = append(t.Fields, ext.Fields...)
+		t.Interfaces = append(t.Interfaces, ext.Interfaces...)
+		t.PossibleTypes = append(t.PossibleTypes, ext.PossibleTypes...)
+		t.EnumValues = append(t.EnumValues, ext.EnumValues...)
+	}
+	return nil
+}
+
+// setDefaultRootTypes uses the conventional root type names when there is no schema definition
+func (p *parser) setDefaultRootTypes() {
+	if p.hasSchema {
+		return
+	}
+	if _, ok := p.schema.Types["Query"]; ok && p.schema.QueryType == "" {
+		p.schema.QueryType = "Query"
+	}
+	if _, ok := p.schema.Types["Mutation"]; ok && p.schema.MutationType == "" {
+		p.schema.MutationType = "Mutation"
+	}
+	if _, ok := p.schema.Types["Subscription"]; ok && p.schema.SubscriptionType == "" {
+		p.schema.SubscriptionType = "Subscription"
+	}
+}
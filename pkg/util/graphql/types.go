@@ -0,0 +1,119 @@
+package graphql
+
+import "sort"
+
+// Type kinds, matching the introspection __TypeKind values
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+)
+
+// Root operation types
+const (
+	OperationQuery        = "query"
+	OperationMutation     = "mutation"
+	OperationSubscription = "subscription"
+)
+
+var builtInScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// Schema is the type system of a GraphQL service, parsed from SDL or an introspection result
+type Schema struct {
+	Description      string
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*Type
+	Directives       []string
+}
+
+// Type is a named type in the schema
+type Type struct {
+	Kind          string
+	Name          string
+	Description   string
+	Fields        []*Field
+	Interfaces    []string
+	PossibleTypes []string
+	EnumValues    []string
+}
+
+// Field is a field of an object, interface or input object type
+type Field struct {
+	Name              string
+	Description       string
+	Type              *TypeRef
+	Arguments         []*Field
+	IsDeprecated      bool
+	DeprecationReason string
+}
+
+// TypeRef is a reference to a named type, possibly wrapped in lists and non null modifiers
+type TypeRef struct {
+	Name    string
+	NonNull bool
+	OfType  *TypeRef
+}
+
+// NamedType returns the name of the innermost named type
+func (t *TypeRef) NamedType() string {
+	if t == nil {
+		return ""
+	}
+	if t.OfType != nil {
+		return t.OfType.NamedType()
+	}
+	return t.Name
+}
+
+// String returns the type reference as it would be written in SDL, e.g. [User!]!
+func (t *TypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	s := t.Name
+	if t.OfType != nil {
+		s = "[" + t.OfType.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// RootOperations returns the fields of the root operation type for the operation, query, mutation or subscription
+func (s *Schema) RootOperations(operation string) []*Field {
+	typeName := ""
+	switch operation {
+	case OperationQuery:
+		typeName = s.QueryType
+	case OperationMutation:
+		typeName = s.MutationType
+	case OperationSubscription:
+		typeName = s.SubscriptionType
+	}
+	if t, ok := s.Types[typeName]; ok && typeName != "" {
+		return t.Fields
+	}
+	return nil
+}
+
+// TypeNames returns the sorted names of all types in the schema
+func (s *Schema) TypeNames() []string {
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks the schema is complete, all referenced types are defined and the root operation types are objects
+func (s *Schema) Validate() error {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if s.QueryType == "" {
+		addProblem("query root operation type must be provided")
+	}
+	for operation, typeName := range map[string]string{
+		OperationQuery:        s.QueryType,
+		OperationMutation:     s.MutationType,
+		OperationSubscription: s.SubscriptionType,
+	} {
+		if typeName == "" {
+			continue
+		}
+		t, found := s.Types[typeName]
+		if !found {
+			addProblem("%s root type %q is not defined", operation, typeName)
+			continue
+		}
+		if t.Kind != KindObject {
+			addProblem("%s root type %q must be an object type", operation, typeName)
+		}
+	}
+
+	for _, name := range s.TypeNames() {
+		t := s.Types[name]
+		seen := map[string]bool{}
+		for _, f := range t.Fields {
+			if seen[f.Name] {
+				addProblem("field %s.%s can only be defined once", name, f.Name)
+			}
+			seen[f.Name] = true
+			if !s.isDefined(f.Type.NamedType()) {
+				addProblem("unknown type %q for field %s.%s", f.Type.NamedType(), name, f.Name)
+			}
+			for _, a := range f.Arguments {
+				if !s.isDefined(a.Type.NamedType()) {
+					addProblem("unknown type %q for argument %s.%s(%s)", a.Type.NamedType(), name, f.Name, a.Name)
+				}
+			}
+		}
+		if (t.Kind == KindObject || t.Kind == KindInterface || t.Kind == KindInputObject) && len(t.Fields) == 0 {
+			addProblem("type %q must define one or more fields", name)
+		}
+		for _, i := range t.Interfaces {
+			if it, found := s.Types[i]; !found || it.Kind != KindInterface {
+				addProblem("type %q can only implement defined interface types, found %q", name, i)
+			}
+		}
+		for _, m := range t.PossibleTypes {
+			if mt, found := s.Types[m]; !found || (t.Kind == KindUnion && mt.Kind != KindObject) {
+				addProblem("union %q can only include defined object types, found %q", name, m)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid graphql schema: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Schema) isDefined(typeName string) bool {
+	if builtInScalars[typeName] {
+		return true
+	}
+	_, found := s.Types[typeName]
+	return found
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Axway/agent-sdk/pkg/util/log"
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
 )
 
 // ParseOAS2 converts a JSON spec into an OpenAPI2 object.
@@ -33,7 +34,7 @@ func ParseOAS2(spec []byte) (*openapi2.T, error) {
 	return swaggerObj, nil
 }
 
-// ParseOAS3 converts a JSON or YAML spec into an OpenAPI3 object.
+// ParseOAS3 converts a JSON or YAML spec into an OpenAPI3 object. Both 3.0 and 3.1 specs are supported.
 func ParseOAS3(spec []byte) (*openapi3.T, error) {
 	oas3Obj, err := openapi3.NewLoader().LoadFromData(spec)
 	if err != nil {
@@ -43,7 +44,16 @@ func ParseOAS3(spec []byte) (*openapi3.T, error) {
 	if !strings.Contains(oas3Obj.OpenAPI, "3.") {
 		return nil, fmt.Errorf("%s", oasParseError("3", ("'openapi' key is invalid.")))
 	}
-	if oas3Obj.Paths == nil {
+	if IsOAS31(oas3Obj) {
+		// 3.1 only requires one of paths, components or webhooks
+		if oas3Obj.Paths == nil && oas3Obj.Components == nil && len(oas3Obj.Webhooks) == 0 {
+			return nil, fmt.Errorf("%s", oasParseError("3.1", "one of 'paths', 'components' or 'webhooks' keys must be present."))
+		}
+		if oas3Obj.Paths == nil {
+			// an empty paths object is valid in 3.1 and is not serialized as null
+			oas3Obj.Paths = openapi3.NewPaths()
+		}
+	} else if oas3Obj.Paths == nil {
 		return nil, fmt.Errorf("%s", oasParseError("3", "'paths' key not found."))
 	}
 	if oas3Obj.Info == nil {
@@ -55,6 +65,11 @@ func ParseOAS3(spec []byte) (*openapi3.T, error) {
 	return oas3Obj, nil
 }
 
+// IsOAS31 returns true when the OpenAPI3 object is a 3.1, or later, spec.
+func IsOAS31(spec *openapi3.T) bool {
+	return spec.IsOpenAPI31OrLater()
+}
+
 // SetOAS2HostDetails Updates the Host, BasePath, and Schemes fields on an OpenAPI2 object.
 func SetOAS2HostDetails(spec *openapi2.T, endpointURL string) error {
 	endpoint, err := url.Parse(endpointURL)
@@ -90,6 +105,56 @@ func SetOAS3Servers(hosts []string, spec *openapi3.T) {
 	}
 }
 
+// RestoreRefSiblings copies the keywords defined alongside a $ref in the original spec, allowed since OAS 3.1,
+// back into the marshalled spec as the OpenAPI3 object only serializes the $ref itself.
+func RestoreRefSiblings(original, spec []byte) ([]byte, error) {
+	originalDef := map[string]interface{}{}
+	if err := yaml.Unmarshal(original, &originalDef); err != nil {
+		return nil, err
+	}
+	specDef := map[string]interface{}{}
+	if err := json.Unmarshal(spec, &specDef); err != nil {
+		return nil, err
+	}
+	if !restoreRefSiblings(originalDef, specDef) {
+		return spec, nil
+	}
+	return json.Marshal(specDef)
+}
+
+func restoreRefSiblings(original, updated interface{}) bool {
+	changed := false
+	switch o := original.(type) {
+	case map[string]interface{}:
+		u, ok := updated.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if ref, found := o["$ref"]; found && u["$ref"] == ref {
+			for k, v := range o {
+				if _, exists := u[k]; !exists && !strings.HasPrefix(k, "x-") {
+					u[k] = v
+					changed = true
+				}
+			}
+		}
+		for k, v := range o {
+			if uv, found := u[k]; found {
+				changed = restoreRefSiblings(v, uv) || changed
+			}
+		}
+	case []interface{}:
+		u, ok := updated.([]interface{})
+		if !ok || len(u) != len(o) {
+			return false
+		}
+		for i := range o {
+			changed = restoreRefSiblings(o[i], u[i]) || changed
+		}
+	}
+	return changed
+}
+
 func oasParseError(version string, msg string) string {
 	return fmt.Sprintf("invalid openapi %s specification. %s", version, msg)
 }
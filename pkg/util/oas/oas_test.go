@@ -1,6 +1,7 @@
 package oas
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,6 +103,61 @@ func TestParseOAS3(t *testing.T) {
 				]
 			}`,
 		},
+		{
+			name:     "Should parse the OAS3.1 spec without 'paths' when 'webhooks' are defined",
+			hasError: false,
+			spec: `{
+				"openapi": "3.1.0",
+				"info": {
+					"title": "petstore31",
+					"version": "1.0.0"
+				},
+				"webhooks": {
+					"newPet": {
+						"post": {
+							"responses": {
+								"200": {
+									"description": "ok"
+								}
+							}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:     "Should parse the OAS3.1 spec with type arrays and const",
+			hasError: false,
+			spec: `{
+				"openapi": "3.1.0",
+				"info": {
+					"title": "petstore31",
+					"version": "1.0.0"
+				},
+				"components": {
+					"schemas": {
+						"Pet": {
+							"type": "object",
+							"properties": {
+								"name": {"type": ["string", "null"]},
+								"kind": {"const": "pet"}
+							}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:     "Should fail to parse the OAS3.1 spec without 'paths', 'components' or 'webhooks'",
+			hasError: true,
+			spec: `{
+				"openapi": "3.1.0",
+				"info": {
+					"title": "petstore31",
+					"version": "1.0.0"
+				}
+			}`,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -223,3 +279,53 @@ func TestSetOAS2HostDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestParseOAS31Paths(t *testing.T) {
+	obj, err := ParseOAS3([]byte(`{"openapi": "3.1.0", "info": {"title": "petstore31", "version": "1.0.0"}, "components": {}}`))
+	assert.Nil(t, err)
+	assert.True(t, IsOAS31(obj))
+	assert.NotNil(t, obj.Paths)
+
+	SetOAS3Servers([]string{"https://abc.com"}, obj)
+	data, err := json.Marshal(obj)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"paths":{}`)
+	assert.Contains(t, string(data), `"servers":[{"url":"https://abc.com"}]`)
+
+	obj, err = ParseOAS3([]byte(petstore3Json))
+	assert.Nil(t, err)
+	assert.False(t, IsOAS31(obj))
+}
+
+func TestRestoreRefSiblings(t *testing.T) {
+	original := []byte(`
+openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      properties:
+        status:
+          $ref: '#/components/schemas/Status'
+          description: pet status
+          x-internal: true
+        tags:
+          type: array
+          items:
+            - $ref: '#/components/schemas/Tag'
+              deprecated: true
+`)
+	spec := []byte(`{"openapi":"3.1.0","components":{"schemas":{"Pet":{"properties":{"status":{"$ref":"#/components/schemas/Status"},"tags":{"type":"array","items":[{"$ref":"#/components/schemas/Tag"}]}}}}}}`)
+
+	restored, err := RestoreRefSiblings(original, spec)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"openapi":"3.1.0","components":{"schemas":{"Pet":{"properties":{
+		"status":{"$ref":"#/components/schemas/Status","description":"pet status"},
+		"tags":{"type":"array","items":[{"$ref":"#/components/schemas/Tag","deprecated":true}]}}}}}}`, string(restored))
+
+	unchanged, err := RestoreRefSiblings([]byte(petstore3Json), []byte(petstore3Json))
+	assert.Nil(t, err)
+	assert.Equal(t, petstore3Json, string(unchanged))
+
+	_, err = RestoreRefSiblings(original, []byte("not json"))
+	assert.NotNil(t, err)
+}
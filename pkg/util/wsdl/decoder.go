@@ -16,5 +16,6 @@ func Unmarshal(bytes []byte) (*Definitions, error) {
 	if err != nil {
 		return nil, err
 	}
+	d.raw = bytes
 	return &d, nil
 }
@@ -0,0 +1,164 @@
+package wsdl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ResolveImports merges the WSDL documents and XML schemas imported by the definitions into the definitions.
+// The imports, and the imports of the imported documents, are read from the bundle of files keyed by their path
+// relative to the root document. Remote locations that are not in the bundle are skipped, as they are not fetched.
+// A document is only merged once, so imports in a cycle are allowed.
+func ResolveImports(def *Definitions, bundle map[string][]byte) error {
+	r := &importResolver{
+		def:     def,
+		files:   map[string][]byte{},
+		visited: map[string]bool{},
+	}
+	for name, data := range bundle {
+		r.files[cleanLocation(name)] = data
+		// the root document may be in the bundle, for the documents that import it
+		if bytes.Equal(data, def.raw) {
+			r.visited[cleanLocation(name)] = true
+		}
+	}
+
+	for _, schema := range append([]*Schema{}, def.Schemas...) {
+		if err := r.resolveSchema("", schema); err != nil {
+			return err
+		}
+	}
+	if err := r.resolveDefinitions("", def.Imports); err != nil {
+		return err
+	}
+	// the root document may only import its services, schemas or bindings
+	def.setFirstEntries()
+	return nil
+}
+
+type importResolver struct {
+	def     *Definitions
+	files   map[string][]byte
+	visited map[string]bool
+}
+
+// load returns the file at the location, relative to the importing file, and its key in the bundle
+func (r *importResolver) load(base, location string) ([]byte, string, error) {
+	key := location
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" {
+		key = cleanLocation(path.Join(path.Dir(base), location))
+	}
+	if r.visited[key] {
+		return nil, key, nil
+	}
+	data, found := r.files[key]
+	if !found {
+		if err == nil && u.Scheme != "" {
+			return nil, key, nil
+		}
+		return nil, key, fmt.Errorf("could not resolve the import %s: the file %s is not in the bundle", location, key)
+	}
+	r.visited[key] = true
+	return data, key, nil
+}
+
+func (r *importResolver) resolveDefinitions(base string, imports []*Import) error {
+	for _, imp := range imports {
+		if imp.Location == "" {
+			continue
+		}
+		data, key, err := r.load(base, imp.Location)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+
+		// a WSDL import may also refer to an XML schema
+		if rootElement(data) == "schema" {
+			if err := r.addSchema(key, data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		imported, err := Unmarshal(data)
+		if err != nil {
+			return fmt.Errorf("could not parse the import %s: %s", imp.Location, err)
+		}
+		r.def.Messages = append(r.def.Messages, imported.Messages...)
+		r.def.PortType = append(r.def.PortType, imported.PortType...)
+		r.def.Bindings = append(r.def.Bindings, imported.Bindings...)
+		r.def.Services = append(r.def.Services, imported.Services...)
+		r.def.Schemas = append(r.def.Schemas, imported.Schemas...)
+		for _, schema := range imported.Schemas {
+			if err := r.resolveSchema(key, schema); err != nil {
+				return err
+			}
+		}
+		if err := r.resolveDefinitions(key, imported.Imports); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *importResolver) resolveSchema(base string, schema *Schema) error {
+	locations := []string{}
+	for _, imp := range schema.Imports {
+		locations = append(locations, imp.Location)
+	}
+	for _, inc := range schema.Includes {
+		locations = append(locations, inc.Location)
+	}
+
+	for _, location := range locations {
+		if location == "" {
+			continue
+		}
+		data, key, err := r.load(base, location)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+		if err := r.addSchema(key, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *importResolver) addSchema(key string, data []byte) error {
+	schema := &Schema{}
+	if err := xml.Unmarshal(data, schema); err != nil {
+		return fmt.Errorf("could not parse the schema %s: %s", key, err)
+	}
+	r.def.Schemas = append(r.def.Schemas, schema)
+	return r.resolveSchema(key, schema)
+}
+
+// rootElement returns the local name of the root element of the xml document
+func rootElement(data []byte) string {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+func cleanLocation(location string) string {
+	return strings.TrimPrefix(path.Clean("/"+location), "/")
+}
@@ -1,6 +1,23 @@
 package wsdl
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// WSDL and SOAP versions
+const (
+	WSDL11 = "1.1"
+	WSDL20 = "2.0"
+	SOAP11 = "1.1"
+	SOAP12 = "1.2"
+)
+
+// Namespaces of the SOAP binding extensions
+const (
+	soap11Namespace = "http://schemas.xmlsoap.org/wsdl/soap/"
+	soap12Namespace = "http://schemas.xmlsoap.org/wsdl/soap12/"
+)
 
 // Definitions is the root element of a WSDL document.
 type Definitions struct {
@@ -8,19 +25,28 @@ type Definitions struct {
 	Name            string            `xml:"name,attr"`
 	TargetNamespace string            `xml:"targetNamespace,attr"`
 	Namespaces      map[string]string `xml:"-"`
+	Version         string            `xml:"-"`
 	SOAPEnv         string            `xml:"SOAP-ENV,attr"`
 	SOAPEnc         string            `xml:"SOAP-ENC,attr"`
-	Service         Service           `xml:"service"`
-	Imports         []*Import         `xml:"import"`
-	Schema          Schema            `xml:"types>schema"`
-	Messages        []*Message        `xml:"message"`
-	PortType        []*PortType       `xml:"portType"`
-	Binding         Binding           `xml:"binding"`
+	Doc             string            `xml:"documentation"`
+	// Deprecated: the first of the Services, use Services
+	Service  Service    `xml:"-"`
+	Services []*Service `xml:"service"`
+	Imports  []*Import  `xml:"import"`
+	// Deprecated: the first of the Schemas, use Schemas
+	Schema   Schema      `xml:"-"`
+	Schemas  []*Schema   `xml:"types>schema"`
+	Messages []*Message  `xml:"message"`
+	PortType []*PortType `xml:"portType"`
+	// Deprecated: the first of the Bindings, use Bindings
+	Binding  Binding    `xml:"-"`
+	Bindings []*Binding `xml:"binding"`
+	raw      []byte
 }
 
 type definitionDup Definitions
 
-// UnmarshalXML implements the xml.Unmarshaler interface.
+// UnmarshalXML implements the xml.Unmarshaler interface, decoding both WSDL 1.1 definitions and WSDL 2.0 description documents.
 func (def *Definitions) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for _, attr := range start.Attr {
 		if attr.Name.Space == "xmlns" {
@@ -30,11 +56,87 @@ func (def *Definitions) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 			def.Namespaces[attr.Name.Local] = attr.Value
 		}
 	}
-	return d.DecodeElement((*definitionDup)(def), &start)
+	if start.Name.Local == "description" {
+		if err := def.decodeDescription(d, start); err != nil {
+			return err
+		}
+		def.setFirstEntries()
+		return nil
+	}
+	if err := d.DecodeElement((*definitionDup)(def), &start); err != nil {
+		return err
+	}
+	def.Version = WSDL11
+	for _, b := range def.Bindings {
+		if b.BindingType == nil {
+			continue
+		}
+		// the space is the prefix itself when the document does not declare the namespace
+		switch b.BindingType.XMLName.Space {
+		case soap11Namespace, "soap":
+			b.SOAPVersion = SOAP11
+		case soap12Namespace, "soap12":
+			b.SOAPVersion = SOAP12
+		}
+	}
+	def.setFirstEntries()
+	return nil
+}
+
+// setFirstEntries sets the deprecated Service, Schema and Binding fields to the first of the services, schemas and bindings
+func (def *Definitions) setFirstEntries() {
+	if len(def.Services) > 0 {
+		def.Service = *def.Services[0]
+	}
+	if len(def.Schemas) > 0 {
+		def.Schema = *def.Schemas[0]
+	}
+	if len(def.Bindings) > 0 {
+		def.Binding = *def.Bindings[0]
+	}
+}
+
+// GetBinding returns the binding with the name, ignoring the namespace prefix of the qualified name
+func (def *Definitions) GetBinding(qname string) *Binding {
+	for _, b := range def.Bindings {
+		if b.Name == LocalName(qname) {
+			return b
+		}
+	}
+	return nil
+}
+
+// GetPortType returns the port type with the name, ignoring the namespace prefix of the qualified name
+func (def *Definitions) GetPortType(qname string) *PortType {
+	for _, pt := range def.PortType {
+		if pt.Name == LocalName(qname) {
+			return pt
+		}
+	}
+	return nil
+}
+
+// GetMessage returns the message with the name, ignoring the namespace prefix of the qualified name
+func (def *Definitions) GetMessage(qname string) *Message {
+	for _, m := range def.Messages {
+		if m.Name == LocalName(qname) {
+			return m
+		}
+	}
+	return nil
+}
+
+// LocalName returns the name of a qualified name, without its namespace prefix
+func LocalName(qname string) string {
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
 }
 
 // Service defines a WSDL service and with a location, like an HTTP server.
 type Service struct {
+	Name  string  `xml:"name,attr"`
 	Doc   string  `xml:"documentation"`
 	Ports []*Port `xml:"port"`
 }
@@ -269,10 +371,12 @@ type Binding struct {
 	Type        string              `xml:"type,attr"`
 	BindingType *BindingType        `xml:"binding"`
 	Operations  []*BindingOperation `xml:"operation"`
+	SOAPVersion string              `xml:"-"` // 1.1 or 1.2, empty when the binding is not a SOAP binding
 }
 
 // BindingType contains additional meta data on how to implement the binding.
 type BindingType struct {
+	XMLName   xml.Name
 	Style     string `xml:"style,attr"`
 	Transport string `xml:"transport,attr"`
 }
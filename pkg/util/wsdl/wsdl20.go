@@ -0,0 +1,133 @@
+package wsdl
+
+import (
+	"encoding/xml"
+)
+
+const wsdl20SOAPBindingType = "http://www.w3.org/ns/wsdl/soap"
+
+// description is the root element of a WSDL 2.0 document.
+type description struct {
+	TargetNamespace string         `xml:"targetNamespace,attr"`
+	Doc             string         `xml:"documentation"`
+	Imports         []*Import      `xml:"import"`
+	Includes        []*include20   `xml:"include"`
+	Schemas         []*Schema      `xml:"types>schema"`
+	Interfaces      []*interface20 `xml:"interface"`
+	Bindings        []*binding20   `xml:"binding"`
+	Services        []*service20   `xml:"service"`
+}
+
+type include20 struct {
+	Location string `xml:"location,attr"`
+}
+
+type interface20 struct {
+	Name       string         `xml:"name,attr"`
+	Operations []*operation20 `xml:"operation"`
+}
+
+type operation20 struct {
+	Name   string     `xml:"name,attr"`
+	Doc    string     `xml:"documentation"`
+	Input  *message20 `xml:"input"`
+	Output *message20 `xml:"output"`
+}
+
+type message20 struct {
+	Element string `xml:"element,attr"`
+}
+
+type binding20 struct {
+	Name        string                `xml:"name,attr"`
+	Interface   string                `xml:"interface,attr"`
+	Type        string                `xml:"type,attr"`
+	SOAPVersion string                `xml:"http://www.w3.org/ns/wsdl/soap version,attr"`
+	Protocol    string                `xml:"http://www.w3.org/ns/wsdl/soap protocol,attr"`
+	Operations  []*bindingOperation20 `xml:"operation"`
+}
+
+type bindingOperation20 struct {
+	Ref    string `xml:"ref,attr"`
+	Action string `xml:"http://www.w3.org/ns/wsdl/soap action,attr"`
+}
+
+type service20 struct {
+	Name      string        `xml:"name,attr"`
+	Doc       string        `xml:"documentation"`
+	Endpoints []*endpoint20 `xml:"endpoint"`
+}
+
+type endpoint20 struct {
+	Name    string `xml:"name,attr"`
+	Binding string `xml:"binding,attr"`
+	Address string `xml:"address,attr"`
+}
+
+// decodeDescription decodes a WSDL 2.0 description into the WSDL 1.1 model, interfaces are decoded as port types and endpoints as ports
+func (def *Definitions) decodeDescription(d *xml.Decoder, start xml.StartElement) error {
+	desc := description{}
+	if err := d.DecodeElement(&desc, &start); err != nil {
+		return err
+	}
+
+	def.XMLName = start.Name
+	def.Version = WSDL20
+	def.TargetNamespace = desc.TargetNamespace
+	def.Doc = desc.Doc
+	def.Imports = desc.Imports
+	for _, i := range desc.Includes {
+		def.Imports = append(def.Imports, &Import{Location: i.Location})
+	}
+	def.Schemas = desc.Schemas
+
+	for _, i := range desc.Interfaces {
+		pt := &PortType{Name: i.Name}
+		for _, op := range i.Operations {
+			operation := &Operation{Name: op.Name, Doc: op.Doc}
+			if op.Input != nil {
+				operation.Input = &IO{Message: op.Input.Element}
+			}
+			if op.Output != nil {
+				operation.Output = &IO{Message: op.Output.Element}
+			}
+			pt.Operations = append(pt.Operations, operation)
+		}
+		def.PortType = append(def.PortType, pt)
+	}
+
+	for _, b := range desc.Bindings {
+		binding := &Binding{
+			Name:        b.Name,
+			Type:        b.Interface,
+			BindingType: &BindingType{Transport: b.Protocol},
+		}
+		// the soap version of a WSDL 2.0 SOAP binding defaults to 1.2
+		if b.Type == wsdl20SOAPBindingType {
+			binding.SOAPVersion = SOAP12
+			if b.SOAPVersion != "" {
+				binding.SOAPVersion = b.SOAPVersion
+			}
+		}
+		for _, op := range b.Operations {
+			binding.Operations = append(binding.Operations, &BindingOperation{
+				Name:      LocalName(op.Ref),
+				Operation: SOAP12Operation{Action: op.Action},
+			})
+		}
+		def.Bindings = append(def.Bindings, binding)
+	}
+
+	for _, s := range desc.Services {
+		service := &Service{Name: s.Name, Doc: s.Doc}
+		for _, e := range s.Endpoints {
+			service.Ports = append(service.Ports, &Port{
+				Name:    e.Name,
+				Binding: e.Binding,
+				Address: Address{Location: e.Address},
+			})
+		}
+		def.Services = append(def.Services, service)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package wsdl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rootWSDL = `<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/" xmlns:tns="urn:pets" targetNamespace="urn:pets">
+  <wsdl:documentation>Pets</wsdl:documentation>
+  <wsdl:import namespace="urn:pets" location="wsdl/bindings.wsdl"/>
+  <wsdl:types>
+    <xsd:schema targetNamespace="urn:pets">
+      <xsd:import namespace="urn:pets" schemaLocation="xsd/pet.xsd"/>
+    </xsd:schema>
+  </wsdl:types>
+  <wsdl:service name="Pets">
+    <wsdl:port name="PetSoap12" binding="tns:PetSoap12">
+      <soap12:address location="https://pets.example.com/soap12"/>
+    </wsdl:port>
+  </wsdl:service>
+  <wsdl:service name="PetAdmin">
+    <wsdl:port name="PetSoap" binding="tns:PetSoap">
+      <soap12:address location="https://pets.example.com/soap"/>
+    </wsdl:port>
+  </wsdl:service>
+</wsdl:definitions>`
+
+const bindingsWSDL = `<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/" xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/" xmlns:tns="urn:pets" targetNamespace="urn:pets">
+  <wsdl:import namespace="urn:pets" location="../root.wsdl"/>
+  <wsdl:import namespace="urn:pets" location="../xsd/types.xsd"/>
+  <wsdl:binding name="PetSoap" type="tns:PetPortType">
+    <soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+  </wsdl:binding>
+  <wsdl:binding name="PetSoap12" type="tns:PetPortType">
+    <soap12:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+  </wsdl:binding>
+</wsdl:definitions>`
+
+const petXSD = `<xsd:schema xmlns:xsd="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:pets">
+  <xsd:include schemaLocation="types.xsd"/>
+  <xsd:element name="Pet" type="xsd:string"/>
+</xsd:schema>`
+
+const typesXSD = `<xsd:schema xmlns:xsd="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:pets">
+  <xsd:include schemaLocation="pet.xsd"/>
+  <xsd:simpleType name="PetId"><xsd:restriction base="xsd:long"/></xsd:simpleType>
+</xsd:schema>`
+
+const descriptionWSDL = `<?xml version="1.0" encoding="utf-8"?>
+<description xmlns="http://www.w3.org/ns/wsdl" xmlns:wsoap="http://www.w3.org/ns/wsdl/soap" xmlns:tns="urn:pets" targetNamespace="urn:pets">
+  <documentation>Pets</documentation>
+  <include location="common.wsdl"/>
+  <interface name="PetInterface">
+    <operation name="GetPet">
+      <input element="tns:GetPet"/>
+      <output element="tns:Pet"/>
+    </operation>
+  </interface>
+  <binding name="PetBinding" interface="tns:PetInterface" type="http://www.w3.org/ns/wsdl/soap" wsoap:version="1.1">
+    <operation ref="tns:GetPet" wsoap:action="urn:GetPet"/>
+  </binding>
+  <service name="Pets" interface="tns:PetInterface">
+    <endpoint name="PetEndpoint" binding="tns:PetBinding" address="https://pets.example.com/soap"/>
+  </service>
+</description>`
+
+func TestUnmarshal(t *testing.T) {
+	def, err := Unmarshal([]byte(bindingsWSDL))
+	assert.Nil(t, err)
+	assert.Equal(t, WSDL11, def.Version)
+	assert.Len(t, def.Bindings, 2)
+	assert.Equal(t, SOAP11, def.GetBinding("tns:PetSoap").SOAPVersion)
+	assert.Equal(t, SOAP12, def.GetBinding("PetSoap12").SOAPVersion)
+	assert.Nil(t, def.GetBinding("tns:Unknown"))
+
+	def, err = Unmarshal([]byte(rootWSDL))
+	assert.Nil(t, err)
+	assert.Equal(t, "Pets", def.Doc)
+	assert.Len(t, def.Services, 2)
+	assert.Equal(t, "PetAdmin", def.Services[1].Name)
+	assert.Equal(t, "https://pets.example.com/soap", def.Services[1].Ports[0].Address.Location)
+	// the deprecated fields have the first entries
+	assert.Equal(t, *def.Services[0], def.Service)
+	assert.Equal(t, *def.Schemas[0], def.Schema)
+}
+
+func TestUnmarshalDescription(t *testing.T) {
+	def, err := Unmarshal([]byte(descriptionWSDL))
+	assert.Nil(t, err)
+	assert.Equal(t, WSDL20, def.Version)
+	assert.Equal(t, "urn:pets", def.TargetNamespace)
+	assert.Equal(t, "Pets", def.Doc)
+	assert.Len(t, def.Imports, 1)
+	assert.Equal(t, "common.wsdl", def.Imports[0].Location)
+
+	pt := def.GetPortType("tns:PetInterface")
+	assert.NotNil(t, pt)
+	assert.Equal(t, "GetPet", pt.Operations[0].Name)
+	assert.Equal(t, "tns:Pet", pt.Operations[0].Output.Message)
+
+	binding := def.GetBinding("tns:PetBinding")
+	assert.NotNil(t, binding)
+	assert.Equal(t, "tns:PetInterface", binding.Type)
+	assert.Equal(t, SOAP11, binding.SOAPVersion)
+	assert.Equal(t, "GetPet", binding.Operations[0].Name)
+	assert.Equal(t, "urn:GetPet", binding.Operations[0].Operation.Action)
+
+	assert.Len(t, def.Services, 1)
+	assert.Equal(t, "tns:PetBinding", def.Services[0].Ports[0].Binding)
+	assert.Equal(t, "https://pets.example.com/soap", def.Services[0].Ports[0].Address.Location)
+	assert.Equal(t, "Pets", def.Service.Name)
+	assert.Equal(t, "PetBinding", def.Binding.Name)
+}
+
+func TestResolveImports(t *testing.T) {
+	tests := map[string]struct {
+		bundle         map[string][]byte
+		expectErr      bool
+		expectBindings int
+		expectSchemas  int
+		expectServices int
+	}{
+		"resolves wsdl and schema imports, with cycles": {
+			bundle: map[string][]byte{
+				"root.wsdl":            []byte(rootWSDL),
+				"./wsdl/bindings.wsdl": []byte(bindingsWSDL),
+				"xsd/pet.xsd":          []byte(petXSD),
+				"xsd/types.xsd":        []byte(typesXSD),
+			},
+			expectBindings: 2,
+			expectSchemas:  3,
+			expectServices: 2,
+		},
+		"missing import": {
+			bundle: map[string][]byte{
+				"wsdl/bindings.wsdl": []byte(bindingsWSDL),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			def, err := Unmarshal([]byte(rootWSDL))
+			assert.Nil(t, err)
+
+			err = ResolveImports(def, tc.bundle)
+			if tc.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, def.Bindings, tc.expectBindings)
+			assert.Len(t, def.Schemas, tc.expectSchemas)
+			assert.Len(t, def.Services, tc.expectServices)
+			// the root document has no bindings, the first is imported
+			assert.Equal(t, *def.Bindings[0], def.Binding)
+		})
+	}
+}
+
+func TestResolveImportsSkipsRemoteLocations(t *testing.T) {
+	def, err := Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <import location="https://example.com/remote.wsdl"/>
+</definitions>`))
+	assert.Nil(t, err)
+	assert.Nil(t, ResolveImports(def, map[string][]byte{"other.wsdl": []byte(bindingsWSDL)}))
+	assert.Empty(t, def.Bindings)
+}